@@ -0,0 +1,245 @@
+// Package client is a typed Go client for the lang portal API. It is
+// maintained alongside the handlers and shares their models, so request and
+// response types cannot drift from the server.
+//
+//	c, err := client.New("http://localhost:8080", client.WithLearner("learner-1"))
+//	words := c.Words(ctx, client.WordListOptions{GroupID: 1})
+//	for words.Next() {
+//		fmt.Println(words.Value().English)
+//	}
+//	if err := words.Err(); err != nil { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header names shared with the server
+const (
+	learnerHeader        = "X-User-ID"
+	requestIDHeader      = "X-Request-ID"
+	idempotencyKeyHeader = "Idempotency-Key"
+	replayedHeader       = "Idempotent-Replayed"
+)
+
+// Retry defaults
+const (
+	DefaultMaxRetries = 3
+	DefaultRetryDelay = 200 * time.Millisecond
+	maxRetryDelay     = 10 * time.Second
+)
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	learner    string
+	userAgent  string
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of a client with a 30
+// second timeout
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithLearner sends requests on behalf of a learner
+func WithLearner(id string) Option {
+	return func(c *Client) { c.learner = id }
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithRetries sets how many times a failed request is retried and the
+// delay before the first retry, which doubles with each attempt. Zero
+// retries disables retrying.
+func WithRetries(maxRetries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = delay
+	}
+}
+
+// New returns a client for the API at baseURL, such as
+// "http://localhost:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing base URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("base URL must be an http or https URL")
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "lang-portal-client",
+		maxRetries: DefaultMaxRetries,
+		retryDelay: DefaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// ForLearner returns a copy of the client acting on behalf of another
+// learner, for services that call the API for many learners
+func (c *Client) ForLearner(id string) *Client {
+	copied := *c
+	copied.learner = id
+	return &copied
+}
+
+// request describes one API call
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	header http.Header
+}
+
+// do sends req, retrying transient failures, and decodes a successful
+// response into out when out is not nil
+func (c *Client) do(ctx context.Context, req request, out any) (http.Header, error) {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("error encoding request: %v", err)
+		}
+	}
+
+	u := *c.baseURL
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req, u.String(), body)
+		if err == nil && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			if out != nil && resp.StatusCode != http.StatusNoContent {
+				if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+					return nil, fmt.Errorf("error decoding response: %v", err)
+				}
+			}
+			return resp.Header, nil
+		}
+
+		var retryAfter time.Duration
+		if err == nil {
+			err = responseError(resp)
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+		if ctx.Err() != nil || attempt >= c.maxRetries || !retryable(req, err) {
+			return nil, err
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter > delay {
+			delay = min(retryAfter, maxRetryDelay)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// send makes one attempt at req
+func (c *Client) send(ctx context.Context, req request, u string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.learner != "" {
+		httpReq.Header.Set(learnerHeader, c.learner)
+	}
+	return c.httpClient.Do(httpReq)
+}
+
+// retryable reports whether req may be sent again after err. Only requests
+// the server treats as idempotent are retried: reads, PUTs, DELETEs and
+// requests carrying an idempotency key.
+func retryable(req request, err error) bool {
+	switch req.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		if req.header.Get(idempotencyKeyHeader) == "" {
+			return false
+		}
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// Transport errors: the connection failed or was reset
+	return true
+}
+
+// backoff returns the delay before retry attempt+1: exponential with full
+// jitter, so clients retrying together spread out
+func (c *Client) backoff(attempt int) time.Duration {
+	limit := c.retryDelay << attempt
+	if limit <= 0 || limit > maxRetryDelay {
+		limit = maxRetryDelay
+	}
+	return time.Duration(rand.Int63n(int64(limit)) + 1)
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+func id(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
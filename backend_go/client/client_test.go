@@ -0,0 +1,133 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/client"
+)
+
+// newTestClient returns a client for a test server running handler that
+// retries up to three times after a millisecond
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...client.Option) *client.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	opts = append([]client.Option{client.WithRetries(3, time.Millisecond)}, opts...)
+	c, err := client.New(server.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// failing answers the first len(statuses) requests with those statuses and
+// later ones with a group, counting every request
+func failing(calls *atomic.Int32, header http.Header, statuses ...int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			for name, values := range header {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(statuses[n-1])
+			w.Write([]byte(`{"error":"try again"}`))
+			return
+		}
+		w.Write([]byte(`{"id":1,"name":"Basics"}`))
+	}
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, failing(&calls, nil, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusTooManyRequests))
+
+	group, err := c.GetGroup(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetGroup: %v", err)
+	}
+	if group.Name != "Basics" || calls.Load() != 4 {
+		t.Errorf("got %+v after %d requests, want Basics after 4", group, calls.Load())
+	}
+}
+
+func TestRetryAfterIsHonoured(t *testing.T) {
+	var calls atomic.Int32
+	header := http.Header{"Retry-After": {"1"}}
+	c := newTestClient(t, failing(&calls, header, http.StatusTooManyRequests))
+
+	began := time.Now()
+	if _, err := c.GetGroup(context.Background(), 1); err != nil {
+		t.Fatalf("GetGroup: %v", err)
+	}
+	if elapsed := time.Since(began); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("%d requests, want 2", calls.Load())
+	}
+}
+
+func TestRetriesGiveUp(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, failing(&calls, nil, 503, 503, 503, 503, 503))
+
+	_, err := c.GetGroup(context.Background(), 1)
+	if !errors.Is(err, client.ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("%d requests, want 4", calls.Load())
+	}
+}
+
+func TestRetriesOnlyIdempotentRequests(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		call  func(c *client.Client) error
+		want  int32
+		codes []int
+	}{
+		{
+			name: "client error",
+			call: func(c *client.Client) error {
+				_, err := c.GetGroup(context.Background(), 1)
+				return err
+			},
+			want:  1,
+			codes: []int{http.StatusBadRequest},
+		},
+		{
+			name: "post",
+			call: func(c *client.Client) error {
+				_, err := c.CreateGroup(context.Background(), client.Group{Name: "Basics"})
+				return err
+			},
+			want:  1,
+			codes: []int{http.StatusServiceUnavailable},
+		},
+		{
+			name: "post with idempotency key",
+			call: func(c *client.Client) error {
+				_, _, err := c.SubmitReviews(context.Background(), 1, "batch-1", nil)
+				return err
+			},
+			want:  2,
+			codes: []int{http.StatusServiceUnavailable},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			c := newTestClient(t, failing(&calls, nil, tc.codes...))
+			tc.call(c)
+			if calls.Load() != tc.want {
+				t.Errorf("%d requests, want %d", calls.Load(), tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// LastStudySession returns the most recent study session; the error
+// matches ErrNotFound when there is none yet
+func (c *Client) LastStudySession(ctx context.Context) (*StudySession, error) {
+	var session StudySession
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/dashboard/last-session"}, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// StudyProgress returns how much of the vocabulary has been studied and
+// mastered, broken down per group when byGroup is set
+func (c *Client) StudyProgress(ctx context.Context, byGroup bool) (*StudyProgress, error) {
+	q := url.Values{}
+	if byGroup {
+		q.Set("by_group", "true")
+	}
+	var progress StudyProgress
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/dashboard/study-progress", query: q}, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// QuickStats returns the dashboard's headline numbers
+func (c *Client) QuickStats(ctx context.Context) (*DashboardStats, error) {
+	var stats DashboardStats
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/dashboard/quick-stats"}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
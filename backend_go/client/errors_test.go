@@ -0,0 +1,88 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/client"
+)
+
+func TestErrorResponses(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		status    int
+		body      string
+		header    string
+		sentinel  error
+		message   string
+		requestID string
+	}{
+		{
+			name:      "json body",
+			status:    http.StatusNotFound,
+			body:      `{"error":"word not found","request_id":"req-body"}`,
+			header:    "req-header",
+			sentinel:  client.ErrNotFound,
+			message:   "word not found",
+			requestID: "req-body",
+		},
+		{
+			name:      "request id header",
+			status:    http.StatusConflict,
+			body:      `{"error":"word is in use"}`,
+			header:    "req-header",
+			sentinel:  client.ErrConflict,
+			message:   "word is in use",
+			requestID: "req-header",
+		},
+		{
+			name:     "bad request",
+			status:   http.StatusBadRequest,
+			body:     `{"error":"invalid word id"}`,
+			sentinel: client.ErrBadRequest,
+			message:  "invalid word id",
+		},
+		{
+			name:     "unprocessable",
+			status:   http.StatusUnprocessableEntity,
+			body:     `{"error":"answer is empty"}`,
+			sentinel: client.ErrUnprocessable,
+			message:  "answer is empty",
+		},
+		{
+			name:    "plain text body",
+			status:  http.StatusInternalServerError,
+			body:    "upstream exploded",
+			message: "Internal Server Error",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tc.header != "" {
+					w.Header().Set("X-Request-ID", tc.header)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}, client.WithRetries(0, 0))
+
+			_, err := c.GetWord(context.Background(), 1)
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v (%T), want *client.Error", err, err)
+			}
+			if apiErr.StatusCode != tc.status || apiErr.Message != tc.message || apiErr.RequestID != tc.requestID {
+				t.Errorf("error = %+v, want status %d, message %q, request %q", apiErr, tc.status, tc.message, tc.requestID)
+			}
+			if tc.sentinel != nil && !errors.Is(err, tc.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tc.sentinel)
+			}
+			for _, other := range []error{client.ErrBadRequest, client.ErrNotFound, client.ErrConflict, client.ErrUnprocessable, client.ErrUnavailable} {
+				if other != tc.sentinel && errors.Is(err, other) {
+					t.Errorf("errors.Is(%v, %v) = true", err, other)
+				}
+			}
+		})
+	}
+}
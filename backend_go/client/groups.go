@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListGroups returns every group
+func (c *Client) ListGroups(ctx context.Context) ([]Group, error) {
+	var groups []Group
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/groups"}, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetGroup returns a group
+func (c *Client) GetGroup(ctx context.Context, groupID int64) (*Group, error) {
+	var group Group
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/groups/" + id(groupID)}, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// CreateGroup creates a group and returns it with its ID
+func (c *Client) CreateGroup(ctx context.Context, group Group) (*Group, error) {
+	var created Group
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/groups", body: group}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateGroup replaces a group's name and description
+func (c *Client) UpdateGroup(ctx context.Context, group Group) (*Group, error) {
+	var updated Group
+	if _, err := c.do(ctx, request{method: http.MethodPut, path: "/api/groups/" + id(group.ID), body: group}, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteGroup moves a group to the trash
+func (c *Client) DeleteGroup(ctx context.Context, groupID int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/api/groups/" + id(groupID)}, nil)
+	return err
+}
+
+// RestoreGroup brings a group back from the trash
+func (c *Client) RestoreGroup(ctx context.Context, groupID int64) (*Group, error) {
+	var group Group
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/groups/" + id(groupID) + "/restore"}, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// AddWordToGroup adds a word to a group
+func (c *Client) AddWordToGroup(ctx context.Context, groupID, wordID int64) error {
+	body := struct {
+		WordID int64 `json:"word_id"`
+	}{wordID}
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/api/groups/" + id(groupID) + "/words", body: body}, nil)
+	return err
+}
+
+// RemoveWordFromGroup removes a word from a group
+func (c *Client) RemoveWordFromGroup(ctx context.Context, groupID, wordID int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/api/groups/" + id(groupID) + "/words/" + id(wordID)}, nil)
+	return err
+}
+
+// GroupWords iterates over the words of a group
+func (c *Client) GroupWords(ctx context.Context, groupID int64) *Iterator[Word] {
+	return c.Words(ctx, WordListOptions{GroupID: groupID, PageSize: 100})
+}
+
+// GroupStudySessions returns the study sessions of a group
+func (c *Client) GroupStudySessions(ctx context.Context, groupID int64) ([]StudySession, error) {
+	var sessions []StudySession
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/groups/" + id(groupID) + "/study-sessions"}, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// GroupStats returns learning progress for a group with a daily trend over
+// the last days days; zero uses the server's default of 30
+func (c *Client) GroupStats(ctx context.Context, groupID int64, days int) (*GroupStats, error) {
+	q := url.Values{}
+	if days > 0 {
+		q.Set("days", strconv.Itoa(days))
+	}
+	var stats GroupStats
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/groups/" + id(groupID) + "/stats", query: q}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,59 @@
+package client
+
+import "context"
+
+// Iterator walks a paginated list, fetching pages as it goes
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, page int) ([]T, bool, error)
+	page  int
+	items []T
+	index int
+	more  bool
+	value T
+	err   error
+}
+
+// newIterator returns an iterator over the pages returned by fetch, which
+// reports whether further pages follow
+func newIterator[T any](ctx context.Context, fetch func(ctx context.Context, page int) ([]T, bool, error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, more: true}
+}
+
+// Next advances to the next item, fetching the next page when needed. It
+// returns false at the end of the list or on error; check Err afterwards.
+func (it *Iterator[T]) Next() bool {
+	for it.index >= len(it.items) {
+		if !it.more || it.err != nil {
+			return false
+		}
+		it.page++
+		it.items, it.more, it.err = it.fetch(it.ctx, it.page)
+		it.index = 0
+		if it.err != nil {
+			return false
+		}
+	}
+	it.value = it.items[it.index]
+	it.index++
+	return true
+}
+
+// Value returns the current item
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All drains the iterator into a slice
+func (it *Iterator[T]) All() ([]T, error) {
+	var all []T
+	for it.Next() {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
@@ -0,0 +1,104 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/client"
+)
+
+// wordPages serves total words in pages of the requested size, failing
+// with 404 from page failFrom on when it is set
+func wordPages(t *testing.T, total, failFrom int, pages *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		*pages = append(*pages, q.Get("page"))
+		page, _ := strconv.Atoi(q.Get("page"))
+		size, _ := strconv.Atoi(q.Get("page_size"))
+		if failFrom > 0 && page >= failFrom {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"group not found"}`))
+			return
+		}
+		if q.Get("group_id") != "7" || r.Header.Get("X-User-ID") != "learner-1" {
+			t.Errorf("request %s with learner %q", r.URL, r.Header.Get("X-User-ID"))
+		}
+
+		words := []client.Word{}
+		for id := (page-1)*size + 1; id <= min(page*size, total); id++ {
+			words = append(words, client.Word{ID: int64(id), English: fmt.Sprint("word ", id)})
+		}
+		json.NewEncoder(w).Encode(client.WordsPage{
+			Words:      words,
+			Pagination: client.Pagination{Total: total, Page: page, PageSize: size},
+		})
+	}
+}
+
+func ids(words []client.Word) []int64 {
+	var out []int64
+	for _, word := range words {
+		out = append(out, word.ID)
+	}
+	return out
+}
+
+func TestWordsIteratesAcrossPages(t *testing.T) {
+	var pages []string
+	c := newTestClient(t, wordPages(t, 5, 0, &pages), client.WithLearner("learner-1"))
+
+	words, err := c.Words(context.Background(), client.WordListOptions{GroupID: 7, PageSize: 2}).All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if fmt.Sprint(ids(words)) != "[1 2 3 4 5]" || fmt.Sprint(pages) != "[1 2 3]" {
+		t.Errorf("got words %v from pages %v, want [1 2 3 4 5] from [1 2 3]", ids(words), pages)
+	}
+
+	pages = nil
+	words, err = c.Words(context.Background(), client.WordListOptions{GroupID: 7, PageSize: 2, Page: 2}).All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if fmt.Sprint(ids(words)) != "[3 4 5]" || fmt.Sprint(pages) != "[2 3]" {
+		t.Errorf("from page 2 got words %v from pages %v, want [3 4 5] from [2 3]", ids(words), pages)
+	}
+}
+
+func TestWordsStopsOnExactLastPage(t *testing.T) {
+	var pages []string
+	c := newTestClient(t, wordPages(t, 4, 0, &pages), client.WithLearner("learner-1"))
+
+	words, err := c.Words(context.Background(), client.WordListOptions{GroupID: 7, PageSize: 2}).All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(words) != 4 || fmt.Sprint(pages) != "[1 2]" {
+		t.Errorf("got %d words from pages %v, want 4 from [1 2]", len(words), pages)
+	}
+}
+
+func TestWordsStopsOnError(t *testing.T) {
+	var pages []string
+	c := newTestClient(t, wordPages(t, 5, 2, &pages), client.WithLearner("learner-1"))
+
+	it := c.Words(context.Background(), client.WordListOptions{GroupID: 7, PageSize: 2})
+	var got []client.Word
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	if !errors.Is(it.Err(), client.ErrNotFound) {
+		t.Errorf("Err = %v, want ErrNotFound", it.Err())
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d words before the error, want 2", len(got))
+	}
+	if it.Next() {
+		t.Error("Next succeeded after an error")
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// ListStudyActivities returns every study activity
+func (c *Client) ListStudyActivities(ctx context.Context) ([]StudyActivity, error) {
+	var activities []StudyActivity
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/study-activities"}, &activities); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// GetStudyActivity returns a study activity
+func (c *Client) GetStudyActivity(ctx context.Context, activityID int64) (*StudyActivity, error) {
+	var activity StudyActivity
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/study-activities/" + id(activityID)}, &activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// CreateStudyActivity creates a study activity for a group
+func (c *Client) CreateStudyActivity(ctx context.Context, groupID int64) (*StudyActivity, error) {
+	body := struct {
+		GroupID int64 `json:"group_id"`
+	}{groupID}
+	var activity StudyActivity
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/study-activities", body: body}, &activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// StudyActivitySessions returns the study sessions of an activity
+func (c *Client) StudyActivitySessions(ctx context.Context, activityID int64) ([]StudySession, error) {
+	var sessions []StudySession
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/study-activities/" + id(activityID) + "/study-sessions"}, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// StartStudySession starts a study session of an activity for a group
+func (c *Client) StartStudySession(ctx context.Context, activityID, groupID int64) (*StudySession, error) {
+	body := struct {
+		GroupID int64 `json:"group_id"`
+	}{groupID}
+	var session StudySession
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/study-activities/" + id(activityID) + "/study-sessions", body: body}, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FinishStudySession marks a study session finished
+func (c *Client) FinishStudySession(ctx context.Context, sessionID int64) (*StudySession, error) {
+	var session StudySession
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/study-sessions/" + id(sessionID) + "/finish"}, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// SubmitReviews records a batch of up to 500 reviews for a session.
+// Batches sent with the same idempotency key are recorded once, so a
+// non-empty key also lets the client retry the batch safely; replayed
+// reports whether the server returned the stored result of an earlier
+// submission.
+func (c *Client) SubmitReviews(ctx context.Context, sessionID int64, idempotencyKey string, items []WordReviewItem) (result *ReviewBatchResult, replayed bool, err error) {
+	req := request{
+		method: http.MethodPost,
+		path:   "/api/study-sessions/" + id(sessionID) + "/reviews:batch",
+		body: struct {
+			Items []WordReviewItem `json:"items"`
+		}{items},
+		header: http.Header{},
+	}
+	if idempotencyKey != "" {
+		req.header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+
+	result = &ReviewBatchResult{}
+	header, err := c.do(ctx, req, result)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, header.Get(replayedHeader) == "true", nil
+}
+
+// CreateReview records one review
+func (c *Client) CreateReview(ctx context.Context, review WordReviewItem) (*WordReviewItem, error) {
+	var created WordReviewItem
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/reviews", body: review}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GradeReview grades a typed answer and records it as a review
+func (c *Client) GradeReview(ctx context.Context, req GradeRequest) (*GradedReview, error) {
+	var graded GradedReview
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/reviews/grade", body: req}, &graded); err != nil {
+		return nil, err
+	}
+	return &graded, nil
+}
+
+// SessionReviews returns the reviews recorded in a study session
+func (c *Client) SessionReviews(ctx context.Context, sessionID int64) ([]WordReviewItem, error) {
+	var reviews []WordReviewItem
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/reviews/session/" + id(sessionID)}, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// SessionReviewAnalytics summarises the reviews of a study session
+func (c *Client) SessionReviewAnalytics(ctx context.Context, sessionID int64) (*ReviewAnalytics, error) {
+	var analytics ReviewAnalytics
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/reviews/session/" + id(sessionID) + "/analytics"}, &analytics); err != nil {
+		return nil, err
+	}
+	return &analytics, nil
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/grading"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// The API's models, aliased so code outside this module can name them
+type (
+	Word              = models.Word
+	WordsPage         = models.WordsPage
+	Pagination        = models.Pagination
+	WordStats         = models.WordStats
+	Group             = models.Group
+	GroupStats        = models.GroupStats
+	StudyActivity     = models.StudyActivity
+	StudySession      = models.StudySession
+	WordReviewItem    = models.WordReviewItem
+	ReviewBatchResult = models.ReviewBatchResult
+	ReviewAnalytics   = models.ReviewAnalytics
+	DashboardStats    = models.DashboardStats
+	StudyProgress     = models.StudyProgress
+	GradeResult       = grading.Result
+)
+
+// GradeRequest is a typed answer for the server to grade
+type GradeRequest struct {
+	WordID         int64  `json:"word_id"`
+	StudySessionID int64  `json:"study_session_id"`
+	Answer         string `json:"answer"`
+	// Direction selects the expected answer (ar-en, en-ar or romaji-ar);
+	// when empty the answer is compared with every form of the word
+	Direction string `json:"direction,omitempty"`
+	// MaxEdits tightens the server's length-based edit-distance tolerance
+	MaxEdits   *int `json:"max_edits,omitempty"`
+	ResponseMS *int `json:"response_ms,omitempty"`
+	HintsUsed  int  `json:"hints_used"`
+	Confidence *int `json:"confidence,omitempty"`
+}
+
+// GradedReview is a graded answer and the review it was recorded as
+type GradedReview struct {
+	GradeResult
+	Expected []string       `json:"expected"`
+	Review   WordReviewItem `json:"review"`
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// WordListOptions filters and pages the word list
+type WordListOptions struct {
+	GroupID int64
+	Search  string
+	// Page starts at 1
+	Page int
+	// PageSize is at most 100; the server defaults to 20
+	PageSize int
+}
+
+func (o WordListOptions) query() url.Values {
+	q := url.Values{}
+	if o.GroupID != 0 {
+		q.Set("group_id", id(o.GroupID))
+	}
+	if o.Search != "" {
+		q.Set("search", o.Search)
+	}
+	if o.Page > 0 {
+		q.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	return q
+}
+
+// ListWords returns one page of words
+func (c *Client) ListWords(ctx context.Context, opts WordListOptions) (*WordsPage, error) {
+	var page WordsPage
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/words", query: opts.query()}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Words iterates over every word matching opts, starting at opts.Page
+func (c *Client) Words(ctx context.Context, opts WordListOptions) *Iterator[Word] {
+	first := max(opts.Page, 1)
+	return newIterator(ctx, func(ctx context.Context, n int) ([]Word, bool, error) {
+		opts.Page = first + n - 1
+		page, err := c.ListWords(ctx, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		p := page.Pagination
+		return page.Words, len(page.Words) > 0 && p.Page*p.PageSize < p.Total, nil
+	})
+}
+
+// GetWord returns a word
+func (c *Client) GetWord(ctx context.Context, wordID int64) (*Word, error) {
+	var word Word
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/words/" + id(wordID)}, &word); err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// CreateWord creates a word and returns it with its ID
+func (c *Client) CreateWord(ctx context.Context, word Word) (*Word, error) {
+	var created Word
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/words", body: word}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateWord replaces a word's fields
+func (c *Client) UpdateWord(ctx context.Context, word Word) (*Word, error) {
+	var updated Word
+	if _, err := c.do(ctx, request{method: http.MethodPut, path: "/api/words/" + id(word.ID), body: word}, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteWord moves a word to the trash
+func (c *Client) DeleteWord(ctx context.Context, wordID int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/api/words/" + id(wordID)}, nil)
+	return err
+}
+
+// RestoreWord brings a word back from the trash
+func (c *Client) RestoreWord(ctx context.Context, wordID int64) (*Word, error) {
+	var word Word
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api/words/" + id(wordID) + "/restore"}, &word); err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// WordStats returns learning statistics for a word
+func (c *Client) WordStats(ctx context.Context, wordID int64) (*WordStats, error) {
+	var stats WordStats
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/words/" + id(wordID) + "/stats"}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// WordReviews returns a word's review history
+func (c *Client) WordReviews(ctx context.Context, wordID int64) ([]WordReviewItem, error) {
+	var reviews []WordReviewItem
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api/words/" + id(wordID) + "/reviews"}, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
@@ -3,15 +3,23 @@ package db
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// migrationsDir is where the versioned migration files live
+var migrationsDir = filepath.Join("internal", "db", "migrations")
+
 // InitDB initializes the database connection and runs migrations
 func InitDB(dbPath string) (*sql.DB, error) {
 	db, err := sql.Open("sqlite3", dbPath)
@@ -23,14 +31,9 @@ func InitDB(dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("error connecting to the database: %v", err)
 	}
 
-	// Read and execute migration SQL
-	migrationSQL, err := os.ReadFile(filepath.Join("internal", "db", "migrations", "001_initial_schema.up.sql"))
-	if err != nil {
-		return nil, fmt.Errorf("error reading migration file: %v", err)
-	}
-
-	if _, err := db.Exec(string(migrationSQL)); err != nil {
-		return nil, fmt.Errorf("error executing migration: %v", err)
+	// Apply pending migrations
+	if err := runMigrations(db); err != nil {
+		return nil, err
 	}
 
 	// Load seed data
@@ -38,12 +41,110 @@ func InitDB(dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("error loading seed data: %v", err)
 	}
 
-	log.Println("Database initialized successfully")
+	slog.Info("database initialized")
 	return db, nil
 }
 
+// newMigrate creates a migrate instance bound to an open database.
+// The returned instance must not be closed, as that closes db as well.
+func newMigrate(db *sql.DB) (*migrate.Migrate, error) {
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("error creating migration driver: %v", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+filepath.ToSlash(migrationsDir), "sqlite3", driver)
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %v", err)
+	}
+
+	return m, nil
+}
+
+// runMigrations applies all pending up migrations
+func runMigrations(db *sql.DB) error {
+	m, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("error executing migration: %v", err)
+	}
+
+	return nil
+}
+
+// MigrationStatus describes the schema version of a database
+type MigrationStatus struct {
+	Version uint
+	Latest  uint
+	Dirty   bool
+}
+
+// GetMigrationStatus returns the applied and latest available migration versions
+func GetMigrationStatus(db *sql.DB) (*MigrationStatus, error) {
+	status := &MigrationStatus{}
+
+	err := db.QueryRow(`
+		SELECT version, dirty
+		FROM schema_migrations
+		LIMIT 1
+	`).Scan(&status.Version, &status.Dirty)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error querying migration version: %v", err)
+	}
+
+	src, err := source.Open("file://" + filepath.ToSlash(migrationsDir))
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %v", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	for err == nil {
+		status.Latest = version
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading migrations: %v", err)
+	}
+
+	return status, nil
+}
+
+// SeedStatus describes how much seed data is present
+type SeedStatus struct {
+	Words  int
+	Groups int
+}
+
+// GetSeedStatus counts the seeded words and groups
+func GetSeedStatus(db *sql.DB) (*SeedStatus, error) {
+	status := &SeedStatus{}
+	err := db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM words),
+			(SELECT COUNT(*) FROM groups)
+	`).Scan(&status.Words, &status.Groups)
+	if err != nil {
+		return nil, fmt.Errorf("error querying seed status: %v", err)
+	}
+
+	return status, nil
+}
+
 // loadSeedData loads initial data from JSON files
 func loadSeedData(db *sql.DB) error {
+	// Skip seeding when a previous run already loaded data
+	status, err := GetSeedStatus(db)
+	if err != nil {
+		return err
+	}
+	if status.Words > 0 || status.Groups > 0 {
+		return nil
+	}
+
 	// Load groups
 	groupData, err := os.ReadFile("data/groups.json")
 	if err != nil {
@@ -93,32 +194,22 @@ func loadSeedData(db *sql.DB) error {
 		}
 	}
 
-	log.Println("Seed data loaded successfully")
+	slog.Info("seed data loaded")
 	return nil
 }
 
-// ResetDB drops all tables and reruns migrations
+// ResetDB rolls back all migrations and reruns them
 func ResetDB(db *sql.DB) error {
-	// Drop all tables
-	_, err := db.Exec(`
-		DROP TABLE IF EXISTS word_review_items;
-		DROP TABLE IF EXISTS study_sessions;
-		DROP TABLE IF EXISTS study_activities;
-		DROP TABLE IF EXISTS words_groups;
-		DROP TABLE IF EXISTS words;
-		DROP TABLE IF EXISTS groups;
-	`)
+	m, err := newMigrate(db)
 	if err != nil {
-		return fmt.Errorf("error dropping tables: %v", err)
+		return err
 	}
 
-	// Read and execute migration SQL
-	migrationSQL, err := os.ReadFile(filepath.Join("internal", "db", "migrations", "001_initial_schema.up.sql"))
-	if err != nil {
-		return fmt.Errorf("error reading migration file: %v", err)
+	if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("error rolling back migrations: %v", err)
 	}
 
-	if _, err := db.Exec(string(migrationSQL)); err != nil {
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		return fmt.Errorf("error executing migration: %v", err)
 	}
 
@@ -127,6 +218,6 @@ func ResetDB(db *sql.DB) error {
 		return fmt.Errorf("error loading seed data: %v", err)
 	}
 
-	log.Println("Database reset successfully")
+	slog.Info("database reset")
 	return nil
 }
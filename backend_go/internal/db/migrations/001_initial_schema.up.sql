@@ -56,9 +56,9 @@ CREATE TABLE IF NOT EXISTS word_review_items (
 );
 
 -- Create indexes for better query performance
-CREATE INDEX idx_words_groups_word_id ON words_groups(word_id);
-CREATE INDEX idx_words_groups_group_id ON words_groups(group_id);
-CREATE INDEX idx_study_sessions_group_id ON study_sessions(group_id);
-CREATE INDEX idx_study_activities_group_id ON study_activities(group_id);
-CREATE INDEX idx_word_review_items_word_id ON word_review_items(word_id);
-CREATE INDEX idx_word_review_items_session_id ON word_review_items(study_session_id);
+CREATE INDEX IF NOT EXISTS idx_words_groups_word_id ON words_groups(word_id);
+CREATE INDEX IF NOT EXISTS idx_words_groups_group_id ON words_groups(group_id);
+CREATE INDEX IF NOT EXISTS idx_study_sessions_group_id ON study_sessions(group_id);
+CREATE INDEX IF NOT EXISTS idx_study_activities_group_id ON study_activities(group_id);
+CREATE INDEX IF NOT EXISTS idx_word_review_items_word_id ON word_review_items(word_id);
+CREATE INDEX IF NOT EXISTS idx_word_review_items_session_id ON word_review_items(study_session_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_groups_deleted_at;
+DROP INDEX IF EXISTS idx_words_deleted_at;
+
+ALTER TABLE groups DROP COLUMN deleted_at;
+ALTER TABLE words DROP COLUMN deleted_at;
@@ -0,0 +1,6 @@
+-- Soft delete words and groups so their history survives deletion
+ALTER TABLE words ADD COLUMN deleted_at DATETIME;
+ALTER TABLE groups ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_words_deleted_at ON words(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_groups_deleted_at ON groups(deleted_at);
@@ -0,0 +1,28 @@
+-- Create quizzes table
+CREATE TABLE IF NOT EXISTS quizzes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_id INTEGER,
+    study_session_id INTEGER,
+    direction TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE SET NULL,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id) ON DELETE SET NULL
+);
+
+-- Create quiz_questions table; options holds the offered word IDs as JSON
+CREATE TABLE IF NOT EXISTS quiz_questions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quiz_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    word_id INTEGER NOT NULL,
+    options JSON NOT NULL,
+    answer_word_id INTEGER,
+    word_review_item_id INTEGER,
+    answered_at DATETIME,
+    FOREIGN KEY (quiz_id) REFERENCES quizzes(id) ON DELETE CASCADE,
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+    FOREIGN KEY (word_review_item_id) REFERENCES word_review_items(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_quiz_id ON quiz_questions(quiz_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_word_id ON quiz_questions(word_id);
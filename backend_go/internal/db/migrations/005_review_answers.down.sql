@@ -0,0 +1,2 @@
+ALTER TABLE word_review_items DROP COLUMN score;
+ALTER TABLE word_review_items DROP COLUMN answer_text;
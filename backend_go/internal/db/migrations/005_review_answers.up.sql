@@ -0,0 +1,3 @@
+-- Store typed answers and their partial-credit score on reviews
+ALTER TABLE word_review_items ADD COLUMN answer_text TEXT;
+ALTER TABLE word_review_items ADD COLUMN score REAL;
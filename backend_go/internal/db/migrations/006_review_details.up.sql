@@ -0,0 +1,6 @@
+-- Capture how a review was answered for difficulty analysis
+ALTER TABLE word_review_items ADD COLUMN response_ms INTEGER;
+ALTER TABLE word_review_items ADD COLUMN direction TEXT;
+ALTER TABLE word_review_items ADD COLUMN mode TEXT;
+ALTER TABLE word_review_items ADD COLUMN hints_used INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE word_review_items ADD COLUMN confidence INTEGER;
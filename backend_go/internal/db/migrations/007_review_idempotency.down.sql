@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS idempotency_keys;
+DROP INDEX IF EXISTS idx_word_review_items_client_id;
+ALTER TABLE word_review_items DROP COLUMN client_id;
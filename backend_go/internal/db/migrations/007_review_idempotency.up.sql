@@ -0,0 +1,13 @@
+-- Client-generated IDs let offline clients replay reviews without duplicates
+ALTER TABLE word_review_items ADD COLUMN client_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_word_review_items_client_id
+    ON word_review_items(client_id) WHERE client_id IS NOT NULL;
+
+-- Responses to batch submissions, replayed when a request is retried
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key TEXT PRIMARY KEY,
+    study_session_id INTEGER NOT NULL,
+    request_hash TEXT NOT NULL,
+    response JSON NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,25 @@
+DROP TRIGGER IF EXISTS words_groups_sync_delete;
+DROP TRIGGER IF EXISTS words_groups_sync_update;
+DROP TRIGGER IF EXISTS words_groups_sync_insert;
+DROP INDEX IF EXISTS idx_words_groups_change_version;
+ALTER TABLE words_groups DROP COLUMN change_version;
+ALTER TABLE words_groups DROP COLUMN updated_at;
+
+DROP TRIGGER IF EXISTS groups_sync_delete;
+DROP TRIGGER IF EXISTS groups_sync_update;
+DROP TRIGGER IF EXISTS groups_sync_insert;
+DROP INDEX IF EXISTS idx_groups_change_version;
+ALTER TABLE groups DROP COLUMN change_version;
+ALTER TABLE groups DROP COLUMN updated_at;
+
+DROP TRIGGER IF EXISTS words_sync_delete;
+DROP TRIGGER IF EXISTS words_sync_update;
+DROP TRIGGER IF EXISTS words_sync_insert;
+DROP INDEX IF EXISTS idx_words_change_version;
+ALTER TABLE words DROP COLUMN change_version;
+ALTER TABLE words DROP COLUMN updated_at;
+
+DROP INDEX IF EXISTS idx_study_sessions_client_id;
+ALTER TABLE study_sessions DROP COLUMN client_id;
+DROP TABLE IF EXISTS sync_tombstones;
+DROP TABLE IF EXISTS sync_state;
@@ -0,0 +1,118 @@
+-- Offline sync: every content change takes the next value of a global
+-- counter, which clients use as their position in the change stream
+CREATE TABLE IF NOT EXISTS sync_state (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    version INTEGER NOT NULL
+);
+INSERT OR IGNORE INTO sync_state (id, version) VALUES (1, 1);
+
+-- Hard deletes leave a tombstone so clients can drop their copies
+CREATE TABLE IF NOT EXISTS sync_tombstones (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type TEXT NOT NULL,
+    entity_id INTEGER NOT NULL,
+    change_version INTEGER NOT NULL,
+    deleted_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_change_version ON sync_tombstones(change_version);
+
+-- Sessions created offline are identified by a client-generated ID
+ALTER TABLE study_sessions ADD COLUMN client_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_study_sessions_client_id
+    ON study_sessions(client_id) WHERE client_id IS NOT NULL;
+
+-- Track changes to words
+ALTER TABLE words ADD COLUMN updated_at DATETIME;
+ALTER TABLE words ADD COLUMN change_version INTEGER NOT NULL DEFAULT 0;
+UPDATE words SET updated_at = created_at, change_version = 1;
+CREATE INDEX IF NOT EXISTS idx_words_change_version ON words(change_version);
+
+CREATE TRIGGER IF NOT EXISTS words_sync_insert AFTER INSERT ON words
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    UPDATE words
+    SET change_version = (SELECT version FROM sync_state),
+        updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_sync_update AFTER UPDATE ON words
+WHEN NEW.change_version = OLD.change_version
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    UPDATE words
+    SET change_version = (SELECT version FROM sync_state),
+        updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_sync_delete AFTER DELETE ON words
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    INSERT INTO sync_tombstones (entity_type, entity_id, change_version)
+    VALUES ('word', OLD.id, (SELECT version FROM sync_state));
+END;
+
+-- Track changes to groups
+ALTER TABLE groups ADD COLUMN updated_at DATETIME;
+ALTER TABLE groups ADD COLUMN change_version INTEGER NOT NULL DEFAULT 0;
+UPDATE groups SET updated_at = created_at, change_version = 1;
+CREATE INDEX IF NOT EXISTS idx_groups_change_version ON groups(change_version);
+
+CREATE TRIGGER IF NOT EXISTS groups_sync_insert AFTER INSERT ON groups
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    UPDATE groups
+    SET change_version = (SELECT version FROM sync_state),
+        updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS groups_sync_update AFTER UPDATE ON groups
+WHEN NEW.change_version = OLD.change_version
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    UPDATE groups
+    SET change_version = (SELECT version FROM sync_state),
+        updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS groups_sync_delete AFTER DELETE ON groups
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    INSERT INTO sync_tombstones (entity_type, entity_id, change_version)
+    VALUES ('group', OLD.id, (SELECT version FROM sync_state));
+END;
+
+-- Track changes to words_groups
+ALTER TABLE words_groups ADD COLUMN updated_at DATETIME;
+ALTER TABLE words_groups ADD COLUMN change_version INTEGER NOT NULL DEFAULT 0;
+UPDATE words_groups SET updated_at = created_at, change_version = 1;
+CREATE INDEX IF NOT EXISTS idx_words_groups_change_version ON words_groups(change_version);
+
+CREATE TRIGGER IF NOT EXISTS words_groups_sync_insert AFTER INSERT ON words_groups
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    UPDATE words_groups
+    SET change_version = (SELECT version FROM sync_state),
+        updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_groups_sync_update AFTER UPDATE ON words_groups
+WHEN NEW.change_version = OLD.change_version
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    UPDATE words_groups
+    SET change_version = (SELECT version FROM sync_state),
+        updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_groups_sync_delete AFTER DELETE ON words_groups
+BEGIN
+    UPDATE sync_state SET version = version + 1;
+    INSERT INTO sync_tombstones (entity_type, entity_id, change_version)
+    VALUES ('word_group', OLD.id, (SELECT version FROM sync_state));
+END;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS group_settings;
+DROP TABLE IF EXISTS learner_settings;
+DROP INDEX IF EXISTS idx_word_schedules_due_at;
+DROP TABLE IF EXISTS word_schedules;
+DROP INDEX IF EXISTS idx_word_review_items_learner_id;
+ALTER TABLE word_review_items DROP COLUMN learner_id;
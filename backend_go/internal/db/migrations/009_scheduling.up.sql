@@ -0,0 +1,33 @@
+-- Reviews are attributed to the learner who made them (X-User-ID)
+ALTER TABLE word_review_items ADD COLUMN learner_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_word_review_items_learner_id ON word_review_items(learner_id);
+
+-- Spaced-repetition state of each word per learner and scheduler. Every
+-- scheduler is kept up to date so learners can switch without losing progress.
+CREATE TABLE IF NOT EXISTS word_schedules (
+    learner_id TEXT NOT NULL,
+    word_id INTEGER NOT NULL,
+    scheduler TEXT NOT NULL,
+    box INTEGER NOT NULL DEFAULT 0,
+    ease_factor REAL NOT NULL DEFAULT 0,
+    interval_days REAL NOT NULL DEFAULT 0,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    due_at DATETIME NOT NULL,
+    last_reviewed_at DATETIME NOT NULL,
+    PRIMARY KEY (learner_id, word_id, scheduler),
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_word_schedules_due_at ON word_schedules(learner_id, scheduler, due_at);
+
+-- Scheduler chosen by a learner, overriding the group's choice
+CREATE TABLE IF NOT EXISTS learner_settings (
+    learner_id TEXT PRIMARY KEY,
+    scheduler TEXT NOT NULL
+);
+
+-- Scheduler chosen for a group, e.g. by its teacher
+CREATE TABLE IF NOT EXISTS group_settings (
+    group_id INTEGER PRIMARY KEY,
+    scheduler TEXT NOT NULL,
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS learner_goals;
+DROP TABLE IF EXISTS learner_achievements;
+DROP INDEX IF EXISTS idx_xp_events_created_at;
+DROP TABLE IF EXISTS xp_events;
+ALTER TABLE study_sessions DROP COLUMN learner_id;
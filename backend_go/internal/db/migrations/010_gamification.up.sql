@@ -0,0 +1,32 @@
+-- Sessions are attributed to the learner who started them (X-User-ID)
+ALTER TABLE study_sessions ADD COLUMN learner_id TEXT NOT NULL DEFAULT '';
+
+-- XP ledger; each review, session or achievement pays out at most once
+CREATE TABLE IF NOT EXISTS xp_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    learner_id TEXT NOT NULL,
+    source TEXT NOT NULL,
+    source_id TEXT NOT NULL,
+    xp INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (learner_id, source, source_id)
+);
+CREATE INDEX IF NOT EXISTS idx_xp_events_created_at ON xp_events(created_at);
+
+-- Unlocked achievements; detail distinguishes repeatable ones such as
+-- each completed root family
+CREATE TABLE IF NOT EXISTS learner_achievements (
+    learner_id TEXT NOT NULL,
+    code TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    xp INTEGER NOT NULL,
+    unlocked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (learner_id, code, detail)
+);
+
+-- Daily goal targets; 0 disables a target
+CREATE TABLE IF NOT EXISTS learner_goals (
+    learner_id TEXT PRIMARY KEY,
+    daily_reviews INTEGER NOT NULL,
+    daily_minutes INTEGER NOT NULL
+);
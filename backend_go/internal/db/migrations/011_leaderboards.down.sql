@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_leaderboard_stats_board;
+DROP TABLE IF EXISTS leaderboard_stats;
+DROP TABLE IF EXISTS learner_word_progress;
+DROP TABLE IF EXISTS learner_profiles;
@@ -0,0 +1,36 @@
+-- Leaderboard display preferences
+CREATE TABLE IF NOT EXISTS learner_profiles (
+    learner_id TEXT PRIMARY KEY,
+    pseudonym TEXT NOT NULL DEFAULT '',
+    leaderboard_opt_out BOOLEAN NOT NULL DEFAULT 0
+);
+
+-- Running per-word tallies per learner, so each review can tell whether
+-- it made the word mastered or unmastered without rereading history
+CREATE TABLE IF NOT EXISTS learner_word_progress (
+    learner_id TEXT NOT NULL,
+    word_id INTEGER NOT NULL,
+    attempts INTEGER NOT NULL,
+    correct_count INTEGER NOT NULL,
+    current_streak INTEGER NOT NULL,
+    mastered BOOLEAN NOT NULL,
+    PRIMARY KEY (learner_id, word_id)
+);
+
+-- Leaderboard totals kept up to date by every review, session and
+-- achievement. group_id 0 covers all groups; period is 'all' or the
+-- Monday (UTC) starting a week.
+CREATE TABLE IF NOT EXISTS leaderboard_stats (
+    learner_id TEXT NOT NULL,
+    group_id INTEGER NOT NULL,
+    period TEXT NOT NULL,
+    xp INTEGER NOT NULL DEFAULT 0,
+    reviews INTEGER NOT NULL DEFAULT 0,
+    correct_count INTEGER NOT NULL DEFAULT 0,
+    words_mastered INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (learner_id, group_id, period)
+);
+CREATE INDEX IF NOT EXISTS idx_leaderboard_stats_board ON leaderboard_stats(group_id, period);
+
+-- Existing history is replayed into these tables at startup by
+-- BackfillLeaderboards, with the same mastery rule as new reviews
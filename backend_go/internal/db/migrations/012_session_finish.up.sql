@@ -0,0 +1,2 @@
+-- Sessions are finished explicitly, e.g. when a teacher ends an activity
+ALTER TABLE study_sessions ADD COLUMN finished_at DATETIME;
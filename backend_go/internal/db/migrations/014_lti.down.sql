@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_lti_launches_study_session_id;
+DROP INDEX IF EXISTS idx_lti_launches_score_status;
+DROP TABLE IF EXISTS lti_launches;
+DROP TABLE IF EXISTS lti_users;
+DROP TABLE IF EXISTS lti_login_states;
+DROP TABLE IF EXISTS lti_keys;
+DROP TABLE IF EXISTS lti_platforms;
@@ -0,0 +1,67 @@
+-- LMS platforms allowed to launch activities through LTI 1.3
+CREATE TABLE IF NOT EXISTS lti_platforms (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL DEFAULT '',
+    issuer TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    deployment_id TEXT NOT NULL DEFAULT '',
+    auth_login_url TEXT NOT NULL,
+    auth_token_url TEXT NOT NULL,
+    jwks_url TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (issuer, client_id)
+);
+
+-- The tool's signing key, generated on first start
+CREATE TABLE IF NOT EXISTS lti_keys (
+    kid TEXT PRIMARY KEY,
+    private_key TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Pending OIDC logins; each state is used by at most one launch
+CREATE TABLE IF NOT EXISTS lti_login_states (
+    state TEXT PRIMARY KEY,
+    nonce TEXT NOT NULL,
+    platform_id INTEGER NOT NULL,
+    expires_at DATETIME NOT NULL,
+    FOREIGN KEY (platform_id) REFERENCES lti_platforms(id) ON DELETE CASCADE
+);
+
+-- Platform users mapped to local learners
+CREATE TABLE IF NOT EXISTS lti_users (
+    platform_id INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    learner_id TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (platform_id, subject),
+    FOREIGN KEY (platform_id) REFERENCES lti_platforms(id) ON DELETE CASCADE
+);
+
+-- Validated launches and the scores owed for their study sessions
+CREATE TABLE IF NOT EXISTS lti_launches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    launch_key TEXT NOT NULL UNIQUE,
+    platform_id INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    learner_id TEXT NOT NULL,
+    message_type TEXT NOT NULL,
+    deployment_id TEXT NOT NULL DEFAULT '',
+    group_id INTEGER,
+    study_activity_id INTEGER,
+    study_session_id INTEGER,
+    lineitem_url TEXT NOT NULL DEFAULT '',
+    deep_link_return_url TEXT NOT NULL DEFAULT '',
+    deep_link_data TEXT NOT NULL DEFAULT '',
+    score_status TEXT NOT NULL DEFAULT '',
+    score_attempts INTEGER NOT NULL DEFAULT 0,
+    score_error TEXT,
+    score_posted_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (platform_id) REFERENCES lti_platforms(id) ON DELETE CASCADE,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_lti_launches_score_status ON lti_launches(score_status);
+CREATE INDEX IF NOT EXISTS idx_lti_launches_study_session_id ON lti_launches(study_session_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS learner_review_days;
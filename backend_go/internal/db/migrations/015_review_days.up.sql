@@ -0,0 +1,13 @@
+-- Days on which each learner reviewed words, kept up to date by every
+-- review so streaks are counted without rereading the review history
+CREATE TABLE IF NOT EXISTS learner_review_days (
+    learner_id TEXT NOT NULL,
+    day TEXT NOT NULL,
+    PRIMARY KEY (learner_id, day)
+);
+
+-- Backfill from existing history
+INSERT INTO learner_review_days (learner_id, day)
+SELECT DISTINCT learner_id, date(created_at)
+FROM word_review_items
+WHERE learner_id != '';
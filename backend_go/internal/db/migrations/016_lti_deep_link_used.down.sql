@@ -0,0 +1 @@
+ALTER TABLE lti_launches DROP COLUMN deep_link_used_at;
@@ -0,0 +1,3 @@
+-- When a deep linking request was answered, so its launch cannot be
+-- replayed to sign further responses
+ALTER TABLE lti_launches ADD COLUMN deep_link_used_at DATETIME;
@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_keys_global (
+    key TEXT PRIMARY KEY,
+    study_session_id INTEGER NOT NULL,
+    request_hash TEXT NOT NULL,
+    response JSON NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT OR IGNORE INTO idempotency_keys_global (key, study_session_id, request_hash, response, created_at)
+SELECT key, study_session_id, request_hash, response, created_at FROM idempotency_keys ORDER BY created_at;
+DROP TABLE idempotency_keys;
+ALTER TABLE idempotency_keys_global RENAME TO idempotency_keys;
@@ -0,0 +1,14 @@
+-- Scope idempotency keys to their study session, so one learner's key
+-- neither replays nor blocks another learner's batch
+CREATE TABLE idempotency_keys_scoped (
+    key TEXT NOT NULL,
+    study_session_id INTEGER NOT NULL,
+    request_hash TEXT NOT NULL,
+    response JSON NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (study_session_id, key)
+);
+INSERT INTO idempotency_keys_scoped (key, study_session_id, request_hash, response, created_at)
+SELECT key, study_session_id, request_hash, response, created_at FROM idempotency_keys;
+DROP TABLE idempotency_keys;
+ALTER TABLE idempotency_keys_scoped RENAME TO idempotency_keys;
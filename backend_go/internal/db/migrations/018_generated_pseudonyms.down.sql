@@ -0,0 +1,9 @@
+CREATE TABLE learner_profiles_chosen (
+    learner_id TEXT PRIMARY KEY,
+    pseudonym TEXT NOT NULL DEFAULT '',
+    leaderboard_opt_out BOOLEAN NOT NULL DEFAULT 0
+);
+INSERT INTO learner_profiles_chosen (learner_id, pseudonym, leaderboard_opt_out)
+SELECT learner_id, pseudonym, leaderboard_opt_out FROM learner_profiles;
+DROP TABLE learner_profiles;
+ALTER TABLE learner_profiles_chosen RENAME TO learner_profiles;
@@ -0,0 +1,14 @@
+-- Give every learner a random pseudonym shown on leaderboards until they
+-- choose one, so learner IDs are never published
+CREATE TABLE learner_profiles_generated (
+    learner_id TEXT PRIMARY KEY,
+    pseudonym TEXT NOT NULL DEFAULT '',
+    generated_pseudonym TEXT NOT NULL DEFAULT ('Learner ' || upper(hex(randomblob(3)))),
+    leaderboard_opt_out BOOLEAN NOT NULL DEFAULT 0
+);
+INSERT INTO learner_profiles_generated (learner_id, pseudonym, leaderboard_opt_out)
+SELECT learner_id, pseudonym, leaderboard_opt_out FROM learner_profiles;
+INSERT OR IGNORE INTO learner_profiles_generated (learner_id)
+SELECT DISTINCT learner_id FROM leaderboard_stats;
+DROP TABLE learner_profiles;
+ALTER TABLE learner_profiles_generated RENAME TO learner_profiles;
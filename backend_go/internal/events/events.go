@@ -0,0 +1,150 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	ReviewRecorded  = "review.recorded"
+	SessionStarted  = "session.started"
+	SessionFinished = "session.finished"
+	GroupMastered   = "group.mastered"
+)
+
+// historySize is how many recent events are kept for clients resuming a
+// stream with Last-Event-ID
+const historySize = 1000
+
+// subscriberBuffer is how many events may queue for a slow subscriber
+// before newer ones are dropped for it
+const subscriberBuffer = 256
+
+// Event is something that happened in a study session or to a learner
+type Event struct {
+	ID        int64       `json:"id"`
+	Type      string      `json:"type"`
+	SessionID int64       `json:"session_id"`
+	GroupID   int64       `json:"group_id"`
+	LearnerID string      `json:"learner_id,omitempty"`
+	Data      interface{} `json:"data"`
+	Time      time.Time   `json:"time"`
+}
+
+// Filter selects the events a subscriber receives
+type Filter func(Event) bool
+
+type subscriber struct {
+	ch     chan Event
+	filter Filter
+}
+
+// Bus fans events out to in-process subscribers
+type Bus struct {
+	mu          sync.Mutex
+	nextID      int64
+	history     []Event
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+// NewBus creates an empty bus
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*subscriber]struct{})}
+}
+
+// Publish numbers an event and delivers it to every matching subscriber
+// without blocking; a subscriber whose buffer is full misses the event
+func (b *Bus) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	b.nextID++
+	event.ID = b.nextID
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > historySize {
+		b.history = b.history[len(b.history)-historySize:]
+	}
+
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of matching events, starting with those
+// after lastID still held in history (0 for none), and a function that
+// ends the subscription. The channel is closed when the bus closes.
+func (b *Bus) Subscribe(filter Filter, lastID int64) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &subscriber{ch: make(chan Event, subscriberBuffer+historySize), filter: filter}
+	if b.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+
+	if lastID > 0 {
+		for _, event := range b.history {
+			if event.ID > lastID && (filter == nil || filter(event)) {
+				sub.ch <- event
+			}
+		}
+	}
+	b.subscribers[sub] = struct{}{}
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if _, ok := b.subscribers[sub]; ok {
+				delete(b.subscribers, sub)
+				close(sub.ch)
+			}
+		})
+	}
+}
+
+// Close ends every subscription so open streams finish, e.g. on shutdown
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+var defaultBus = NewBus()
+
+// Publish publishes an event on the process-wide bus
+func Publish(event Event) {
+	defaultBus.Publish(event)
+}
+
+// Subscribe subscribes to the process-wide bus
+func Subscribe(filter Filter, lastID int64) (<-chan Event, func()) {
+	return defaultBus.Subscribe(filter, lastID)
+}
+
+// Close closes the process-wide bus
+func Close() {
+	defaultBus.Close()
+}
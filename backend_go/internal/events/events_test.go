@@ -0,0 +1,155 @@
+package events
+
+import (
+	"slices"
+	"testing"
+)
+
+// drain returns the events already queued on ch without blocking, and
+// whether ch has been closed
+func drain(ch <-chan Event) ([]Event, bool) {
+	var got []Event
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return got, true
+			}
+			got = append(got, event)
+		default:
+			return got, false
+		}
+	}
+}
+
+// ids returns the IDs of events
+func ids(events []Event) []int64 {
+	out := make([]int64, len(events))
+	for i, event := range events {
+		out[i] = event.ID
+	}
+	return out
+}
+
+func TestPublishNumbersAndFilters(t *testing.T) {
+	b := NewBus()
+	ch, cancel := b.Subscribe(func(e Event) bool { return e.SessionID == 1 }, 0)
+	defer cancel()
+
+	b.Publish(Event{Type: SessionStarted, SessionID: 1})
+	b.Publish(Event{Type: SessionStarted, SessionID: 2})
+	b.Publish(Event{Type: ReviewRecorded, SessionID: 1})
+
+	got, _ := drain(ch)
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 3 || got[1].Type != ReviewRecorded {
+		t.Fatalf("received %+v, want events 1 and 3", got)
+	}
+	if got[0].Time.IsZero() {
+		t.Error("published event has no time")
+	}
+}
+
+func TestSubscribeReplaysHistory(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		lastID int64
+		want   []int64
+	}{
+		{"new subscriber", nil, 0, nil},
+		{"resumed", nil, 2, []int64{3, 4, 5}},
+		{"resumed with a filter", func(e Event) bool { return e.SessionID == 1 }, 2, []int64{3, 5}},
+		{"up to date", nil, 5, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBus()
+			for session := int64(1); session <= 5; session++ {
+				b.Publish(Event{Type: SessionStarted, SessionID: session % 2})
+			}
+
+			ch, cancel := b.Subscribe(tt.filter, tt.lastID)
+			defer cancel()
+			b.Publish(Event{Type: ReviewRecorded, SessionID: 1})
+
+			// Replayed events come first, followed by live ones
+			got, _ := drain(ch)
+			if want := append(tt.want, 6); !slices.Equal(ids(got), want) {
+				t.Errorf("received %v, want %v", ids(got), want)
+			}
+		})
+	}
+}
+
+func TestHistoryKeepsTheLatestEvents(t *testing.T) {
+	b := NewBus()
+	for i := 0; i < historySize+10; i++ {
+		b.Publish(Event{Type: ReviewRecorded})
+	}
+
+	ch, cancel := b.Subscribe(nil, 1)
+	defer cancel()
+	got, _ := drain(ch)
+	if len(got) != historySize || got[0].ID != 11 || got[len(got)-1].ID != historySize+10 {
+		t.Fatalf("replayed %d events from %d, want the last %d", len(got), got[0].ID, historySize)
+	}
+}
+
+func TestSlowSubscriberMissesEvents(t *testing.T) {
+	b := NewBus()
+	slow, cancelSlow := b.Subscribe(nil, 0)
+	defer cancelSlow()
+
+	capacity := subscriberBuffer + historySize
+	for i := 0; i < capacity+5; i++ {
+		b.Publish(Event{Type: ReviewRecorded})
+	}
+
+	// Publishing never blocks; the slow subscriber keeps what fit in its
+	// buffer and misses the rest
+	got, _ := drain(slow)
+	if len(got) != capacity || got[len(got)-1].ID != int64(capacity) {
+		t.Fatalf("slow subscriber received %d events up to %d, want the first %d", len(got), got[len(got)-1].ID, capacity)
+	}
+
+	// Once drained it receives new events again
+	b.Publish(Event{Type: ReviewRecorded})
+	if got, _ := drain(slow); len(got) != 1 || got[0].ID != int64(capacity+6) {
+		t.Errorf("after draining received %v, want event %d", ids(got), capacity+6)
+	}
+}
+
+func TestCancelSubscription(t *testing.T) {
+	b := NewBus()
+	ch, cancel := b.Subscribe(nil, 0)
+	cancel()
+	cancel()
+
+	b.Publish(Event{Type: ReviewRecorded})
+	if got, closed := drain(ch); len(got) != 0 || !closed {
+		t.Errorf("cancelled subscription received %v, closed %v", ids(got), closed)
+	}
+}
+
+func TestClose(t *testing.T) {
+	b := NewBus()
+	ch, cancel := b.Subscribe(nil, 0)
+	b.Publish(Event{Type: SessionStarted})
+	b.Close()
+	cancel()
+
+	// Queued events are still delivered before the channel closes
+	if got, closed := drain(ch); len(got) != 1 || !closed {
+		t.Errorf("after Close received %v, closed %v; want the queued event and a closed channel", ids(got), closed)
+	}
+
+	b.Publish(Event{Type: SessionFinished})
+	late, cancelLate := b.Subscribe(nil, 0)
+	defer cancelLate()
+	if got, closed := drain(late); len(got) != 0 || !closed {
+		t.Errorf("subscribing after Close received %v, closed %v; want a closed channel", ids(got), closed)
+	}
+	if b.nextID != 1 {
+		t.Errorf("an event was published after Close")
+	}
+}
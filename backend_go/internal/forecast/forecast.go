@@ -0,0 +1,149 @@
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// MinFitSamples is how many repeat reviews a learner needs before the
+// model is fitted to them; with fewer, the default curve is used
+const MinFitSamples = 20
+
+// maxSimulatedReviews bounds the reviews projected for a single word
+const maxSimulatedReviews = 1000
+
+// Fit replays a learner's reviews through the memory model and fits how
+// fast the learner forgets: a multiplier on every word's stability chosen
+// to best predict whether each repeat review was answered correctly. It
+// returns the fitted model and each word's memory state.
+func Fit(reviews []models.WordReviewItem) (models.ForecastModel, map[int64]Memory) {
+	sorted := append([]models.WordReviewItem(nil), reviews...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].WordID != sorted[j].WordID {
+			return sorted[i].WordID < sorted[j].WordID
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	type sample struct {
+		stability, days float64
+		correct         bool
+	}
+	var samples []sample
+
+	memories := make(map[int64]Memory)
+	for _, review := range sorted {
+		memory, seen := memories[review.WordID]
+		if !seen {
+			memories[review.WordID] = initialMemory(rating(review), review.CreatedAt)
+			continue
+		}
+
+		days := elapsedDays(memory.LastReviewedAt, review.CreatedAt)
+		samples = append(samples, sample{memory.Stability, days, review.IsCorrect})
+		memories[review.WordID] = memory.next(rating(review), Retrievability(memory.Stability, 1, days), review.CreatedAt)
+	}
+
+	model := models.ForecastModel{Multiplier: 1, Samples: len(samples)}
+	logLoss := func(multiplier float64) float64 {
+		var loss float64
+		for _, s := range samples {
+			p := math.Min(math.Max(Retrievability(s.stability, multiplier, s.days), 1e-6), 1-1e-6)
+			if s.correct {
+				loss -= math.Log(p)
+			} else {
+				loss -= math.Log(1 - p)
+			}
+		}
+		return loss / float64(len(samples))
+	}
+
+	if len(samples) == 0 {
+		return model, memories
+	}
+	model.LogLoss = logLoss(1)
+	if len(samples) < MinFitSamples {
+		return model, memories
+	}
+
+	// Multipliers from 1/8 to 8 in quarter powers of two
+	for step := -12; step <= 12; step++ {
+		multiplier := math.Pow(2, float64(step)/4)
+		if loss := logLoss(multiplier); loss < model.LogLoss {
+			model.Multiplier = multiplier
+			model.LogLoss = loss
+		}
+	}
+	model.Fitted = true
+	return model, memories
+}
+
+// Project counts the reviews that come due on each of the next days days,
+// assuming each word is reviewed when its predicted recall falls to
+// retention and answered correctly. Overdue words count towards today.
+func Project(memories map[int64]Memory, model models.ForecastModel, retention float64, days int, now time.Time) []models.ForecastDay {
+	today := now.UTC().Truncate(24 * time.Hour)
+	end := today.AddDate(0, 0, days)
+
+	forecast := make([]models.ForecastDay, days)
+	for i := range forecast {
+		forecast[i].Date = today.AddDate(0, 0, i).Format("2006-01-02")
+	}
+
+	for _, memory := range memories {
+		for i := 0; i < maxSimulatedReviews; i++ {
+			due := memory.LastReviewedAt.Add(time.Duration(interval(memory.Stability, model.Multiplier, retention) * 24 * float64(time.Hour)))
+			if !due.Before(end) {
+				break
+			}
+			if due.Before(now) {
+				due = now
+			}
+
+			forecast[int(due.Sub(today).Hours()/24)].Due++
+			memory = memory.next(ratingGood, retention, due)
+		}
+	}
+	return forecast
+}
+
+// GroupRetention estimates the share of a group's reviewed words the
+// learner can recall now and at the end of the forecast if they stop
+// reviewing
+func GroupRetention(group models.Group, wordIDs []int64, memories map[int64]Memory, model models.ForecastModel, now, horizon time.Time) models.GroupRetention {
+	retention := models.GroupRetention{GroupID: group.ID, GroupName: group.Name, WordCount: len(wordIDs)}
+
+	var current, later float64
+	for _, id := range wordIDs {
+		memory, ok := memories[id]
+		if !ok {
+			continue
+		}
+		retention.WordsStudied++
+		current += Retrievability(memory.Stability, model.Multiplier, elapsedDays(memory.LastReviewedAt, now))
+		later += Retrievability(memory.Stability, model.Multiplier, elapsedDays(memory.LastReviewedAt, horizon))
+	}
+
+	if retention.WordsStudied > 0 {
+		retention.Retention = current / float64(retention.WordsStudied)
+		retention.RetentionAtHorizon = later / float64(retention.WordsStudied)
+	}
+	return retention
+}
+
+// Summarize fills the totals and busiest day of a forecast
+func Summarize(forecast *models.Forecast) {
+	forecast.TotalDue = 0
+	forecast.PeakDue = 0
+	forecast.PeakDate = ""
+	for _, day := range forecast.Daily {
+		forecast.TotalDue += day.Due
+		if day.Due > forecast.PeakDue {
+			forecast.PeakDue = day.Due
+			forecast.PeakDate = day.Date
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// daysAgo returns the time n days before now
+func daysAgo(n float64) time.Time {
+	return now.Add(-time.Duration(n * 24 * float64(time.Hour)))
+}
+
+// reviewAt returns a review of word answered at a time
+func reviewAt(wordID int64, correct bool, at time.Time) models.WordReviewItem {
+	return models.WordReviewItem{WordID: wordID, IsCorrect: correct, CreatedAt: at}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRetrievabilityAtStability(t *testing.T) {
+	// Stability is defined as the days after which recall drops to 90%
+	for _, stability := range []float64{0.5, 1, 3.7145, 30, 365} {
+		for _, multiplier := range []float64{0.125, 1, 2} {
+			if r := Retrievability(stability, multiplier, stability*multiplier); !near(r, 0.9) {
+				t.Errorf("Retrievability(%v, %v, S) = %v, want 0.9", stability, multiplier, r)
+			}
+		}
+	}
+}
+
+func TestRetrievability(t *testing.T) {
+	if r := Retrievability(10, 1, 0); r != 1 {
+		t.Errorf("recall right after a review = %v, want 1", r)
+	}
+	if r := Retrievability(10, 1, -1); r != 1 {
+		t.Errorf("recall before a review = %v, want 1", r)
+	}
+	previous := 1.0
+	for days := 1.0; days <= 1000; days *= 2 {
+		r := Retrievability(10, 1, days)
+		if r >= previous || r <= 0 {
+			t.Fatalf("recall after %v days = %v, want below %v and above 0", days, r, previous)
+		}
+		previous = r
+	}
+	if slow, fast := Retrievability(10, 2, 20), Retrievability(10, 1, 20); slow <= fast {
+		t.Errorf("a larger multiplier forgets faster: %v <= %v", slow, fast)
+	}
+}
+
+func TestIntervalRoundTrip(t *testing.T) {
+	for _, stability := range []float64{0.5, 4, 90} {
+		for _, multiplier := range []float64{0.5, 1, 3} {
+			for _, retention := range []float64{0.7, 0.8, 0.9, 0.95} {
+				days := interval(stability, multiplier, retention)
+				if r := Retrievability(stability, multiplier, days); !near(r, retention) {
+					t.Errorf("Retrievability after interval(%v, %v, %v) = %v days is %v",
+						stability, multiplier, retention, days, r)
+				}
+			}
+			if days := interval(stability, multiplier, 0.9); !near(days, stability*multiplier) {
+				t.Errorf("interval(%v, %v, 0.9) = %v, want the scaled stability", stability, multiplier, days)
+			}
+		}
+	}
+}
+
+func TestFitWithoutRepeats(t *testing.T) {
+	model, memories := Fit(nil)
+	if model != (models.ForecastModel{Multiplier: 1}) || len(memories) != 0 {
+		t.Errorf("Fit(nil) = %+v, %v; want the default model", model, memories)
+	}
+
+	model, memories = Fit([]models.WordReviewItem{reviewAt(1, true, daysAgo(2)), reviewAt(2, false, daysAgo(1))})
+	if model != (models.ForecastModel{Multiplier: 1}) {
+		t.Errorf("model = %+v, want the default model", model)
+	}
+	if got := memories[1]; got.Stability != weights[ratingGood-1] || !got.LastReviewedAt.Equal(daysAgo(2)) {
+		t.Errorf("memory after a correct answer = %+v", got)
+	}
+	if got := memories[2]; got.Stability != weights[ratingAgain-1] || !got.LastReviewedAt.Equal(daysAgo(1)) {
+		t.Errorf("memory after a wrong answer = %+v", got)
+	}
+}
+
+func TestFit(t *testing.T) {
+	// repeats returns n words each answered correctly and then, gap days
+	// later, answered as given; the later review comes first to check
+	// that Fit orders reviews itself
+	repeats := func(n int, gap float64, correct bool) []models.WordReviewItem {
+		var reviews []models.WordReviewItem
+		for id := int64(1); id <= int64(n); id++ {
+			reviews = append(reviews, reviewAt(id, correct, daysAgo(0)), reviewAt(id, true, daysAgo(gap)))
+		}
+		return reviews
+	}
+
+	tests := []struct {
+		name    string
+		reviews []models.WordReviewItem
+		fitted  bool
+		check   func(multiplier float64) bool
+	}{
+		{"too few samples", repeats(MinFitSamples-1, 30, true), false, func(m float64) bool { return m == 1 }},
+		{"remembers longer than the default curve", repeats(MinFitSamples, 30, true), true, func(m float64) bool { return m > 1 }},
+		{"forgets faster than the default curve", repeats(MinFitSamples, 1, false), true, func(m float64) bool { return m < 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, memories := Fit(tt.reviews)
+			if model.Fitted != tt.fitted || !tt.check(model.Multiplier) || model.Samples != len(tt.reviews)/2 {
+				t.Errorf("model = %+v", model)
+			}
+			if model.LogLoss <= 0 || math.IsNaN(model.LogLoss) {
+				t.Errorf("log loss = %v, want positive", model.LogLoss)
+			}
+			if len(memories) != len(tt.reviews)/2 || !memories[1].LastReviewedAt.Equal(now) {
+				t.Errorf("memories = %d, word 1 last reviewed %v; want one per word, reviewed now", len(memories), memories[1].LastReviewedAt)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	model := models.ForecastModel{Multiplier: 1}
+
+	forecast := Project(map[int64]Memory{1: {Stability: 10, Difficulty: 5, LastReviewedAt: now}}, model, 0.9, 30, now)
+	if len(forecast) != 30 || forecast[0].Date != "2025-03-01" || forecast[29].Date != "2025-03-30" {
+		t.Fatalf("forecast covers %d days from %s", len(forecast), forecast[0].Date)
+	}
+	for i, day := range forecast[:10] {
+		if day.Due != 0 {
+			t.Errorf("day %d: %d due before the word's recall falls to 90%%", i, day.Due)
+		}
+	}
+	if forecast[10].Due != 1 {
+		t.Errorf("day 10: %d due, want the word due when recall falls to 90%%", forecast[10].Due)
+	}
+	var total int
+	for _, day := range forecast {
+		total += day.Due
+	}
+	if total != 1 {
+		// A successful review lengthens the next interval past the forecast
+		t.Errorf("%d reviews in 30 days, want 1", total)
+	}
+
+	overdue := Project(map[int64]Memory{1: {Stability: 1, Difficulty: 5, LastReviewedAt: daysAgo(100)}}, model, 0.9, 1, now)
+	if overdue[0].Due != 1 {
+		t.Errorf("overdue word: %+v, want it due today", overdue)
+	}
+
+	if lower := Project(map[int64]Memory{1: {Stability: 10, Difficulty: 5, LastReviewedAt: now}}, model, 0.8, 30, now); lower[10].Due != 0 {
+		t.Errorf("a lower retention target still reviews on day 10: %+v", lower)
+	}
+}
+
+func TestGroupRetention(t *testing.T) {
+	group := models.Group{ID: 7, Name: "Numbers"}
+	memories := map[int64]Memory{
+		1: {Stability: 5, LastReviewedAt: daysAgo(5)},
+		2: {Stability: 20, LastReviewedAt: now},
+		9: {Stability: 1, LastReviewedAt: daysAgo(1)},
+	}
+	model := models.ForecastModel{Multiplier: 1}
+	horizon := now.AddDate(0, 0, 15)
+
+	got := GroupRetention(group, []int64{1, 2, 3}, memories, model, now, horizon)
+	if got.GroupID != 7 || got.GroupName != "Numbers" || got.WordCount != 3 || got.WordsStudied != 2 {
+		t.Errorf("GroupRetention = %+v, want 2 of 3 words studied", got)
+	}
+	if !near(got.Retention, (0.9+1)/2) {
+		t.Errorf("retention = %v, want %v", got.Retention, (0.9+1)/2)
+	}
+	if want := (Retrievability(5, 1, 20) + Retrievability(20, 1, 15)) / 2; !near(got.RetentionAtHorizon, want) {
+		t.Errorf("retention at horizon = %v, want %v", got.RetentionAtHorizon, want)
+	}
+
+	if empty := GroupRetention(group, []int64{3}, memories, model, now, horizon); empty.Retention != 0 || empty.WordsStudied != 0 {
+		t.Errorf("group without studied words = %+v", empty)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	forecast := &models.Forecast{Daily: []models.ForecastDay{{Date: "a", Due: 2}, {Date: "b", Due: 5}, {Date: "c", Due: 5}}, TotalDue: 99}
+	Summarize(forecast)
+	if forecast.TotalDue != 12 || forecast.PeakDue != 5 || forecast.PeakDate != "b" {
+		t.Errorf("Summarize = %d total, peak %d on %s; want 12, 5 on b", forecast.TotalDue, forecast.PeakDue, forecast.PeakDate)
+	}
+}
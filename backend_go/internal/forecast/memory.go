@@ -0,0 +1,119 @@
+package forecast
+
+import (
+	"math"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/scheduler"
+)
+
+// weights are the published FSRS-4.5 default parameters
+var weights = [17]float64{
+	0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+}
+
+// decay and factor shape the FSRS-4.5 power forgetting curve. The factor
+// is chosen so that retrievability is 90% when the elapsed days equal the
+// stability.
+const (
+	decay  = -0.5
+	factor = 19.0 / 81
+)
+
+// FSRS ratings
+const (
+	ratingAgain = 1
+	ratingHard  = 2
+	ratingGood  = 3
+	ratingEasy  = 4
+)
+
+// Memory is the FSRS memory state of a word: stability is the number of
+// days after which recall drops to 90%, difficulty ranges from 1 to 10
+type Memory struct {
+	Stability      float64
+	Difficulty     float64
+	LastReviewedAt time.Time
+}
+
+// rating maps a review to an FSRS rating through the scheduler quality grade
+func rating(review models.WordReviewItem) int {
+	switch quality := scheduler.Quality(review.IsCorrect, review.Confidence, review.HintsUsed); {
+	case quality < 3:
+		return ratingAgain
+	case quality == 3:
+		return ratingHard
+	case quality == 4:
+		return ratingGood
+	default:
+		return ratingEasy
+	}
+}
+
+// Retrievability is the probability of recalling a word days after its
+// last review, given its stability scaled by the learner's multiplier
+func Retrievability(stability, multiplier, days float64) float64 {
+	if days <= 0 {
+		return 1
+	}
+	return math.Pow(1+factor*days/(stability*multiplier), decay)
+}
+
+// interval is the number of days until retrievability falls to retention
+func interval(stability, multiplier, retention float64) float64 {
+	return stability * multiplier / factor * (math.Pow(retention, 1/decay) - 1)
+}
+
+// initialMemory is the state after a word's first review
+func initialMemory(rating int, at time.Time) Memory {
+	return Memory{
+		Stability:      weights[rating-1],
+		Difficulty:     initialDifficulty(rating),
+		LastReviewedAt: at,
+	}
+}
+
+func initialDifficulty(rating int) float64 {
+	return clampDifficulty(weights[4] - float64(rating-3)*weights[5])
+}
+
+func clampDifficulty(d float64) float64 {
+	return math.Min(10, math.Max(1, d))
+}
+
+// next returns the state after a review given the recall probability r
+// the model predicted for it
+func (m Memory) next(rating int, r float64, at time.Time) Memory {
+	s, d := m.Stability, m.Difficulty
+
+	var stability float64
+	if rating == ratingAgain {
+		stability = weights[11] * math.Pow(d, -weights[12]) * (math.Pow(s+1, weights[13]) - 1) * math.Exp(weights[14]*(1-r))
+		stability = math.Min(stability, s)
+	} else {
+		factor := math.Exp(weights[8]) * (11 - d) * math.Pow(s, -weights[9]) * (math.Exp(weights[10]*(1-r)) - 1)
+		if rating == ratingHard {
+			factor *= weights[15]
+		}
+		if rating == ratingEasy {
+			factor *= weights[16]
+		}
+		stability = s * (1 + factor)
+	}
+
+	difficulty := d - weights[6]*float64(rating-3)
+	difficulty = weights[7]*initialDifficulty(ratingGood) + (1-weights[7])*difficulty
+
+	return Memory{
+		Stability:      math.Max(stability, 0.01),
+		Difficulty:     clampDifficulty(difficulty),
+		LastReviewedAt: at,
+	}
+}
+
+// elapsedDays returns the days between two times
+func elapsedDays(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / 24
+}
@@ -0,0 +1,217 @@
+package gamification
+
+import (
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// XP awarded for activity
+const (
+	XPCorrectReview  = 10
+	XPWrongReview    = 2
+	XPSessionStarted = 5
+)
+
+// Thresholds of the achievement rules
+const (
+	WordsLearnedGoal      = 100
+	StreakGoal            = 7
+	PerfectSessionReviews = 10
+	MinRootFamily         = 2
+)
+
+// Default daily goals for learners who have not set their own
+const (
+	DefaultDailyReviews = 20
+	DefaultDailyMinutes = 10
+)
+
+// Facts are what the achievement rules know about a learner after a
+// review, or about a session when it finishes
+type Facts struct {
+	// WordsLearned counts the distinct words answered correctly at least once
+	WordsLearned int
+	// StreakDays counts consecutive days with reviews up to today
+	StreakDays int
+	// SessionReviews and SessionWrong tally the learner's reviews in a
+	// finished session
+	SessionReviews int
+	SessionWrong   int
+	// CompletedRoots lists root families whose words have all been
+	// answered correctly
+	CompletedRoots []string
+}
+
+// Definition describes an achievement and the rule that unlocks it. The
+// rule returns the details of the instances unlocked, "" for a one-off
+// achievement.
+type Definition struct {
+	Code        string
+	Name        string
+	Description string
+	XP          int
+	rule        func(Facts) []string
+}
+
+// once turns a condition into a rule for a one-off achievement
+func once(cond func(Facts) bool) func(Facts) []string {
+	return func(f Facts) []string {
+		if cond(f) {
+			return []string{""}
+		}
+		return nil
+	}
+}
+
+// Definitions lists every achievement in display order
+var Definitions = []Definition{
+	{
+		Code:        models.AchievementFirst100Words,
+		Name:        "First 100 words",
+		Description: "Answer 100 different words correctly",
+		XP:          200,
+		rule:        once(func(f Facts) bool { return f.WordsLearned >= WordsLearnedGoal }),
+	},
+	{
+		Code:        models.AchievementStreak7Days,
+		Name:        "7-day streak",
+		Description: "Review words 7 days in a row",
+		XP:          150,
+		rule:        once(func(f Facts) bool { return f.StreakDays >= StreakGoal }),
+	},
+	{
+		Code:        models.AchievementPerfectSession,
+		Name:        "Perfect session",
+		Description: "Finish a session of at least 10 reviews without a mistake",
+		XP:          100,
+		rule: once(func(f Facts) bool {
+			return f.SessionReviews >= PerfectSessionReviews && f.SessionWrong == 0
+		}),
+	},
+	{
+		Code:        models.AchievementRootFamily,
+		Name:        "Root family completed",
+		Description: "Answer every word sharing a root correctly",
+		XP:          50,
+		rule:        func(f Facts) []string { return f.CompletedRoots },
+	},
+}
+
+// Evaluate returns the achievements whose rules the facts satisfy,
+// including ones the learner may already hold
+func Evaluate(f Facts) []models.Achievement {
+	var met []models.Achievement
+	for _, def := range Definitions {
+		for _, detail := range def.rule(f) {
+			met = append(met, def.achievement(detail))
+		}
+	}
+	return met
+}
+
+func (d Definition) achievement(detail string) models.Achievement {
+	return models.Achievement{
+		Code:        d.Code,
+		Detail:      detail,
+		Name:        d.Name,
+		Description: d.Description,
+		XP:          d.XP,
+	}
+}
+
+// ReviewXP is the XP earned by a review
+func ReviewXP(correct bool) int {
+	if correct {
+		return XPCorrectReview
+	}
+	return XPWrongReview
+}
+
+// List merges a learner's unlocked achievements with the definitions, so
+// locked achievements are listed too
+func List(unlocked []models.Achievement) []models.Achievement {
+	byCode := make(map[string][]models.Achievement)
+	for _, achievement := range unlocked {
+		byCode[achievement.Code] = append(byCode[achievement.Code], achievement)
+	}
+
+	list := make([]models.Achievement, 0, len(Definitions)+len(unlocked))
+	for _, def := range Definitions {
+		if len(byCode[def.Code]) == 0 {
+			list = append(list, def.achievement(""))
+			continue
+		}
+		for _, achievement := range byCode[def.Code] {
+			full := def.achievement(achievement.Detail)
+			full.XP = achievement.XP
+			full.Unlocked = true
+			full.UnlockedAt = achievement.UnlockedAt
+			list = append(list, full)
+		}
+	}
+	return list
+}
+
+// Streak counts consecutive days with activity ending today, or ending
+// yesterday when there is none yet today. days are "2006-01-02" dates.
+func Streak(days []string, now time.Time) int {
+	active := make(map[string]bool, len(days))
+	for _, day := range days {
+		active[day] = true
+	}
+
+	day := now.UTC().Truncate(24 * time.Hour)
+	if !active[day.Format("2006-01-02")] {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for active[day.Format("2006-01-02")] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// Goals measures the last days days of activity, oldest first, against
+// the daily goals and counts the run of completed days
+func Goals(settings models.GoalSettings, activity []models.DailyActivity, days int, now time.Time) models.Goals {
+	byDate := make(map[string]models.DailyActivity, len(activity))
+	for _, day := range activity {
+		byDate[day.Date] = day
+	}
+
+	goals := models.Goals{GoalSettings: settings, History: make([]models.GoalProgress, days)}
+	today := now.UTC().Truncate(24 * time.Hour)
+	var completed []string
+	for i := range goals.History {
+		date := today.AddDate(0, 0, i-days+1).Format("2006-01-02")
+		day, ok := byDate[date]
+		if !ok {
+			day = models.DailyActivity{Date: date}
+		}
+
+		progress := models.GoalProgress{
+			DailyActivity: day,
+			ReviewsMet:    day.Reviews >= settings.DailyReviews,
+			MinutesMet:    day.Minutes >= float64(settings.DailyMinutes),
+		}
+		progress.Completed = progress.ReviewsMet && progress.MinutesMet && (settings.DailyReviews > 0 || settings.DailyMinutes > 0)
+		if progress.Completed {
+			completed = append(completed, date)
+		}
+		goals.History[i] = progress
+	}
+
+	goals.Today = goals.History[days-1]
+	goals.StreakDays = Streak(completed, now)
+	return goals
+}
+
+// WeekStart returns the Monday, in UTC, of the week containing t as a
+// "2006-01-02" date
+func WeekStart(t time.Time) string {
+	day := t.UTC().Truncate(24 * time.Hour)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)).Format("2006-01-02")
+}
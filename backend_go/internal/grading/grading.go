@@ -0,0 +1,122 @@
+package grading
+
+import (
+	"strings"
+)
+
+// Result represents the outcome of grading a typed answer
+type Result struct {
+	Correct  bool    `json:"correct"`
+	Score    float64 `json:"score"`
+	Distance int     `json:"distance"`
+	Matched  string  `json:"matched"`
+}
+
+// DefaultMaxEdits returns the edit-distance tolerance for an answer of the
+// given length: none for very short words, growing with length
+func DefaultMaxEdits(length int) int {
+	switch {
+	case length <= 3:
+		return 0
+	case length <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Alternatives splits an expected answer such as "hello / hi" into its
+// accepted forms
+func Alternatives(expected string) []string {
+	fields := strings.FieldsFunc(expected, func(r rune) bool {
+		return r == '/' || r == ',' || r == ';' || r == '|'
+	})
+
+	alternatives := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			alternatives = append(alternatives, field)
+		}
+	}
+	return alternatives
+}
+
+// Grade compares input against every accepted answer and returns the best
+// match. An answer is correct when its normalized edit distance is within
+// DefaultMaxEdits, or within maxEdits when that is stricter; a negative
+// maxEdits keeps the default. Callers can tighten the tolerance but never
+// loosen it. An answer within its own tolerance beats a closer one that is
+// not, so the verdict does not depend on the order of the answers. The
+// score gives partial credit as the share of the accepted answer that
+// matched.
+func Grade(input string, accepted []string, maxEdits int) Result {
+	given := []rune(Normalize(input))
+	best := Result{Distance: -1}
+
+	for _, answer := range accepted {
+		expected := []rune(Normalize(answer))
+		if len(expected) == 0 {
+			continue
+		}
+
+		distance := Distance(given, expected)
+		tolerance := DefaultMaxEdits(len(expected))
+		if maxEdits >= 0 && maxEdits < tolerance {
+			tolerance = maxEdits
+		}
+		correct := distance <= tolerance
+
+		if best.Distance >= 0 {
+			if best.Correct && !correct {
+				continue
+			}
+			if best.Correct == correct && distance >= best.Distance {
+				continue
+			}
+		}
+
+		longest := len(expected)
+		if len(given) > longest {
+			longest = len(given)
+		}
+
+		best = Result{
+			Correct:  correct,
+			Score:    1 - float64(distance)/float64(longest),
+			Distance: distance,
+			Matched:  answer,
+		}
+	}
+
+	if best.Distance < 0 {
+		return Result{}
+	}
+	return best
+}
+
+// Distance returns the Levenshtein edit distance between a and b
+func Distance(a, b []rune) int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
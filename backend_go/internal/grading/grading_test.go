@@ -0,0 +1,146 @@
+package grading
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	for input, want := range map[string]string{
+		"Hello":            "hello",
+		"  thank   you  ":  "thank you",
+		"good-morning!":    "good morning",
+		"Café":             "cafe",
+		"don't":            "don t",
+		"مَرْحَبًا":        "مرحبا",
+		"مرحـــبا":         "مرحبا",
+		"أهلا":             "اهلا",
+		"إسلام":            "اسلام",
+		"مدرسة":            "مدرسه",
+		"على":              "علي",
+		"مر\u200cحبا":      "مرحبا",
+		"...":              "",
+		"":                 "",
+		"3 apples":         "3 apples",
+		"Numbers: 1, 2, 3": "numbers 1 2 3",
+	} {
+		if got := Normalize(input); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestAlternatives(t *testing.T) {
+	for input, want := range map[string][]string{
+		"hello":               {"hello"},
+		"hello / hi":          {"hello", "hi"},
+		"one, single; alone":  {"one", "single", "alone"},
+		"yes|yeah":            {"yes", "yeah"},
+		" / thank you / ":     {"thank you"},
+		"":                    {},
+		"good morning, / hi ": {"good morning", "hi"},
+	} {
+		if got := Alternatives(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("Alternatives(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// score is the partial credit for distance edits over length runes
+func score(distance, length int) float64 {
+	return 1 - float64(distance)/float64(length)
+}
+
+func TestGrade(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    string
+		accepted []string
+		maxEdits int
+		want     Result
+	}{
+		{
+			name:     "exact after normalizing",
+			input:    " Thank-You ",
+			accepted: []string{"thank you"},
+			maxEdits: -1,
+			want:     Result{Correct: true, Score: 1, Distance: 0, Matched: "thank you"},
+		},
+		{
+			name:     "short words allow no typos",
+			input:    "tow",
+			accepted: []string{"two"},
+			maxEdits: -1,
+			want:     Result{Correct: false, Score: score(2, 3), Distance: 2, Matched: "two"},
+		},
+		{
+			name:     "one typo in a medium word",
+			input:    "helo",
+			accepted: []string{"hello"},
+			maxEdits: -1,
+			want:     Result{Correct: true, Score: 0.8, Distance: 1, Matched: "hello"},
+		},
+		{
+			name:     "two typos in a long word",
+			input:    "mornin goodd",
+			accepted: []string{"morning good"},
+			maxEdits: -1,
+			want:     Result{Correct: true, Score: score(2, 12), Distance: 2, Matched: "morning good"},
+		},
+		{
+			name:     "maxEdits tightens",
+			input:    "helo",
+			accepted: []string{"hello"},
+			maxEdits: 0,
+			want:     Result{Correct: false, Score: 0.8, Distance: 1, Matched: "hello"},
+		},
+		{
+			name:     "maxEdits cannot loosen",
+			input:    "hxllx",
+			accepted: []string{"hello"},
+			maxEdits: 5,
+			want:     Result{Correct: false, Score: 0.6, Distance: 2, Matched: "hello"},
+		},
+		{
+			name:     "closest alternative",
+			input:    "hi",
+			accepted: []string{"hello", "hi"},
+			maxEdits: -1,
+			want:     Result{Correct: true, Score: 1, Distance: 0, Matched: "hi"},
+		},
+		{
+			name:     "accepted match beats a closer rejected one",
+			input:    "abcd",
+			accepted: []string{"abc", "abcde"},
+			maxEdits: -1,
+			want:     Result{Correct: true, Score: 0.8, Distance: 1, Matched: "abcde"},
+		},
+		{
+			name:     "in either order",
+			input:    "abcd",
+			accepted: []string{"abcde", "abc"},
+			maxEdits: -1,
+			want:     Result{Correct: true, Score: 0.8, Distance: 1, Matched: "abcde"},
+		},
+		{
+			name:     "arabic variants",
+			input:    "اهلاً",
+			accepted: []string{"أهلا"},
+			maxEdits: -1,
+			want:     Result{Correct: true, Score: 1, Distance: 0, Matched: "أهلا"},
+		},
+		{
+			name:     "empty answers are skipped",
+			input:    "hello",
+			accepted: []string{"", "!!"},
+			maxEdits: -1,
+			want:     Result{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Grade(tc.input, tc.accepted, tc.maxEdits); got != tc.want {
+				t.Errorf("Grade(%q, %q, %d) = %+v, want %+v", tc.input, tc.accepted, tc.maxEdits, got, tc.want)
+			}
+		})
+	}
+}
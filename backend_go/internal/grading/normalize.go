@@ -0,0 +1,50 @@
+package grading
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// arabicVariants folds common orthographic variants onto one letter
+var arabicVariants = map[rune]rune{
+	'أ': 'ا', // alef with hamza above
+	'إ': 'ا', // alef with hamza below
+	'آ': 'ا', // alef with madda
+	'ٱ': 'ا', // alef wasla
+	'ى': 'ي', // alef maqsura
+	'ة': 'ه', // ta marbuta
+	'ؤ': 'و', // waw with hamza
+	'ئ': 'ي', // ya with hamza
+}
+
+// Normalize folds an answer to a canonical form for comparison: it strips
+// diacritics (Arabic harakat and Latin accents), tatweel and punctuation,
+// folds Arabic letter variants, lowercases and collapses whitespace
+func Normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFD.String(s) {
+		if v, ok := arabicVariants[r]; ok {
+			r = v
+		}
+
+		switch {
+		case unicode.Is(unicode.Mn, r), r == 'ـ', unicode.Is(unicode.Cf, r):
+			// Diacritics, tatweel and zero-width format characters
+			continue
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteRune(' ')
+			}
+			space = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			// Whitespace, hyphens, apostrophes and other punctuation
+			space = true
+		}
+	}
+
+	return b.String()
+}
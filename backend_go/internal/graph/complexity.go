@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	// MaxComplexity bounds the estimated number of fields a query resolves
+	MaxComplexity = 5000
+	// MaxDepth bounds how deeply a query nests fields
+	MaxDepth = 12
+
+	// listEstimate is the assumed length of lists that are not connections
+	listEstimate = 10
+)
+
+// complexity estimates how many fields op resolves and how deeply it
+// nests them. Every field costs one; the fields under a connection are
+// counted once per node it may return, so a query's cost grows with the
+// page sizes it asks for. Introspection is free, since its size is bounded
+// by the schema.
+func complexity(op *ast.OperationDefinition, vars map[string]interface{}) (cost, depth int) {
+	return selectionCost(op.SelectionSet, "", vars, 1)
+}
+
+func selectionCost(set ast.SelectionSet, parentType string, vars map[string]interface{}, level int) (cost, depth int) {
+	for _, selection := range set {
+		var c, d int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			c, d = fieldCost(selection, parentType, vars, level)
+		case *ast.InlineFragment:
+			c, d = selectionCost(selection.SelectionSet, parentType, vars, level)
+		case *ast.FragmentSpread:
+			c, d = selectionCost(selection.Definition.SelectionSet, parentType, vars, level)
+		}
+		cost += c
+		if d > depth {
+			depth = d
+		}
+	}
+	return cost, depth
+}
+
+func fieldCost(field *ast.Field, parentType string, vars map[string]interface{}, level int) (cost, depth int) {
+	if strings.HasPrefix(field.Name, "__") || field.Definition == nil {
+		return 0, 0
+	}
+
+	fieldType := field.Definition.Type.Name()
+	childCost, childDepth := selectionCost(field.SelectionSet, fieldType, vars, level+1)
+
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(fieldType, "Connection"):
+		multiplier = pageSize(field.ArgumentMap(vars)["first"])
+	case field.Definition.Type.Elem != nil && !strings.HasSuffix(parentType, "Connection"):
+		// The edges and nodes of a connection are already counted
+		multiplier = listEstimate
+	}
+
+	return 1 + childCost*multiplier, max(level, childDepth)
+}
+
+// pageSize reads a first argument, counting at least one node so a page's
+// fields are priced even when it asks for none
+func pageSize(first interface{}) int {
+	n := defaultPageSize
+	switch v := first.(type) {
+	case int64:
+		n = int(v)
+	case float64:
+		n = int(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			n = int(i)
+		}
+	}
+	return max(n, 1)
+}
@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestComplexity(t *testing.T) {
+	s := New(nil)
+	tests := []struct {
+		name        string
+		query       string
+		vars        map[string]interface{}
+		cost, depth int
+	}{
+		{"plain fields", `{ word(id: "1") { english arabic } }`, nil, 3, 2},
+		{"connection counts its nodes once per page item", `{ words(first: 5) { nodes { english } } }`, nil, 11, 3},
+		{"default page size", `{ words { nodes { english } } }`, nil, 41, 3},
+		{"page size from a variable", `query($n: Int) { words(first: $n) { nodes { english } } }`, map[string]interface{}{"n": json.Number("7")}, 15, 3},
+		{"empty page still prices its fields", `{ words(first: 0) { nodes { english } } }`, nil, 3, 3},
+		{"plain list", `{ word(id: "1") { groups { name } } }`, nil, 12, 3},
+		{"nested connections multiply", `{ groups(first: 2) { nodes { words(first: 3) { nodes { id } } } } }`, nil, 1 + 2*(1+1*(1+3*(1+1))), 5},
+		{"fragments", `{ word(id: "1") { ...W ... on Word { romaji } } } fragment W on Word { english }`, nil, 3, 2},
+		{"introspection is free", `{ __schema { types { name } } word(id: "1") { __typename } }`, nil, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, errs := gqlparser.LoadQuery(s.ast, tt.query)
+			if len(errs) > 0 {
+				t.Fatalf("LoadQuery: %v", errs)
+			}
+			cost, depth := complexity(doc.Operations[0], tt.vars)
+			if cost != tt.cost || depth != tt.depth {
+				t.Errorf("complexity = %d, %d, want %d, %d", cost, depth, tt.cost, tt.depth)
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		first interface{}
+		want  int
+	}{
+		{nil, defaultPageSize},
+		{int64(5), 5},
+		{float64(3), 3},
+		{json.Number("9"), 9},
+		{json.Number("nine"), defaultPageSize},
+		{"9", defaultPageSize},
+		{int64(0), 1},
+		{int64(-4), 1},
+	}
+	for _, tt := range tests {
+		if got := pageSize(tt.first); got != tt.want {
+			t.Errorf("pageSize(%#v) = %d, want %d", tt.first, got, tt.want)
+		}
+	}
+}
+
+// nest returns a query selecting id at the end of path, nesting
+// len(path)+1 fields deep
+func nest(path ...string) string {
+	return "{ " + strings.Join(path, " { ") + " { id" + strings.Repeat(" }", len(path)) + " }"
+}
+
+func TestCheck(t *testing.T) {
+	s := New(nil)
+	code := func(t *testing.T, query string, vars map[string]interface{}) interface{} {
+		t.Helper()
+		if err := s.check(query, "", vars); err != nil {
+			return err.Extensions["code"]
+		}
+		return nil
+	}
+
+	t.Run("cheap query", func(t *testing.T) {
+		if got := code(t, `{ words { nodes { english } } }`, nil); got != nil {
+			t.Errorf("code = %v, want none", got)
+		}
+	})
+
+	t.Run("too complex", func(t *testing.T) {
+		query := `{ groups(first: 100) { nodes { words(first: 100) { nodes { id } } } } }`
+		if got := code(t, query, nil); got != "COMPLEXITY_LIMIT_EXCEEDED" {
+			t.Errorf("code = %v, want COMPLEXITY_LIMIT_EXCEEDED", got)
+		}
+	})
+
+	// Both depth limits count the same way: MaxDepth passes, one more
+	// field is refused
+	t.Run("depth", func(t *testing.T) {
+		for _, tt := range []struct {
+			query string
+			depth int
+		}{
+			{nest(`word(id: "1")`, "groups", "words(first: 1)", "nodes", "groups", "words(first: 1)", "nodes", "groups", "words(first: 1)", "nodes", "groups"), MaxDepth},
+			{nest(`word(id: "1")`, "groups", "studySessions(first: 1)", "nodes", "activity", "group", "words(first: 1)", "nodes", "groups", "words(first: 1)", "nodes", "groups"), MaxDepth + 1},
+		} {
+			doc, errs := gqlparser.LoadQuery(s.ast, tt.query)
+			if len(errs) > 0 {
+				t.Fatalf("LoadQuery: %v", errs)
+			}
+			if _, depth := complexity(doc.Operations[0], nil); depth != tt.depth {
+				t.Fatalf("depth = %d, want %d", depth, tt.depth)
+			}
+
+			refused := code(t, tt.query, nil) == "MAX_DEPTH_EXCEEDED"
+			execRefused := len(s.schema.Validate(tt.query)) > 0
+			if want := tt.depth > MaxDepth; refused != want || execRefused != want {
+				t.Errorf("depth %d: refused = %t, executor refused = %t, want %t", tt.depth, refused, execRefused, want)
+			}
+		}
+	})
+
+	// Documents the pricer cannot load are left to the executor only when
+	// it rejects them too
+	t.Run("parsers agree", func(t *testing.T) {
+		for _, query := range []string{
+			`{ nope }`,
+			`{ dashboard }`,
+			`{ words(first: "a") { nodes { english } } }`,
+			`{ word(id: 1.5) { english } }`,
+			`{ word(id: "1") { english(x: 1) } }`,
+			`query($x: Int) { words { nodes { english } } }`,
+			`query($x: [Int]) { words(first: $x) { nodes { english } } }`,
+			`{ words { nodes { english } } } fragment F on Word { english }`,
+			`{ words { nodes { ... on Group { name } } } }`,
+			`{ words { nodes { english @skip(if: true) @skip(if: false) } } }`,
+			`{ a: words(first: 1) { nodes { id } } a: words(first: 2) { nodes { id } } }`,
+			`query Q { words { nodes { id } } } query Q { groups { nodes { id } } }`,
+		} {
+			_, parseErrs := gqlparser.LoadQuery(s.ast, query)
+			execErrs := s.schema.Validate(query)
+			if len(parseErrs) == 0 || len(execErrs) == 0 {
+				t.Errorf("%s: pricer errors %v, executor errors %v, want both to refuse it", query, parseErrs, execErrs)
+			}
+			if got := code(t, query, nil); got != nil {
+				t.Errorf("%s: code = %v, want the executor to report it", query, got)
+			}
+		}
+	})
+
+	t.Run("unpriceable query fails closed", func(t *testing.T) {
+		// A pricer that does not know the dashboard, as if the two
+		// schemas had drifted apart
+		drifted := New(nil)
+		drifted.ast = gqlparser.MustLoadSchema(&ast.Source{
+			Name:  "schema.graphql",
+			Input: strings.Replace(Schema, "dashboard: Dashboard!", "board: Dashboard!", 1),
+		})
+		err := drifted.check(`{ dashboard { quickStats { totalWords } } }`, "", nil)
+		if err == nil || err.Extensions["code"] != "COMPLEXITY_UNKNOWN" {
+			t.Errorf("check = %v, want COMPLEXITY_UNKNOWN", err)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+const (
+	// defaultPageSize matches the default of first in the schema
+	defaultPageSize = 20
+	// maxPageSize bounds first on every connection
+	maxPageSize = 100
+
+	cursorPrefix = "cursor:"
+)
+
+// errInvalidCursor is returned for an after argument that is not a cursor
+// this API issued
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageArgs are the arguments of every connection field; the schema
+// defaults first to defaultPageSize
+type pageArgs struct {
+	First int32
+	After *string
+}
+
+// request turns the arguments into a repository page request. One more
+// row than asked for is fetched to tell whether there is a next page.
+func (a pageArgs) request() (models.PageRequest, error) {
+	if a.First < 0 || a.First > maxPageSize {
+		return models.PageRequest{}, fmt.Errorf("first must be between 0 and %d", maxPageSize)
+	}
+
+	page := models.PageRequest{Limit: int(a.First) + 1}
+	if a.After != nil && *a.After != "" {
+		after, err := decodeCursor(*a.After)
+		if err != nil {
+			return models.PageRequest{}, err
+		}
+		page.After = after
+	}
+	return page, nil
+}
+
+// encodeCursor returns the opaque cursor for the row with the given ID
+func encodeCursor(id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(id, 10)))
+}
+
+// decodeCursor returns the row ID of a cursor
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, errInvalidCursor
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(string(raw), cursorPrefix), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errInvalidCursor
+	}
+	return id, nil
+}
+
+// connection is a page of nodes with their cursors
+type connection[T any] struct {
+	edges    []*edge[T]
+	pageInfo *pageInfo
+}
+
+type edge[T any] struct {
+	cursor string
+	node   T
+}
+
+type pageInfo struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+// newConnection cuts a connection from rows fetched with page, which hold
+// at most one row past the page. Rows are put in cursor order first: by
+// ID, descending when desc is set. rows may be shared through a loader's
+// cache, so they are sorted in a copy.
+func newConnection[V any, T any](rows []V, page models.PageRequest, desc bool, id func(V) int64, node func(V) T) *connection[T] {
+	rows = append([]V(nil), rows...)
+	sortByID(rows, id, desc)
+
+	info := &pageInfo{}
+	if len(rows) >= page.Limit {
+		info.hasNextPage = true
+		rows = rows[:page.Limit-1]
+	}
+
+	edges := make([]*edge[T], len(rows))
+	for i, row := range rows {
+		edges[i] = &edge[T]{cursor: encodeCursor(id(row)), node: node(row)}
+	}
+	if len(edges) > 0 {
+		info.endCursor = &edges[len(edges)-1].cursor
+	}
+	return &connection[T]{edges: edges, pageInfo: info}
+}
+
+func (c *connection[T]) Edges() []*edge[T] {
+	return c.edges
+}
+
+func (c *connection[T]) Nodes() []T {
+	nodes := make([]T, len(c.edges))
+	for i, e := range c.edges {
+		nodes[i] = e.node
+	}
+	return nodes
+}
+
+func (c *connection[T]) PageInfo() *pageInfo {
+	return c.pageInfo
+}
+
+func (e *edge[T]) Cursor() string {
+	return e.cursor
+}
+
+func (e *edge[T]) Node() T {
+	return e.node
+}
+
+func (p *pageInfo) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *pageInfo) EndCursor() *string {
+	return p.endCursor
+}
+
+// sortByID orders rows by ID, descending when desc is set
+func sortByID[V any](rows []V, id func(V) int64, desc bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if desc {
+			return id(rows[i]) > id(rows[j])
+		}
+		return id(rows[i]) < id(rows[j])
+	})
+}
@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/stats"
+)
+
+// dashboardResolver resolves the Dashboard type. Quick stats and study
+// progress are both judged from the same word progress, loaded once.
+type dashboardResolver struct {
+	repo repositories.Repository
+
+	progressOnce sync.Once
+	progress     []models.WordProgress
+	progressErr  error
+}
+
+func (r *dashboardResolver) wordProgress(ctx context.Context) ([]models.WordProgress, error) {
+	r.progressOnce.Do(func() {
+		r.progress, r.progressErr = r.repo.GetWordProgress(ctx)
+	})
+	return r.progress, r.progressErr
+}
+
+func (r *dashboardResolver) LastSession(ctx context.Context) (*sessionResolver, error) {
+	session, err := r.repo.GetLastStudySession(ctx)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	return newSessionResolver(*session), nil
+}
+
+func (r *dashboardResolver) QuickStats(ctx context.Context) (*quickStatsResolver, error) {
+	quickStats, err := r.repo.GetQuickStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	words, err := r.wordProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quickStats.MasteryPercentage = stats.Progress(words, nil, nil).MasteryPercentage
+	return &quickStatsResolver{stats: *quickStats}, nil
+}
+
+func (r *dashboardResolver) StudyProgress(ctx context.Context, args struct{ ByGroup bool }) (*progressResolver, error) {
+	words, err := r.wordProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []models.Group
+	var memberships []models.WordGroup
+	if args.ByGroup {
+		if groups, err = r.repo.GetGroups(ctx); err != nil {
+			return nil, err
+		}
+		if memberships, err = r.repo.GetGroupMemberships(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return &progressResolver{progress: stats.Progress(words, groups, memberships)}, nil
+}
+
+// quickStatsResolver resolves the QuickStats type
+type quickStatsResolver struct {
+	stats models.DashboardStats
+}
+
+func (r *quickStatsResolver) TotalWords() int32 {
+	return int32(r.stats.TotalWords)
+}
+
+func (r *quickStatsResolver) TotalGroups() int32 {
+	return int32(r.stats.TotalGroups)
+}
+
+func (r *quickStatsResolver) TotalSessions() int32 {
+	return int32(r.stats.TotalSessions)
+}
+
+func (r *quickStatsResolver) ReviewCount() int32 {
+	return int32(r.stats.ReviewCount)
+}
+
+func (r *quickStatsResolver) CorrectCount() int32 {
+	return int32(r.stats.CorrectCount)
+}
+
+func (r *quickStatsResolver) AccuracyRate() float64 {
+	return r.stats.AccuracyRate
+}
+
+func (r *quickStatsResolver) SuccessRate() int32 {
+	return int32(r.stats.SuccessRate)
+}
+
+func (r *quickStatsResolver) StudyStreak() int32 {
+	return int32(r.stats.StudyStreak)
+}
+
+func (r *quickStatsResolver) MasteryPercentage() float64 {
+	return r.stats.MasteryPercentage
+}
+
+func (r *quickStatsResolver) LastSessionDate() *string {
+	return optionalString(r.stats.LastSessionDate)
+}
+
+// progressResolver resolves the StudyProgress type
+type progressResolver struct {
+	progress models.StudyProgress
+}
+
+func (r *progressResolver) TotalAvailableWords() int32 {
+	return int32(r.progress.TotalAvailableWords)
+}
+
+func (r *progressResolver) TotalWordsStudied() int32 {
+	return int32(r.progress.TotalWordsStudied)
+}
+
+func (r *progressResolver) MasteredWords() int32 {
+	return int32(r.progress.MasteredWords)
+}
+
+func (r *progressResolver) MasteryPercentage() float64 {
+	return r.progress.MasteryPercentage
+}
+
+func (r *progressResolver) Groups() []*groupProgressResolver {
+	groups := make([]*groupProgressResolver, len(r.progress.Groups))
+	for i, group := range r.progress.Groups {
+		groups[i] = &groupProgressResolver{progress: group}
+	}
+	return groups
+}
+
+// groupProgressResolver resolves the GroupProgress type
+type groupProgressResolver struct {
+	progress models.GroupProgress
+}
+
+func (r *groupProgressResolver) Group(ctx context.Context) (*groupResolver, error) {
+	return loadGroup(ctx, r.progress.GroupID)
+}
+
+func (r *groupProgressResolver) TotalAvailableWords() int32 {
+	return int32(r.progress.TotalAvailableWords)
+}
+
+func (r *groupProgressResolver) TotalWordsStudied() int32 {
+	return int32(r.progress.TotalWordsStudied)
+}
+
+func (r *groupProgressResolver) MasteredWords() int32 {
+	return int32(r.progress.MasteredWords)
+}
+
+func (r *groupProgressResolver) MasteryPercentage() float64 {
+	return r.progress.MasteryPercentage
+}
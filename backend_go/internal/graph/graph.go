@@ -0,0 +1,88 @@
+// Package graph serves the GraphQL API. Related objects are fetched through
+// per-request batching loaders, lists are cursor-paginated connections, and
+// queries are priced before they run so a single request cannot fan out
+// without bound.
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Schema is the GraphQL schema in SDL
+//
+//go:embed schema.graphql
+var Schema string
+
+// maxParallelism bounds how many resolvers of one request run at once. It
+// matches maxPageSize so the items of a full page batch together.
+const maxParallelism = maxPageSize
+
+// Server executes GraphQL requests against the repository
+type Server struct {
+	repo   repositories.Repository
+	schema *graphql.Schema
+	// ast is the schema as gqlparser sees it, for pricing queries
+	ast *ast.Schema
+}
+
+// New creates a GraphQL server over repo
+func New(repo repositories.Repository) *Server {
+	return &Server{
+		repo: repo,
+		schema: graphql.MustParseSchema(Schema, &resolver{repo: repo},
+			graphql.UseStringDescriptions(),
+			graphql.MaxParallelism(maxParallelism),
+			graphql.MaxDepth(MaxDepth),
+		),
+		ast: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: Schema}),
+	}
+}
+
+// Exec runs a request. Queries over MaxComplexity or MaxDepth are refused
+// before any resolver runs.
+func (s *Server) Exec(ctx context.Context, query, operationName string, variables map[string]interface{}) *graphql.Response {
+	if err := s.check(query, operationName, variables); err != nil {
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{err}}
+	}
+	return s.schema.Exec(withLoaders(ctx, s.repo), query, operationName, variables)
+}
+
+// check prices the requested operation. Documents the executor rejects
+// pass, so it reports them with its own validation errors; anything else
+// that cannot be priced is refused rather than run unchecked.
+func (s *Server) check(query, operationName string, variables map[string]interface{}) *gqlerrors.QueryError {
+	doc, errs := gqlparser.LoadQuery(s.ast, query)
+	if len(errs) > 0 {
+		if len(s.schema.ValidateWithVariables(query, variables)) > 0 {
+			return nil
+		}
+		err := gqlerrors.Errorf("query could not be priced: %s", errs[0].Message)
+		err.Extensions = map[string]interface{}{"code": "COMPLEXITY_UNKNOWN"}
+		return err
+	}
+	// The executor refuses a missing operation without running anything
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return nil
+	}
+
+	cost, depth := complexity(op, variables)
+	switch {
+	case depth > MaxDepth:
+		err := gqlerrors.Errorf("query depth %d exceeds the maximum of %d", depth, MaxDepth)
+		err.Extensions = map[string]interface{}{"code": "MAX_DEPTH_EXCEEDED", "depth": depth, "maxDepth": MaxDepth}
+		return err
+	case cost > MaxComplexity:
+		err := gqlerrors.Errorf("query complexity %d exceeds the maximum of %d", cost, MaxComplexity)
+		err.Extensions = map[string]interface{}{"code": "COMPLEXITY_LIMIT_EXCEEDED", "complexity": cost, "maxComplexity": MaxComplexity}
+		return err
+	}
+	return nil
+}
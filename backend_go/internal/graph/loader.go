@@ -0,0 +1,208 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+)
+
+const (
+	// batchWait is how long a batch collects keys before it is fetched.
+	// Resolvers for the items of a list run concurrently, so this only has
+	// to cover goroutine scheduling.
+	batchWait = 2 * time.Millisecond
+	// maxBatch fetches a batch early once it holds this many keys
+	maxBatch = 500
+)
+
+// batcher collects the keys requested by concurrent resolvers and loads
+// them with a single fetch, caching the result for the rest of the request
+type batcher[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending *batch[K, V]
+	loaded  map[K]*batch[K, V]
+}
+
+// batch is one fetch; done is closed once values and err are set
+type batch[K comparable, V any] struct {
+	keys   []K
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+func newBatcher[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *batcher[K, V] {
+	return &batcher[K, V]{fetch: fetch, loaded: make(map[K]*batch[K, V])}
+}
+
+// load returns the value for key, or the zero value if the fetch found
+// none, waiting for the batch key was added to
+func (l *batcher[K, V]) load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	b, ok := l.loaded[key]
+	if !ok {
+		b = l.pending
+		if b == nil {
+			b = &batch[K, V]{done: make(chan struct{})}
+			l.pending = b
+			time.AfterFunc(batchWait, func() { l.dispatch(ctx, b) })
+		}
+		b.keys = append(b.keys, key)
+		l.loaded[key] = b
+		// Detaching the full batch keeps later keys out of it
+		if len(b.keys) >= maxBatch {
+			l.pending = nil
+			go l.run(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return b.values[key], b.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch fetches b once its wait is over, unless it was fetched early
+func (l *batcher[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	l.mu.Lock()
+	if l.pending != b {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+	l.run(ctx, b)
+}
+
+// run fetches b and wakes its waiters
+func (l *batcher[K, V]) run(ctx context.Context, b *batch[K, V]) {
+	b.values, b.err = l.fetch(ctx, b.keys)
+	close(b.done)
+}
+
+// pageKey asks for one page of a parent's children
+type pageKey struct {
+	parent int64
+	page   models.PageRequest
+}
+
+// pager fetches one page of children for each of many parents; it backs
+// the batchers of nested connections
+func pager[V any](fetch func(ctx context.Context, parents []int64, page models.PageRequest) ([]V, error), parentOf func(V) int64) func(context.Context, []pageKey) (map[pageKey][]V, error) {
+	return func(ctx context.Context, keys []pageKey) (map[pageKey][]V, error) {
+		// Parents asking for the same page share a query
+		parents := make(map[models.PageRequest][]int64)
+		for _, key := range keys {
+			parents[key.page] = append(parents[key.page], key.parent)
+		}
+
+		values := make(map[pageKey][]V, len(keys))
+		for page, ids := range parents {
+			items, err := fetch(ctx, ids, page)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				key := pageKey{parent: parentOf(item), page: page}
+				values[key] = append(values[key], item)
+			}
+		}
+		return values, nil
+	}
+}
+
+// byID indexes rows fetched by ID
+func byID[V any](items []V, idOf func(V) int64) map[int64]*V {
+	values := make(map[int64]*V, len(items))
+	for i := range items {
+		values[idOf(items[i])] = &items[i]
+	}
+	return values
+}
+
+// loaders holds the batchers of one request
+type loaders struct {
+	words      *batcher[int64, *models.Word]
+	groups     *batcher[int64, *models.Group]
+	activities *batcher[int64, *models.StudyActivity]
+	sessions   *batcher[int64, *models.StudySession]
+	wordGroups *batcher[int64, []models.Group]
+
+	groupWords       *batcher[pageKey, []models.WordGroup]
+	groupActivities  *batcher[pageKey, []models.StudyActivity]
+	groupSessions    *batcher[pageKey, []models.StudySession]
+	activitySessions *batcher[pageKey, []models.StudySession]
+	wordReviews      *batcher[pageKey, []models.WordReviewItem]
+	sessionReviews   *batcher[pageKey, []models.WordReviewItem]
+}
+
+func newLoaders(repo repositories.Repository) *loaders {
+	sessionsBy := func(by string) func(context.Context, []int64, models.PageRequest) ([]models.StudySession, error) {
+		return func(ctx context.Context, ids []int64, page models.PageRequest) ([]models.StudySession, error) {
+			return repo.PageStudySessions(ctx, by, ids, page)
+		}
+	}
+	reviewsBy := func(by string) func(context.Context, []int64, models.PageRequest) ([]models.WordReviewItem, error) {
+		return func(ctx context.Context, ids []int64, page models.PageRequest) ([]models.WordReviewItem, error) {
+			return repo.PageWordReviews(ctx, by, ids, page)
+		}
+	}
+
+	return &loaders{
+		words: newBatcher(func(ctx context.Context, ids []int64) (map[int64]*models.Word, error) {
+			words, err := repo.GetWordsByIDs(ctx, ids)
+			return byID(words, func(w models.Word) int64 { return w.ID }), err
+		}),
+		groups: newBatcher(func(ctx context.Context, ids []int64) (map[int64]*models.Group, error) {
+			groups, err := repo.GetGroupsByIDs(ctx, ids)
+			return byID(groups, func(g models.Group) int64 { return g.ID }), err
+		}),
+		activities: newBatcher(func(ctx context.Context, ids []int64) (map[int64]*models.StudyActivity, error) {
+			activities, err := repo.GetStudyActivitiesByIDs(ctx, ids)
+			return byID(activities, func(a models.StudyActivity) int64 { return a.ID }), err
+		}),
+		sessions: newBatcher(func(ctx context.Context, ids []int64) (map[int64]*models.StudySession, error) {
+			sessions, err := repo.GetStudySessionsByIDs(ctx, ids)
+			return byID(sessions, func(s models.StudySession) int64 { return s.ID }), err
+		}),
+		wordGroups: newBatcher(func(ctx context.Context, ids []int64) (map[int64][]models.Group, error) {
+			memberships, err := repo.GetWordGroupsByWordIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			groups := make(map[int64][]models.Group, len(ids))
+			for _, membership := range memberships {
+				groups[membership.WordID] = append(groups[membership.WordID], *membership.Group)
+			}
+			return groups, nil
+		}),
+
+		groupWords:       newBatcher(pager(repo.PageGroupWords, func(m models.WordGroup) int64 { return m.GroupID })),
+		groupActivities:  newBatcher(pager(repo.PageStudyActivities, func(a models.StudyActivity) int64 { return a.GroupID })),
+		groupSessions:    newBatcher(pager(sessionsBy(models.PageByGroup), func(s models.StudySession) int64 { return s.GroupID })),
+		activitySessions: newBatcher(pager(sessionsBy(models.PageByActivity), func(s models.StudySession) int64 { return s.StudyActivityID })),
+		wordReviews:      newBatcher(pager(reviewsBy(models.PageByWord), func(r models.WordReviewItem) int64 { return r.WordID })),
+		sessionReviews:   newBatcher(pager(reviewsBy(models.PageBySession), func(r models.WordReviewItem) int64 { return r.StudySessionID })),
+	}
+}
+
+type loadersKey struct{}
+
+// withLoaders returns a context carrying fresh loaders over repo
+func withLoaders(ctx context.Context, repo repositories.Repository) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders(repo))
+}
+
+// loadersFrom returns the request's loaders
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
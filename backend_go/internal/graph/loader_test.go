@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a fetch that squares its keys and records each batch
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *recorder) fetch(_ context.Context, keys []int) (map[int]int, error) {
+	r.mu.Lock()
+	r.batches = append(r.batches, append([]int(nil), keys...))
+	r.mu.Unlock()
+
+	values := make(map[int]int, len(keys))
+	for _, key := range keys {
+		values[key] = key * key
+	}
+	return values, nil
+}
+
+func (r *recorder) calls() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.batches
+}
+
+// loadAll loads keys concurrently and fails the test on any error or
+// wrong value
+func loadAll(t *testing.T, l *batcher[int, int], keys []int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			value, err := l.load(context.Background(), key)
+			if err != nil || value != key*key {
+				t.Errorf("load(%d) = %d, %v, want %d", key, value, err, key*key)
+			}
+		}(key)
+	}
+	wg.Wait()
+}
+
+func TestBatcherBatchesConcurrentLoads(t *testing.T) {
+	var r recorder
+	l := newBatcher(r.fetch)
+
+	loadAll(t, l, []int{3, 1, 2, 1, 3})
+	calls := r.calls()
+	if len(calls) != 1 {
+		t.Fatalf("fetched %d batches, want 1: %v", len(calls), calls)
+	}
+	slices.Sort(calls[0])
+	if !slices.Equal(calls[0], []int{1, 2, 3}) {
+		t.Errorf("batch = %v, want each key once", calls[0])
+	}
+
+	// Loaded keys are served from the cache
+	loadAll(t, l, []int{1, 2})
+	if len(r.calls()) != 1 {
+		t.Errorf("fetched %d batches after loading cached keys, want 1", len(r.calls()))
+	}
+}
+
+func TestBatcherSplitsAtMaxBatch(t *testing.T) {
+	var r recorder
+	l := newBatcher(r.fetch)
+
+	keys := make([]int, 2*maxBatch+1)
+	for i := range keys {
+		keys[i] = i
+	}
+	loadAll(t, l, keys)
+
+	seen := 0
+	for _, batch := range r.calls() {
+		if len(batch) > maxBatch {
+			t.Errorf("batch of %d keys, want at most %d", len(batch), maxBatch)
+		}
+		seen += len(batch)
+	}
+	if seen != len(keys) {
+		t.Errorf("fetched %d keys, want %d", seen, len(keys))
+	}
+}
+
+func TestBatcherDispatchesFullBatchEarly(t *testing.T) {
+	var r recorder
+	l := newBatcher(r.fetch)
+
+	// A pending batch one key short of full, with no timer to fetch it
+	b := &batch[int, int]{done: make(chan struct{})}
+	for key := 0; key < maxBatch-1; key++ {
+		b.keys = append(b.keys, key)
+		l.loaded[key] = b
+	}
+	l.pending = b
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if value, err := l.load(ctx, maxBatch); err != nil || value != maxBatch*maxBatch {
+		t.Fatalf("load = %d, %v, want the full batch fetched at once", value, err)
+	}
+	if calls := r.calls(); len(calls) != 1 || len(calls[0]) != maxBatch {
+		t.Errorf("batches = %d, want one of %d keys", len(calls), maxBatch)
+	}
+}
+
+func TestBatcherLoadHonoursCancellation(t *testing.T) {
+	release := make(chan struct{})
+	l := newBatcher(func(_ context.Context, keys []int) (map[int]int, error) {
+		<-release
+		return map[int]int{1: 10}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		_, err := l.load(ctx, 1)
+		result <- err
+	}()
+	time.Sleep(2 * batchWait)
+	cancel()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("load = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("load did not return after its context was cancelled")
+	}
+
+	// The fetch still completes for other waiters
+	close(release)
+	if value, err := l.load(context.Background(), 1); err != nil || value != 10 {
+		t.Errorf("load after the fetch = %d, %v, want 10", value, err)
+	}
+}
+
+func TestBatcherSharesFetchErrors(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	l := newBatcher(func(_ context.Context, keys []int) (map[int]int, error) {
+		return nil, errFetch
+	})
+
+	var wg sync.WaitGroup
+	for key := 0; key < 3; key++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			if _, err := l.load(context.Background(), key); !errors.Is(err, errFetch) {
+				t.Errorf("load(%d) = %v, want the fetch error", key, err)
+			}
+		}(key)
+	}
+	wg.Wait()
+}
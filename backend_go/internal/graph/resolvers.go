@@ -0,0 +1,441 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// errInvalidID is returned for an ID argument that is not a row ID
+var errInvalidID = errors.New("invalid id")
+
+// resolver resolves the Query type
+type resolver struct {
+	repo repositories.Repository
+}
+
+type idArgs struct {
+	ID graphql.ID
+}
+
+// parseID returns the row ID of a GraphQL ID
+func parseID(id graphql.ID) (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, errInvalidID
+	}
+	return n, nil
+}
+
+func toID(id int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(id, 10))
+}
+
+func (r *resolver) Word(ctx context.Context, args idArgs) (*wordResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadWord(ctx, id)
+}
+
+func (r *resolver) Words(ctx context.Context, args struct {
+	pageArgs
+	Search *string
+}) (*connection[*wordResolver], error) {
+	page, err := args.request()
+	if err != nil {
+		return nil, err
+	}
+	search := ""
+	if args.Search != nil {
+		search = *args.Search
+	}
+	words, err := r.repo.PageWords(ctx, search, page)
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(words, page, false, wordID, newWordResolver), nil
+}
+
+func (r *resolver) Group(ctx context.Context, args idArgs) (*groupResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadGroup(ctx, id)
+}
+
+func (r *resolver) Groups(ctx context.Context, args pageArgs) (*connection[*groupResolver], error) {
+	page, err := args.request()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := r.repo.PageGroups(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(groups, page, false, groupID, newGroupResolver), nil
+}
+
+func (r *resolver) StudyActivity(ctx context.Context, args idArgs) (*activityResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadActivity(ctx, id)
+}
+
+func (r *resolver) StudyActivities(ctx context.Context, args pageArgs) (*connection[*activityResolver], error) {
+	page, err := args.request()
+	if err != nil {
+		return nil, err
+	}
+	activities, err := r.repo.PageStudyActivities(ctx, nil, page)
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(activities, page, false, activityID, newActivityResolver), nil
+}
+
+func (r *resolver) StudySession(ctx context.Context, args idArgs) (*sessionResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadSession(ctx, id)
+}
+
+func (r *resolver) StudySessions(ctx context.Context, args pageArgs) (*connection[*sessionResolver], error) {
+	page, err := args.request()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := r.repo.PageStudySessions(ctx, "", nil, page)
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(sessions, page, true, sessionID, newSessionResolver), nil
+}
+
+func (r *resolver) Dashboard() *dashboardResolver {
+	return &dashboardResolver{repo: r.repo}
+}
+
+// loadWord returns the word with the given ID through the request's
+// loader, or nil if there is none
+func loadWord(ctx context.Context, id int64) (*wordResolver, error) {
+	word, err := loadersFrom(ctx).words.load(ctx, id)
+	if err != nil || word == nil {
+		return nil, err
+	}
+	return newWordResolver(*word), nil
+}
+
+// loadGroup returns the group with the given ID through the request's
+// loader, or nil if there is none
+func loadGroup(ctx context.Context, id int64) (*groupResolver, error) {
+	group, err := loadersFrom(ctx).groups.load(ctx, id)
+	if err != nil || group == nil {
+		return nil, err
+	}
+	return newGroupResolver(*group), nil
+}
+
+// loadActivity returns the study activity with the given ID through the
+// request's loader, or nil if there is none
+func loadActivity(ctx context.Context, id int64) (*activityResolver, error) {
+	activity, err := loadersFrom(ctx).activities.load(ctx, id)
+	if err != nil || activity == nil {
+		return nil, err
+	}
+	return newActivityResolver(*activity), nil
+}
+
+// loadSession returns the study session with the given ID through the
+// request's loader, or nil if there is none
+func loadSession(ctx context.Context, id int64) (*sessionResolver, error) {
+	session, err := loadersFrom(ctx).sessions.load(ctx, id)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	return newSessionResolver(*session), nil
+}
+
+// loadPage loads one page of a parent's children through a nested
+// connection's batcher
+func loadPage[V any, T any](ctx context.Context, l *batcher[pageKey, []V], parent int64, args pageArgs, desc bool, id func(V) int64, node func(V) T) (*connection[T], error) {
+	page, err := args.request()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := l.load(ctx, pageKey{parent: parent, page: page})
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(rows, page, desc, id, node), nil
+}
+
+// ID and resolver constructors for the rows behind each type
+
+func wordID(w models.Word) int64              { return w.ID }
+func groupID(g models.Group) int64            { return g.ID }
+func activityID(a models.StudyActivity) int64 { return a.ID }
+func sessionID(s models.StudySession) int64   { return s.ID }
+func reviewID(r models.WordReviewItem) int64  { return r.ID }
+func memberWordID(m models.WordGroup) int64   { return m.WordID }
+
+func newWordResolver(w models.Word) *wordResolver {
+	return &wordResolver{word: w}
+}
+
+func newGroupResolver(g models.Group) *groupResolver {
+	return &groupResolver{group: g}
+}
+
+func newActivityResolver(a models.StudyActivity) *activityResolver {
+	return &activityResolver{activity: a}
+}
+
+func newSessionResolver(s models.StudySession) *sessionResolver {
+	return &sessionResolver{session: s}
+}
+
+func newReviewResolver(r models.WordReviewItem) *reviewResolver {
+	return &reviewResolver{review: r}
+}
+
+func newMemberResolver(m models.WordGroup) *wordResolver {
+	return &wordResolver{word: *m.Word}
+}
+
+// wordResolver resolves the Word type
+type wordResolver struct {
+	word models.Word
+}
+
+func (r *wordResolver) ID() graphql.ID {
+	return toID(r.word.ID)
+}
+
+func (r *wordResolver) Arabic() string {
+	return r.word.Arabic
+}
+
+func (r *wordResolver) Romaji() string {
+	return r.word.Romaji
+}
+
+func (r *wordResolver) English() string {
+	return r.word.English
+}
+
+func (r *wordResolver) Parts() *string {
+	if len(r.word.Parts) == 0 {
+		return nil
+	}
+	parts := string(r.word.Parts)
+	return &parts
+}
+
+func (r *wordResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.word.CreatedAt}
+}
+
+func (r *wordResolver) Groups(ctx context.Context) ([]*groupResolver, error) {
+	groups, err := loadersFrom(ctx).wordGroups.load(ctx, r.word.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*groupResolver, len(groups))
+	for i, group := range groups {
+		resolvers[i] = newGroupResolver(group)
+	}
+	return resolvers, nil
+}
+
+func (r *wordResolver) Reviews(ctx context.Context, args pageArgs) (*connection[*reviewResolver], error) {
+	return loadPage(ctx, loadersFrom(ctx).wordReviews, r.word.ID, args, true, reviewID, newReviewResolver)
+}
+
+// groupResolver resolves the Group type
+type groupResolver struct {
+	group models.Group
+}
+
+func (r *groupResolver) ID() graphql.ID {
+	return toID(r.group.ID)
+}
+
+func (r *groupResolver) Name() string {
+	return r.group.Name
+}
+
+func (r *groupResolver) Description() string {
+	return r.group.Description
+}
+
+func (r *groupResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.group.CreatedAt}
+}
+
+func (r *groupResolver) Words(ctx context.Context, args pageArgs) (*connection[*wordResolver], error) {
+	return loadPage(ctx, loadersFrom(ctx).groupWords, r.group.ID, args, false, memberWordID, newMemberResolver)
+}
+
+func (r *groupResolver) StudyActivities(ctx context.Context, args pageArgs) (*connection[*activityResolver], error) {
+	return loadPage(ctx, loadersFrom(ctx).groupActivities, r.group.ID, args, false, activityID, newActivityResolver)
+}
+
+func (r *groupResolver) StudySessions(ctx context.Context, args pageArgs) (*connection[*sessionResolver], error) {
+	return loadPage(ctx, loadersFrom(ctx).groupSessions, r.group.ID, args, true, sessionID, newSessionResolver)
+}
+
+// activityResolver resolves the StudyActivity type
+type activityResolver struct {
+	activity models.StudyActivity
+}
+
+func (r *activityResolver) ID() graphql.ID {
+	return toID(r.activity.ID)
+}
+
+func (r *activityResolver) Group(ctx context.Context) (*groupResolver, error) {
+	return loadGroup(ctx, r.activity.GroupID)
+}
+
+func (r *activityResolver) SessionCount() int32 {
+	return int32(r.activity.ActivityCount)
+}
+
+func (r *activityResolver) ReviewCount() int32 {
+	return int32(r.activity.ReviewCount)
+}
+
+func (r *activityResolver) CorrectCount() int32 {
+	return int32(r.activity.CorrectCount)
+}
+
+func (r *activityResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.activity.CreatedAt}
+}
+
+func (r *activityResolver) StudySessions(ctx context.Context, args pageArgs) (*connection[*sessionResolver], error) {
+	return loadPage(ctx, loadersFrom(ctx).activitySessions, r.activity.ID, args, true, sessionID, newSessionResolver)
+}
+
+// sessionResolver resolves the StudySession type
+type sessionResolver struct {
+	session models.StudySession
+}
+
+func (r *sessionResolver) ID() graphql.ID {
+	return toID(r.session.ID)
+}
+
+func (r *sessionResolver) Activity(ctx context.Context) (*activityResolver, error) {
+	return loadActivity(ctx, r.session.StudyActivityID)
+}
+
+func (r *sessionResolver) Group(ctx context.Context) (*groupResolver, error) {
+	return loadGroup(ctx, r.session.GroupID)
+}
+
+func (r *sessionResolver) WordsReviewed() int32 {
+	return int32(r.session.WordsReviewed)
+}
+
+func (r *sessionResolver) CorrectCount() int32 {
+	return int32(r.session.CorrectCount)
+}
+
+func (r *sessionResolver) WrongCount() int32 {
+	return int32(r.session.WrongCount)
+}
+
+func (r *sessionResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.session.CreatedAt}
+}
+
+func (r *sessionResolver) FinishedAt() *graphql.Time {
+	if r.session.FinishedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.session.FinishedAt}
+}
+
+func (r *sessionResolver) Reviews(ctx context.Context, args pageArgs) (*connection[*reviewResolver], error) {
+	return loadPage(ctx, loadersFrom(ctx).sessionReviews, r.session.ID, args, true, reviewID, newReviewResolver)
+}
+
+// reviewResolver resolves the WordReviewItem type
+type reviewResolver struct {
+	review models.WordReviewItem
+}
+
+func (r *reviewResolver) ID() graphql.ID {
+	return toID(r.review.ID)
+}
+
+func (r *reviewResolver) Word(ctx context.Context) (*wordResolver, error) {
+	return loadWord(ctx, r.review.WordID)
+}
+
+func (r *reviewResolver) Session(ctx context.Context) (*sessionResolver, error) {
+	return loadSession(ctx, r.review.StudySessionID)
+}
+
+func (r *reviewResolver) IsCorrect() bool {
+	return r.review.IsCorrect
+}
+
+func (r *reviewResolver) AnswerText() *string {
+	return r.review.AnswerText
+}
+
+func (r *reviewResolver) Score() *float64 {
+	return r.review.Score
+}
+
+func (r *reviewResolver) ResponseMs() *int32 {
+	return optionalInt(r.review.ResponseMS)
+}
+
+func (r *reviewResolver) Direction() *string {
+	return optionalString(r.review.Direction)
+}
+
+func (r *reviewResolver) Mode() *string {
+	return optionalString(r.review.Mode)
+}
+
+func (r *reviewResolver) HintsUsed() int32 {
+	return int32(r.review.HintsUsed)
+}
+
+func (r *reviewResolver) Confidence() *int32 {
+	return optionalInt(r.review.Confidence)
+}
+
+func (r *reviewResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.review.CreatedAt}
+}
+
+func optionalInt(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/logging"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditContext attributes repository mutations made while handling a
+// request to the X-User-ID caller and the request ID
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := repositories.WithAuditInfo(c.Request.Context(), repositories.AuditInfo{
+			Actor:     c.GetHeader(logging.UserIDHeader),
+			RequestID: logging.GetRequestID(c),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// GetWordHistory returns every recorded change to a word
+func (h *Handler) GetWordHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+		return
+	}
+
+	events, err := h.repo.GetAuditHistory(c.Request.Context(), models.EntityWord, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if len(events) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "word history not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// RevertWord restores a word to a previous version of its history
+func (h *Handler) RevertWord(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	word, err := h.repo.RevertWord(c.Request.Context(), id, version)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, word)
+}
@@ -5,7 +5,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/stats"
 )
 
 type Handler struct {
@@ -24,11 +26,11 @@ type DashboardStats struct {
 	TodayReviews    int64 `json:"today_reviews"`
 }
 
-// GetLastStudySession returns the most recent study session
+// GetLastStudySession returns the requesting learner's most recent study session
 func (h *Handler) GetLastStudySession(c *gin.Context) {
-	session, err := h.repo.GetLastStudySession()
+	session, err := h.repo.GetLastStudySession(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 	if session == nil {
@@ -38,29 +40,56 @@ func (h *Handler) GetLastStudySession(c *gin.Context) {
 	c.JSON(http.StatusOK, session)
 }
 
-// GetStudyProgress returns study progress for the specified number of days
+// GetStudyProgress returns how many words the requesting learner has
+// studied and mastered, broken down by group when by_group=true
 func (h *Handler) GetStudyProgress(c *gin.Context) {
-	daysStr := c.DefaultQuery("days", "30")
-	days, err := strconv.Atoi(daysStr)
+	byGroup, err := strconv.ParseBool(c.DefaultQuery("by_group", "false"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days parameter"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid by_group parameter"})
 		return
 	}
 
-	progress, err := h.repo.GetStudyProgress(days)
+	ctx := c.Request.Context()
+	words, err := h.repo.GetWordProgress(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, progress)
+
+	var groups []models.Group
+	var memberships []models.WordGroup
+	if byGroup {
+		if groups, err = h.repo.GetGroups(ctx); err != nil {
+			internalError(c, err)
+			return
+		}
+		if groups == nil {
+			groups = []models.Group{}
+		}
+		if memberships, err = h.repo.GetGroupMemberships(ctx); err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, stats.Progress(words, groups, memberships))
 }
 
-// GetQuickStats returns dashboard statistics
+// GetQuickStats returns dashboard statistics for the requesting learner
 func (h *Handler) GetQuickStats(c *gin.Context) {
-	stats, err := h.repo.GetQuickStats()
+	ctx := c.Request.Context()
+	quickStats, err := h.repo.GetQuickStats(ctx)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	words, err := h.repo.GetWordProgress(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, stats)
+	quickStats.MasteryPercentage = stats.Progress(words, nil, nil).MasteryPercentage
+
+	c.JSON(http.StatusOK, quickStats)
 }
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/logging"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse is the body of every 4xx and 5xx response
+type ErrorResponse struct {
+	Error string `json:"error"`
+	// RequestID is set on internal errors so they can be found in the logs
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// internalError hands err to the access logger, which records it once with
+// the request context, and responds without leaking repository details
+func internalError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:     "internal server error",
+		RequestID: logging.GetRequestID(c),
+	})
+}
+
+// repositoryError maps known repository errors to client responses and
+// treats anything else as an internal error
+func repositoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidRevert), errors.Is(err, repositories.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		internalError(c, err)
+	}
+}
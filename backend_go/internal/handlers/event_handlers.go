@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/events"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// heartbeatInterval keeps idle streams from being closed by proxies
+const heartbeatInterval = 15 * time.Second
+
+// FinishStudySession marks a study session as finished
+func (h *Handler) FinishStudySession(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	session, err := h.repo.FinishStudySession(c.Request.Context(), id)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// StreamSessionEvents streams a study session's events as Server-Sent
+// Events until the session finishes. A finished session answers 204 so
+// EventSource clients stop reconnecting.
+func (h *Handler) StreamSessionEvents(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	// Subscribe before checking the session, so a finish landing in
+	// between is either seen here or delivered on the stream
+	ch, cancel := subscribe(c, func(event events.Event) bool { return event.SessionID == id })
+	defer cancel()
+
+	session, err := h.repo.GetStudySession(c.Request.Context(), id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if session == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "study session not found"})
+		return
+	}
+	if session.FinishedAt != nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	streamEvents(c, ch, func(event events.Event) bool { return event.Type == events.SessionFinished })
+}
+
+// StreamEvents streams the events of every study session as Server-Sent
+// Events, limited to one group (class) when group_id is set
+func (h *Handler) StreamEvents(c *gin.Context) {
+	var groupID int64
+	if value := c.Query("group_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+			return
+		}
+		groupID = id
+	}
+
+	ch, cancel := subscribe(c, func(event events.Event) bool {
+		return groupID == 0 || event.GroupID == groupID
+	})
+	defer cancel()
+
+	streamEvents(c, ch, nil)
+}
+
+// subscribe subscribes to matching events. Clients resume after a
+// reconnect with the Last-Event-ID header.
+func subscribe(c *gin.Context, filter events.Filter) (<-chan events.Event, func()) {
+	lastID, _ := strconv.ParseInt(c.GetHeader("Last-Event-ID"), 10, 64)
+	return events.Subscribe(filter, lastID)
+}
+
+// streamEvents writes the events from ch to the client until it
+// disconnects, the server shuts down or last reports the final event
+func streamEvents(c *gin.Context, ch <-chan events.Event, last func(events.Event) bool) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-ch:
+			if !ok {
+				return false
+			}
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatInt(event.ID, 10),
+				Event: event.Type,
+				Data:  event,
+			})
+			return last == nil || !last(event)
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": keepalive\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/forecast"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ForecastQueryParams represents query parameters for the forecast endpoint
+type ForecastQueryParams struct {
+	Days      int     `form:"days,default=30" json:"days" binding:"min=1,max=365"`
+	Retention float64 `form:"retention,default=0.9" json:"retention" binding:"min=0.7,max=0.97"`
+	GroupID   int64   `form:"group_id" json:"group_id"`
+}
+
+// GetForecast projects how many reviews will come due per day for the
+// learner and how much of each group they are likely to remember
+func (h *Handler) GetForecast(c *gin.Context) {
+	var params ForecastQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	groups, err := h.repo.GetGroups(ctx)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if params.GroupID > 0 {
+		groups = filterGroups(groups, params.GroupID)
+		if len(groups) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+			return
+		}
+	}
+
+	memberships, err := h.repo.GetGroupMemberships(ctx)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	groupWords := make(map[int64][]int64)
+	for _, membership := range memberships {
+		groupWords[membership.GroupID] = append(groupWords[membership.GroupID], membership.WordID)
+	}
+
+	reviews, err := h.repo.GetLearnerReviews(ctx)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	// The model is fitted on all of the learner's history even when the
+	// projection is limited to one group
+	model, memories := forecast.Fit(reviews)
+	if params.GroupID > 0 {
+		inGroup := make(map[int64]forecast.Memory)
+		for _, id := range groupWords[params.GroupID] {
+			if memory, ok := memories[id]; ok {
+				inGroup[id] = memory
+			}
+		}
+		memories = inGroup
+	}
+
+	now := time.Now().UTC()
+	horizon := now.AddDate(0, 0, params.Days)
+	result := models.Forecast{
+		Days:      params.Days,
+		Retention: params.Retention,
+		Model:     model,
+		Daily:     forecast.Project(memories, model, params.Retention, params.Days, now),
+		Groups:    make([]models.GroupRetention, 0, len(groups)),
+	}
+	for _, group := range groups {
+		result.Groups = append(result.Groups, forecast.GroupRetention(group, groupWords[group.ID], memories, model, now, horizon))
+	}
+	forecast.Summarize(&result)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// filterGroups returns the group with the given id, if present
+func filterGroups(groups []models.Group, id int64) []models.Group {
+	for _, group := range groups {
+		if group.ID == id {
+			return []models.Group{group}
+		}
+	}
+	return nil
+}
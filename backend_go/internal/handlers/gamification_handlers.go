@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/gamification"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyAchievements returns the learner's XP, streak and achievements,
+// locked ones included
+func (h *Handler) GetMyAchievements(c *gin.Context) {
+	summary, err := h.repo.GetAchievements(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	summary.Achievements = gamification.List(summary.Achievements)
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetMyGoals returns the learner's daily goals with today's progress and
+// the last days days (default 7)
+func (h *Handler) GetMyGoals(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 90 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 90"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	settings, err := h.repo.GetGoalSettings(ctx)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	activity, err := h.repo.GetDailyActivity(ctx, days)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gamification.Goals(settings, activity, days, time.Now()))
+}
+
+// SetMyGoals updates the learner's daily goals
+func (h *Handler) SetMyGoals(c *gin.Context) {
+	var settings models.GoalSettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.SetGoalSettings(c.Request.Context(), settings); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	h.GetMyGoals(c)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/grading"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GradeReviewRequest represents a typed answer to be graded by the server
+type GradeReviewRequest struct {
+	WordID         int64  `json:"word_id" binding:"required"`
+	StudySessionID int64  `json:"study_session_id" binding:"required"`
+	Answer         string `json:"answer"`
+	// Direction selects the expected answer (ar-en, en-ar or romaji-ar);
+	// when empty the answer is compared with every form of the word
+	Direction string `json:"direction"`
+	// MaxEdits tightens the length-based edit-distance tolerance; values
+	// above it are ignored
+	MaxEdits   *int `json:"max_edits" binding:"omitempty,min=0"`
+	ResponseMS *int `json:"response_ms" binding:"omitempty,min=0"`
+	HintsUsed  int  `json:"hints_used" binding:"min=0"`
+	Confidence *int `json:"confidence" binding:"omitempty,min=1,max=5"`
+}
+
+// GradeReviewResponse represents a graded answer and the stored review
+type GradeReviewResponse struct {
+	grading.Result
+	Expected []string              `json:"expected"`
+	Review   models.WordReviewItem `json:"review"`
+}
+
+// GradeReview grades a typed answer against a word and records the review
+func (h *Handler) GradeReview(c *gin.Context) {
+	var req GradeReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	word, err := h.repo.GetWordByID(ctx, req.WordID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if word == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "word not found"})
+		return
+	}
+
+	expected, ok := expectedAnswers(word, req.Direction)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "direction must be one of ar-en, en-ar, romaji-ar"})
+		return
+	}
+
+	maxEdits := -1
+	if req.MaxEdits != nil {
+		maxEdits = *req.MaxEdits
+	}
+	result := grading.Grade(req.Answer, expected, maxEdits)
+
+	review := models.WordReviewItem{
+		WordID:         req.WordID,
+		StudySessionID: req.StudySessionID,
+		IsCorrect:      result.Correct,
+		AnswerText:     &req.Answer,
+		Score:          &result.Score,
+		ResponseMS:     req.ResponseMS,
+		Direction:      req.Direction,
+		Mode:           models.ModeTyping,
+		HintsUsed:      req.HintsUsed,
+		Confidence:     req.Confidence,
+	}
+	if err := h.repo.CreateWordReviewItem(ctx, &review); err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, GradeReviewResponse{
+		Result:   result,
+		Expected: expected,
+		Review:   review,
+	})
+}
+
+// expectedAnswers returns the accepted answers for a word in a direction,
+// including synonyms listed in the word's parts
+func expectedAnswers(word *models.Word, direction string) ([]string, bool) {
+	var forms []string
+	switch direction {
+	case models.DirectionArabicToEnglish:
+		forms = append(grading.Alternatives(word.English), word.Synonyms()...)
+	case models.DirectionEnglishToArabic, models.DirectionRomajiToArabic:
+		forms = grading.Alternatives(word.Arabic)
+	case "":
+		forms = append(grading.Alternatives(word.Arabic), grading.Alternatives(word.English)...)
+		forms = append(forms, grading.Alternatives(word.Romaji)...)
+		forms = append(forms, word.Synonyms()...)
+	default:
+		return nil, false
+	}
+	return forms, true
+}
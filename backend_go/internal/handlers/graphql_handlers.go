@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/graph"
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// GraphQLRequest is a GraphQL request as sent over HTTP
+type GraphQLRequest struct {
+	Query         string                 `json:"query" form:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables,omitempty" form:"-"`
+}
+
+// GraphQLResponse documents the body of a GraphQL response
+type GraphQLResponse graphql.Response
+
+// GraphQLHandler serves the GraphQL API
+type GraphQLHandler struct {
+	server *graph.Server
+}
+
+// NewGraphQLHandler creates a new GraphQL handler
+func NewGraphQLHandler(server *graph.Server) *GraphQLHandler {
+	return &GraphQLHandler{server: server}
+}
+
+// Query executes a GraphQL request sent as a JSON body or, with GET, as the
+// query, operationName and variables query parameters. Errors raised while
+// executing are reported in the response body, as GraphQL clients expect.
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req GraphQLRequest
+	if c.Request.Method == http.MethodGet {
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid variables parameter"})
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.server.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables))
+}
+
+// Schema returns the GraphQL schema in SDL
+func (h *GraphQLHandler) Schema(c *gin.Context) {
+	c.String(http.StatusOK, graph.Schema)
+}
@@ -10,9 +10,9 @@ import (
 
 // GetGroups returns all groups
 func (h *Handler) GetGroups(c *gin.Context) {
-	groups, err := h.repo.GetGroups()
+	groups, err := h.repo.GetGroups(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, groups)
@@ -26,9 +26,9 @@ func (h *Handler) GetGroupByID(c *gin.Context) {
 		return
 	}
 
-	group, err := h.repo.GetGroupByID(id)
+	group, err := h.repo.GetGroupByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 	if group == nil {
@@ -47,8 +47,8 @@ func (h *Handler) CreateGroup(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.CreateGroup(&group); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.CreateGroup(c.Request.Context(), &group); err != nil {
+		internalError(c, err)
 		return
 	}
 
@@ -70,8 +70,8 @@ func (h *Handler) UpdateGroup(c *gin.Context) {
 	}
 	group.ID = id
 
-	if err := h.repo.UpdateGroup(&group); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.UpdateGroup(c.Request.Context(), &group); err != nil {
+		repositoryError(c, err)
 		return
 	}
 
@@ -86,10 +86,87 @@ func (h *Handler) DeleteGroup(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteGroup(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.DeleteGroup(c.Request.Context(), id); err != nil {
+		repositoryError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// AddWordToGroupRequest represents the request body for adding a word to a group
+type AddWordToGroupRequest struct {
+	WordID int64 `json:"word_id" binding:"required"`
+}
+
+// AddWordToGroup adds a word to a group
+func (h *Handler) AddWordToGroup(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	var req AddWordToGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.AddWordToGroup(c.Request.Context(), id, req.WordID); err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveWordFromGroup removes a word from a group
+func (h *Handler) RemoveWordFromGroup(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	wordID, err := strconv.ParseInt(c.Param("word_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+		return
+	}
+
+	if err := h.repo.RemoveWordFromGroup(c.Request.Context(), id, wordID); err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetGroupStudySessions returns a group's study sessions with their review tallies
+func (h *Handler) GetGroupStudySessions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repo.GetGroupByID(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+
+	sessions, err := h.repo.GetStudySessionsByGroupID(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/db"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statusUp   = "up"
+	statusDown = "down"
+
+	// pingTimeout bounds how long the readiness probe waits on the database
+	pingTimeout = 2 * time.Second
+)
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	db *sql.DB
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(database *sql.DB) *HealthHandler {
+	return &HealthHandler{db: database}
+}
+
+// Healthz reports that the process is up and serving requests
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, models.HealthStatus{Status: statusUp})
+}
+
+// Readyz reports whether the database, schema and seed data are usable
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	components := map[string]models.ComponentHealth{
+		"database":   h.checkDatabase(c.Request.Context()),
+		"migrations": h.checkMigrations(),
+		"seed":       h.checkSeed(),
+	}
+
+	status, code := statusUp, http.StatusOK
+	for _, component := range components {
+		if component.Status != statusUp {
+			status, code = statusDown, http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(code, models.HealthStatus{Status: status, Components: components})
+}
+
+// checkDatabase pings the database
+func (h *HealthHandler) checkDatabase(ctx context.Context) models.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	if err := h.db.PingContext(ctx); err != nil {
+		return models.ComponentHealth{Status: statusDown, Error: err.Error()}
+	}
+
+	stats := h.db.Stats()
+	return models.ComponentHealth{
+		Status: statusUp,
+		Details: map[string]interface{}{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+		},
+	}
+}
+
+// checkMigrations verifies the schema is at the latest version and not dirty
+func (h *HealthHandler) checkMigrations() models.ComponentHealth {
+	status, err := db.GetMigrationStatus(h.db)
+	if err != nil {
+		return models.ComponentHealth{Status: statusDown, Error: err.Error()}
+	}
+
+	health := models.ComponentHealth{
+		Status: statusUp,
+		Details: map[string]interface{}{
+			"version": status.Version,
+			"latest":  status.Latest,
+			"dirty":   status.Dirty,
+		},
+	}
+	if status.Dirty {
+		health.Status = statusDown
+		health.Error = "database is dirty"
+	} else if status.Version != status.Latest {
+		health.Status = statusDown
+		health.Error = "migrations pending"
+	}
+
+	return health
+}
+
+// checkSeed verifies the seed data has been loaded
+func (h *HealthHandler) checkSeed() models.ComponentHealth {
+	status, err := db.GetSeedStatus(h.db)
+	if err != nil {
+		return models.ComponentHealth{Status: statusDown, Error: err.Error()}
+	}
+
+	health := models.ComponentHealth{
+		Status: statusUp,
+		Details: map[string]interface{}{
+			"words":  status.Words,
+			"groups": status.Groups,
+		},
+	}
+	if status.Words == 0 {
+		health.Status = statusDown
+		health.Error = "seed data not loaded"
+	}
+
+	return health
+}
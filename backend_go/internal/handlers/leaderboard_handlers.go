@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/gamification"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/logging"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAccuracyMinReviews keeps learners with a handful of lucky
+// answers off the top of the accuracy board
+const defaultAccuracyMinReviews = 10
+
+// LeaderboardQueryParams represents query parameters for the leaderboard endpoint
+type LeaderboardQueryParams struct {
+	GroupID    int64  `form:"group_id" json:"group_id"`
+	Period     string `form:"period,default=week" json:"period" binding:"oneof=week all"`
+	Metric     string `form:"metric,default=xp" json:"metric" binding:"oneof=xp words_mastered accuracy"`
+	Limit      int    `form:"limit,default=20" json:"limit" binding:"min=1,max=100"`
+	MinReviews *int   `form:"min_reviews" json:"min_reviews" binding:"omitempty,min=0"`
+}
+
+// GetLeaderboard ranks learners for the current week or all time, for a
+// group or everyone
+func (h *Handler) GetLeaderboard(c *gin.Context) {
+	var params LeaderboardQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if params.GroupID > 0 {
+		group, err := h.repo.GetGroupByID(ctx, params.GroupID)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		if group == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+			return
+		}
+	}
+
+	minReviews := 0
+	if params.Metric == models.MetricAccuracy {
+		minReviews = defaultAccuracyMinReviews
+	}
+	if params.MinReviews != nil {
+		minReviews = *params.MinReviews
+	}
+
+	board := models.Leaderboard{GroupID: params.GroupID, Period: params.Period, Metric: params.Metric}
+	period := models.PeriodAll
+	if params.Period == models.PeriodWeek {
+		board.PeriodStart = gamification.WeekStart(time.Now())
+		period = board.PeriodStart
+	}
+
+	entries, me, err := h.repo.GetLeaderboard(ctx, params.GroupID, period, params.Metric, params.Limit, minReviews)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+	board.Entries = entries
+	board.Me = me
+
+	c.JSON(http.StatusOK, board)
+}
+
+// GetMyProfile returns the learner's leaderboard preferences
+func (h *Handler) GetMyProfile(c *gin.Context) {
+	profile, err := h.repo.GetProfile(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateMyProfile sets the learner's pseudonym and leaderboard opt-out
+func (h *Handler) UpdateMyProfile(c *gin.Context) {
+	if c.GetHeader(logging.UserIDHeader) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": logging.UserIDHeader + " header is required"})
+		return
+	}
+
+	var profile models.LearnerProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateProfile(c.Request.Context(), &profile); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/logging"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/lti"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ltiLoginTTL is how long a platform has to answer a login initiation
+	ltiLoginTTL = 10 * time.Minute
+	// ltiDeepLinkTTL is how long a teacher has to pick a group
+	ltiDeepLinkTTL = time.Hour
+)
+
+// deepLinkPage lets a teacher pick the group a platform link launches
+var deepLinkPage = template.Must(template.New("deep-link").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Choose a group</title></head>
+<body>
+<h1>Choose a group</h1>
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="launch" value="{{.Launch}}">
+{{range .Groups}}<p><label><input type="radio" name="group_id" value="{{.ID}}" required> {{.Name}}</label></p>
+{{else}}<p>There are no groups yet.</p>
+{{end}}<button type="submit">Add</button>
+</form>
+</body>
+</html>
+`))
+
+// autoPostPage posts a signed message back to the platform
+var autoPostPage = template.Must(template.New("auto-post").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Returning to your course</title></head>
+<body onload="document.forms[0].submit()">
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="JWT" value="{{.JWT}}">
+<noscript><button type="submit">Continue</button></noscript>
+</form>
+</body>
+</html>
+`))
+
+// LTIHandler serves the LTI 1.3 tool endpoints
+type LTIHandler struct {
+	repo repositories.Repository
+	tool *lti.Tool
+}
+
+// NewLTIHandler creates a new LTI handler
+func NewLTIHandler(repo repositories.Repository, tool *lti.Tool) *LTIHandler {
+	return &LTIHandler{repo: repo, tool: tool}
+}
+
+// LTIToolConfig lists the URLs a platform needs to register the tool
+type LTIToolConfig struct {
+	LoginURL       string `json:"login_url"`
+	RedirectURL    string `json:"redirect_url"`
+	TargetLinkURL  string `json:"target_link_url"`
+	DeepLinkingURL string `json:"deep_linking_url"`
+	JWKSURL        string `json:"jwks_url"`
+}
+
+// GetToolConfig returns the tool's registration URLs
+func (h *LTIHandler) GetToolConfig(c *gin.Context) {
+	cfg := h.tool.Config()
+	c.JSON(http.StatusOK, LTIToolConfig{
+		LoginURL:       cfg.LoginURL(),
+		RedirectURL:    cfg.LaunchURL(),
+		TargetLinkURL:  cfg.LaunchURL(),
+		DeepLinkingURL: cfg.LaunchURL(),
+		JWKSURL:        cfg.JWKSURL(),
+	})
+}
+
+// GetPlatforms lists the registered platforms
+func (h *LTIHandler) GetPlatforms(c *gin.Context) {
+	platforms, err := h.repo.GetLTIPlatforms(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, platforms)
+}
+
+// CreatePlatform registers a platform
+func (h *LTIHandler) CreatePlatform(c *gin.Context) {
+	var platform models.LTIPlatform
+	if err := c.ShouldBindJSON(&platform); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, raw := range []string{platform.AuthLoginURL, platform.AuthTokenURL, platform.JWKSURL} {
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "auth_login_url, auth_token_url and jwks_url must be absolute http or https URLs"})
+			return
+		}
+	}
+
+	if err := h.repo.CreateLTIPlatform(c.Request.Context(), &platform); err != nil {
+		repositoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, platform)
+}
+
+// DeletePlatform removes a platform
+func (h *LTIHandler) DeletePlatform(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid platform id"})
+		return
+	}
+
+	if err := h.repo.DeleteLTIPlatform(c.Request.Context(), id); err != nil {
+		repositoryError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// JWKS publishes the tool's public key
+func (h *LTIHandler) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.tool.JWKS())
+}
+
+// Login answers a platform's third-party login initiation by sending the
+// browser back to the platform with a fresh state and nonce
+func (h *LTIHandler) Login(c *gin.Context) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	form := c.Request.Form
+	issuer, loginHint := form.Get("iss"), form.Get("login_hint")
+	if issuer == "" || loginHint == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "iss and login_hint are required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	platform, err := h.repo.FindLTIPlatform(ctx, issuer, form.Get("client_id"))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if platform == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown platform"})
+		return
+	}
+
+	state, nonce := lti.NewState(), lti.NewState()
+	if err := h.repo.CreateLTILoginState(ctx, state, nonce, platform.ID, time.Now().Add(ltiLoginTTL)); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	redirect, err := h.tool.LoginRedirect(*platform, loginHint, form.Get("lti_message_hint"), state, nonce)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, redirect)
+}
+
+// Launch validates the id_token a platform posts after login. Resource
+// link launches start a study session for the learner; deep linking
+// launches show the group picker.
+func (h *LTIHandler) Launch(c *gin.Context) {
+	idToken, state := c.PostForm("id_token"), c.PostForm("state")
+	if idToken == "" || state == "" {
+		message := "id_token and state are required"
+		if platformErr := c.PostForm("error"); platformErr != "" {
+			message = "platform error: " + platformErr
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	ctx := c.Request.Context()
+	nonce, platformID, err := h.repo.ConsumeLTILoginState(ctx, state)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown or expired login state"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	platform, err := h.repo.GetLTIPlatform(ctx, platformID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if platform == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown platform"})
+		return
+	}
+
+	claims, err := h.tool.ParseLaunch(ctx, *platform, idToken, nonce)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := models.LTIUser{PlatformID: platform.ID, Subject: claims.Subject, Name: claims.DisplayName(), Email: claims.Email}
+	if err := h.repo.UpsertLTIUser(ctx, &user); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	launch := models.LTILaunch{
+		LaunchKey:    lti.NewState(),
+		PlatformID:   platform.ID,
+		Subject:      claims.Subject,
+		LearnerID:    user.LearnerID,
+		MessageType:  claims.MessageType,
+		DeploymentID: claims.DeploymentID,
+	}
+	if claims.MessageType == lti.MessageDeepLinking {
+		h.startDeepLinking(c, &launch, claims)
+		return
+	}
+	h.startSession(c, &launch, &user, claims)
+}
+
+// startSession opens a study session for a resource link launch in the
+// linked group and activity, then hands the learner to the study app
+func (h *LTIHandler) startSession(c *gin.Context, launch *models.LTILaunch, user *models.LTIUser, claims *lti.Claims) {
+	ctx := c.Request.Context()
+
+	var activity *models.StudyActivity
+	var err error
+	if activityID := claims.CustomInt("activity_id"); activityID > 0 {
+		activity, err = h.repo.GetStudyActivity(ctx, activityID)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		if activity == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "study activity not found"})
+			return
+		}
+	}
+
+	groupID := claims.CustomInt("group_id")
+	if activity != nil {
+		if groupID == 0 {
+			groupID = activity.GroupID
+		} else if activity.GroupID != groupID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "study activity does not belong to the linked group"})
+			return
+		}
+	}
+	group, err := h.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found; link the activity to a group with deep linking or a group_id custom parameter"})
+		return
+	}
+	if activity == nil {
+		activity, err = h.groupActivity(ctx, group.ID)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+	}
+
+	learnerCtx := repositories.WithAuditInfo(ctx, repositories.AuditInfo{Actor: user.LearnerID, RequestID: logging.GetRequestID(c)})
+	session := models.StudySession{StudyActivityID: activity.ID, GroupID: group.ID}
+	if err := h.repo.CreateStudySession(learnerCtx, &session); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	launch.GroupID = group.ID
+	launch.StudyActivityID = activity.ID
+	launch.StudySessionID = session.ID
+	if claims.AGS.CanPostScores() {
+		launch.LineItemURL = claims.AGS.LineItem
+		launch.ScoreStatus = models.LTIScorePending
+	}
+	if err := h.repo.CreateLTILaunch(ctx, launch); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	appURL := h.tool.Config().AppURL
+	if appURL == "" {
+		c.JSON(http.StatusOK, models.LTILaunchResult{LearnerID: user.LearnerID, Name: user.Name, StudySession: &session})
+		return
+	}
+	redirect, err := url.Parse(appURL)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	query := redirect.Query()
+	query.Set("learner_id", user.LearnerID)
+	query.Set("group_id", strconv.FormatInt(group.ID, 10))
+	query.Set("study_activity_id", strconv.FormatInt(activity.ID, 10))
+	query.Set("study_session_id", strconv.FormatInt(session.ID, 10))
+	redirect.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, redirect.String())
+}
+
+// startDeepLinking remembers a deep linking request and shows the teacher
+// the groups to choose from
+func (h *LTIHandler) startDeepLinking(c *gin.Context, launch *models.LTILaunch, claims *lti.Claims) {
+	ctx := c.Request.Context()
+	launch.DeepLinkReturnURL = claims.DeepLinking.ReturnURL
+	launch.DeepLinkData = claims.DeepLinking.Data
+	if err := h.repo.CreateLTILaunch(ctx, launch); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	groups, err := h.repo.GetGroups(ctx)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	renderHTML(c, deepLinkPage, gin.H{
+		"Action": h.tool.Config().BaseURL + "/lti/deep-link",
+		"Launch": launch.LaunchKey,
+		"Groups": groups,
+	})
+}
+
+// DeepLink returns the group the teacher picked to the platform as an
+// LTI resource link with a 100-point gradebook column
+func (h *LTIHandler) DeepLink(c *gin.Context) {
+	groupID, err := strconv.ParseInt(c.PostForm("group_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+
+	// The launch is used up here, so a request posted twice cannot sign a
+	// second response
+	launch, err := h.repo.ConsumeLTIDeepLink(ctx, c.PostForm("launch"), ltiDeepLinkTTL)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown, used or expired deep linking request"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	platform, err := h.repo.GetLTIPlatform(ctx, launch.PlatformID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if platform == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown platform"})
+		return
+	}
+	activity, err := h.groupActivity(ctx, group.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	jwt, err := h.tool.DeepLinkResponse(*platform, launch.DeploymentID, launch.DeepLinkData, []lti.ContentItem{{
+		Type:  "ltiResourceLink",
+		Title: group.Name,
+		Text:  group.Description,
+		URL:   h.tool.Config().LaunchURL(),
+		Custom: map[string]string{
+			"group_id":    strconv.FormatInt(group.ID, 10),
+			"activity_id": strconv.FormatInt(activity.ID, 10),
+		},
+		LineItem: &lti.LineItem{Label: group.Name, ScoreMaximum: 100},
+	}})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	renderHTML(c, autoPostPage, gin.H{"Action": launch.DeepLinkReturnURL, "JWT": jwt})
+}
+
+// renderHTML writes a page, rendering it fully first so a template error
+// becomes an error response instead of a truncated page
+func renderHTML(c *gin.Context, page *template.Template, data interface{}) {
+	var buf bytes.Buffer
+	if err := page.Execute(&buf, data); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// groupActivity returns the group's first study activity, creating one if
+// it has none
+func (h *LTIHandler) groupActivity(ctx context.Context, groupID int64) (*models.StudyActivity, error) {
+	activities, err := h.repo.GetStudyActivities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range activities {
+		if activities[i].GroupID == groupID {
+			return &activities[i], nil
+		}
+	}
+
+	activity := models.StudyActivity{GroupID: groupID}
+	if err := h.repo.CreateStudyActivity(ctx, &activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
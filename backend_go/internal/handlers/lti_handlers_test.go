@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/db"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/jobs"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/lti"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TestMain runs the tests from the module root, where InitDB finds the
+// migrations and seed data, without log output
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	gin.SetMode(gin.TestMode)
+	if err := os.Chdir(filepath.Join("..", "..")); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+const (
+	platformKeyID    = "platform-key"
+	platformClientID = "tool-client"
+	platformSubject  = "student-1"
+	accessTokenValue = "access-token"
+)
+
+// mockPlatform is an LMS serving its signing keys, an OAuth2 token
+// endpoint and a gradebook that records the scores posted to it
+type mockPlatform struct {
+	t        *testing.T
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	toolKey  *rsa.PublicKey
+	platform models.LTIPlatform
+	scores   chan lti.Score
+}
+
+func newMockPlatform(t *testing.T, tool *lti.Tool) *mockPlatform {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &mockPlatform{t: t, key: key, toolKey: decodeJWK(t, tool.JWKS().Keys[0]), scores: make(chan lti.Score, 1)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/token", p.token)
+	mux.HandleFunc("/lineitems/1/scores", p.score)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	p.platform = models.LTIPlatform{
+		Name:         "Mock LMS",
+		Issuer:       "https://lms.example.com",
+		ClientID:     platformClientID,
+		DeploymentID: "deployment-1",
+		AuthLoginURL: p.server.URL + "/auth",
+		AuthTokenURL: p.server.URL + "/token",
+		JWKSURL:      p.server.URL + "/jwks",
+	}
+	return p
+}
+
+func (p *mockPlatform) jwks(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(lti.JWKS{Keys: []lti.JWK{{
+		Kty: "RSA",
+		Kid: platformKeyID,
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+	}}})
+}
+
+// token grants an access token to a client assertion signed by the tool
+func (p *mockPlatform) token(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != lti.ScopeScore {
+		http.Error(w, "unsupported grant", http.StatusBadRequest)
+		return
+	}
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(r.FormValue("client_assertion"), &claims, func(*jwt.Token) (interface{}, error) {
+		return p.toolKey, nil
+	}, jwt.WithIssuer(platformClientID), jwt.WithAudience(p.platform.AuthTokenURL))
+	if err != nil || claims.Subject != platformClientID {
+		http.Error(w, "invalid client assertion", http.StatusUnauthorized)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"access_token": accessTokenValue, "expires_in": 3600})
+}
+
+func (p *mockPlatform) score(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+accessTokenValue {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("Content-Type") != "application/vnd.ims.lis.v1.score+json" {
+		http.Error(w, "unsupported media type", http.StatusUnsupportedMediaType)
+		return
+	}
+	var score lti.Score
+	if err := json.NewDecoder(r.Body).Decode(&score); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.scores <- score
+	w.WriteHeader(http.StatusOK)
+}
+
+// claims returns resource link launch claims for the login's nonce
+func (p *mockPlatform) claims(nonce string) *lti.Claims {
+	now := time.Now()
+	return &lti.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.platform.Issuer,
+			Subject:   platformSubject,
+			Audience:  jwt.ClaimStrings{platformClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		},
+		Nonce:        nonce,
+		Name:         "Student One",
+		MessageType:  lti.MessageResourceLink,
+		Version:      lti.Version,
+		DeploymentID: p.platform.DeploymentID,
+	}
+}
+
+// sign signs launch claims with key, as the platform would with its own
+func (p *mockPlatform) sign(claims *lti.Claims, key *rsa.PrivateKey) string {
+	p.t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = platformKeyID
+	signed, err := token.SignedString(key)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return signed
+}
+
+// decodeJWK returns the public key of an RSA JWK
+func decodeJWK(t *testing.T, jwk lti.JWK) *rsa.PublicKey {
+	t.Helper()
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+}
+
+// ltiTest is the tool's LTI endpoints over a fresh database, registered
+// with a mock platform
+type ltiTest struct {
+	t        *testing.T
+	repo     *repositories.SQLiteRepository
+	tool     *lti.Tool
+	router   *gin.Engine
+	platform *mockPlatform
+}
+
+func newLTITest(t *testing.T) *ltiTest {
+	t.Helper()
+	conn, err := db.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	repo := repositories.NewSQLiteRepository(conn)
+
+	kid, key, err := lti.LoadKey(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("LoadKey: %v", err)
+	}
+	tool := lti.NewTool(lti.Config{BaseURL: "http://tool.example.com"}, kid, key)
+	platform := newMockPlatform(t, tool)
+	if err := repo.CreateLTIPlatform(context.Background(), &platform.platform); err != nil {
+		t.Fatalf("CreateLTIPlatform: %v", err)
+	}
+
+	h := NewLTIHandler(repo, tool)
+	router := gin.New()
+	router.POST("/lti/login", h.Login)
+	router.POST("/lti/launch", h.Launch)
+	router.POST("/lti/deep-link", h.DeepLink)
+	return &ltiTest{t: t, repo: repo, tool: tool, router: router, platform: platform}
+}
+
+// post sends a form to the tool
+func (lt *ltiTest) post(path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	lt.router.ServeHTTP(w, req)
+	return w
+}
+
+// login initiates a login and returns the state and nonce the tool sends
+// to the platform
+func (lt *ltiTest) login() (state, nonce string) {
+	lt.t.Helper()
+	w := lt.post("/lti/login", url.Values{
+		"iss":        {lt.platform.platform.Issuer},
+		"login_hint": {platformSubject},
+		"client_id":  {platformClientID},
+	})
+	if w.Code != http.StatusFound {
+		lt.t.Fatalf("login: status %d: %s", w.Code, w.Body)
+	}
+	redirect, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		lt.t.Fatal(err)
+	}
+	query := redirect.Query()
+	if query.Get("client_id") != platformClientID || query.Get("redirect_uri") != lt.tool.Config().LaunchURL() {
+		lt.t.Fatalf("login redirected to %s", redirect)
+	}
+	return query.Get("state"), query.Get("nonce")
+}
+
+// launch posts a signed id_token for a login's state
+func (lt *ltiTest) launch(idToken, state string) *httptest.ResponseRecorder {
+	return lt.post("/lti/launch", url.Values{"id_token": {idToken}, "state": {state}})
+}
+
+// launchSession logs in and launches a resource link with claims changed
+// by edit, returning the started session
+func (lt *ltiTest) launchSession(edit func(*lti.Claims)) models.LTILaunchResult {
+	lt.t.Helper()
+	state, nonce := lt.login()
+	claims := lt.platform.claims(nonce)
+	edit(claims)
+	w := lt.launch(lt.platform.sign(claims, lt.platform.key), state)
+	if w.Code != http.StatusOK {
+		lt.t.Fatalf("launch: status %d: %s", w.Code, w.Body)
+	}
+	var result models.LTILaunchResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		lt.t.Fatal(err)
+	}
+	return result
+}
+
+func TestLTILoginAndLaunch(t *testing.T) {
+	lt := newLTITest(t)
+
+	state, nonce := lt.login()
+	claims := lt.platform.claims(nonce)
+	claims.Custom = map[string]interface{}{"group_id": "1"}
+	idToken := lt.platform.sign(claims, lt.platform.key)
+	w := lt.launch(idToken, state)
+	if w.Code != http.StatusOK {
+		t.Fatalf("launch: status %d: %s", w.Code, w.Body)
+	}
+	var result models.LTILaunchResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	wantLearner := "lti-" + strconv.FormatInt(lt.platform.platform.ID, 10) + "-" + platformSubject
+	if result.LearnerID != wantLearner || result.Name != "Student One" {
+		t.Errorf("launched %q (%q), want %q", result.LearnerID, result.Name, wantLearner)
+	}
+	if result.StudySession == nil || result.StudySession.ID == 0 || result.StudySession.GroupID != 1 {
+		t.Fatalf("launch started %+v, want a session in group 1", result.StudySession)
+	}
+
+	if w := lt.launch(idToken, state); w.Code != http.StatusBadRequest {
+		t.Errorf("replayed state: status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name string
+		edit func(*lti.Claims)
+		key  *rsa.PrivateKey
+		want int
+	}{
+		{name: "bad nonce", edit: func(c *lti.Claims) { c.Nonce = "other" }, want: http.StatusUnauthorized},
+		{name: "bad issuer", edit: func(c *lti.Claims) { c.Issuer = "https://evil.example.com" }, want: http.StatusUnauthorized},
+		{name: "bad audience", edit: func(c *lti.Claims) { c.Audience = jwt.ClaimStrings{"other-tool"} }, want: http.StatusUnauthorized},
+		{name: "expired", edit: func(c *lti.Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }, want: http.StatusUnauthorized},
+		{name: "foreign key", key: otherKey, want: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			state, nonce := lt.login()
+			claims := lt.platform.claims(nonce)
+			if tc.edit != nil {
+				tc.edit(claims)
+			}
+			key := lt.platform.key
+			if tc.key != nil {
+				key = tc.key
+			}
+			if w := lt.launch(lt.platform.sign(claims, key), state); w.Code != tc.want {
+				t.Errorf("status %d, want %d: %s", w.Code, tc.want, w.Body)
+			}
+		})
+	}
+
+	t.Run("bad state", func(t *testing.T) {
+		_, nonce := lt.login()
+		idToken := lt.platform.sign(lt.platform.claims(nonce), lt.platform.key)
+		if w := lt.launch(idToken, "unknown"); w.Code != http.StatusBadRequest {
+			t.Errorf("status %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("activity of another group", func(t *testing.T) {
+		activity := models.StudyActivity{GroupID: 2}
+		if err := lt.repo.CreateStudyActivity(context.Background(), &activity); err != nil {
+			t.Fatal(err)
+		}
+		state, nonce := lt.login()
+		claims := lt.platform.claims(nonce)
+		claims.Custom = map[string]interface{}{"group_id": "1", "activity_id": strconv.FormatInt(activity.ID, 10)}
+		if w := lt.launch(lt.platform.sign(claims, lt.platform.key), state); w.Code != http.StatusBadRequest {
+			t.Errorf("status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body)
+		}
+	})
+}
+
+var (
+	launchField = regexp.MustCompile(`name="launch" value="([^"]+)"`)
+	jwtField    = regexp.MustCompile(`name="JWT" value="([^"]+)"`)
+	formAction  = regexp.MustCompile(`action="([^"]+)"`)
+)
+
+// deepLinkingResponse is the deep linking response the tool signs
+type deepLinkingResponse struct {
+	jwt.RegisteredClaims
+	MessageType  string            `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	DeploymentID string            `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	ContentItems []lti.ContentItem `json:"https://purl.imsglobal.org/spec/lti-dl/claim/content_items"`
+	Data         string            `json:"https://purl.imsglobal.org/spec/lti-dl/claim/data"`
+}
+
+func TestLTIDeepLinking(t *testing.T) {
+	lt := newLTITest(t)
+	returnURL := lt.platform.server.URL + "/deep-link-return"
+
+	state, nonce := lt.login()
+	claims := lt.platform.claims(nonce)
+	claims.MessageType = lti.MessageDeepLinking
+	claims.DeepLinking = &lti.DeepLinkingSettings{ReturnURL: returnURL, AcceptTypes: []string{"ltiResourceLink"}, Data: "opaque"}
+	w := lt.launch(lt.platform.sign(claims, lt.platform.key), state)
+	if w.Code != http.StatusOK {
+		t.Fatalf("launch: status %d: %s", w.Code, w.Body)
+	}
+	match := launchField.FindStringSubmatch(w.Body.String())
+	if match == nil {
+		t.Fatalf("group picker has no launch field: %s", w.Body)
+	}
+	pick := url.Values{"launch": {match[1]}, "group_id": {"2"}}
+
+	w = lt.post("/lti/deep-link", pick)
+	if w.Code != http.StatusOK {
+		t.Fatalf("deep link: status %d: %s", w.Code, w.Body)
+	}
+	if action := formAction.FindStringSubmatch(w.Body.String()); action == nil || action[1] != returnURL {
+		t.Errorf("response posts to %v, want %s", action, returnURL)
+	}
+	match = jwtField.FindStringSubmatch(w.Body.String())
+	if match == nil {
+		t.Fatalf("response page has no JWT: %s", w.Body)
+	}
+
+	var response deepLinkingResponse
+	_, err := jwt.ParseWithClaims(match[1], &response, func(*jwt.Token) (interface{}, error) {
+		return lt.platform.toolKey, nil
+	}, jwt.WithIssuer(platformClientID), jwt.WithAudience(lt.platform.platform.Issuer))
+	if err != nil {
+		t.Fatalf("deep linking response: %v", err)
+	}
+	if response.MessageType != lti.MessageDeepLinkingResponse || response.Data != "opaque" || response.DeploymentID != "deployment-1" {
+		t.Errorf("response = %+v", response)
+	}
+	if len(response.ContentItems) != 1 || response.ContentItems[0].Custom["group_id"] != "2" {
+		t.Fatalf("content items = %+v, want a link to group 2", response.ContentItems)
+	}
+	item := response.ContentItems[0]
+
+	if w := lt.post("/lti/deep-link", pick); w.Code != http.StatusBadRequest {
+		t.Errorf("replayed deep link: status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// The platform launches the link it was given
+	result := lt.launchSession(func(c *lti.Claims) {
+		c.Custom = map[string]interface{}{"group_id": item.Custom["group_id"], "activity_id": item.Custom["activity_id"]}
+	})
+	if result.StudySession.GroupID != 2 || strconv.FormatInt(result.StudySession.StudyActivityID, 10) != item.Custom["activity_id"] {
+		t.Errorf("link started %+v, want activity %s in group 2", result.StudySession, item.Custom["activity_id"])
+	}
+}
+
+func TestLTIScorePost(t *testing.T) {
+	lt := newLTITest(t)
+	result := lt.launchSession(func(c *lti.Claims) {
+		c.Custom = map[string]interface{}{"group_id": "1"}
+		c.AGS = &lti.AGSEndpoint{Scope: []string{lti.ScopeScore}, LineItem: lt.platform.server.URL + "/lineitems/1"}
+	})
+
+	learner := repositories.WithAuditInfo(context.Background(), repositories.AuditInfo{Actor: result.LearnerID})
+	for i, correct := range []bool{true, true, false, true} {
+		review := models.WordReviewItem{StudySessionID: result.StudySession.ID, WordID: int64(i + 1), IsCorrect: correct}
+		if err := lt.repo.CreateWordReviewItem(learner, &review); err != nil {
+			t.Fatalf("CreateWordReviewItem: %v", err)
+		}
+	}
+	if _, err := lt.repo.FinishStudySession(learner, result.StudySession.ID); err != nil {
+		t.Fatalf("FinishStudySession: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jobs.NewLTIScorePoster(lt.repo, lt.tool).Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case score := <-lt.platform.scores:
+		if score.UserID != platformSubject || score.ScoreGiven != 75 || score.ScoreMaximum != 100 ||
+			score.ActivityProgress != "Completed" || score.GradingProgress != "FullyGraded" {
+			t.Errorf("posted score = %+v, want 75/100 for %s", score, platformSubject)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no score posted")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		pending, err := lt.repo.GetPendingLTIScores(context.Background(), 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("score still pending after posting: %+v", pending)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/quiz"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateQuizRequest represents the request body for generating a quiz
+type CreateQuizRequest struct {
+	GroupID         int64  `json:"group_id" binding:"required"`
+	Direction       string `json:"direction" binding:"required"`
+	Size            int    `json:"size"`
+	Choices         int    `json:"choices"`
+	StudyActivityID *int64 `json:"study_activity_id"`
+}
+
+// AnswerQuizRequest represents the request body for answering quiz questions
+type AnswerQuizRequest struct {
+	Answers []models.QuizAnswer `json:"answers" binding:"required,min=1,dive"`
+}
+
+// CreateQuiz generates a multiple-choice quiz for a group
+func (h *Handler) CreateQuiz(c *gin.Context) {
+	var req CreateQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !quiz.ValidDirection(req.Direction) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "direction must be one of ar-en, en-ar, romaji-ar"})
+		return
+	}
+	if req.Size < 1 || req.Size > quiz.MaxSize {
+		req.Size = quiz.DefaultSize
+	}
+	if req.Choices < 2 || req.Choices > quiz.MaxChoices {
+		req.Choices = quiz.DefaultChoices
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repo.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+
+	candidates, err := h.repo.GetQuizCandidates(ctx, req.GroupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	confusions, err := h.repo.GetWordConfusions(ctx, req.GroupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	questions, err := quiz.Generate(candidates, confusions, quiz.Options{
+		Size:      req.Size,
+		Choices:   req.Choices,
+		Direction: req.Direction,
+	}, rng)
+	if errors.Is(err, quiz.ErrNotEnoughWords) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	generated := models.Quiz{
+		GroupID:   req.GroupID,
+		Direction: req.Direction,
+		Questions: questions,
+	}
+	if err := h.repo.CreateQuiz(ctx, &generated, req.StudyActivityID); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, generated)
+}
+
+// AnswerQuiz grades answers to a quiz and records them as word reviews
+func (h *Handler) AnswerQuiz(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid quiz id"})
+		return
+	}
+
+	var req AnswerQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.repo.AnswerQuiz(c.Request.Context(), id, req.Answers)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.QuizResults{Results: results})
+}
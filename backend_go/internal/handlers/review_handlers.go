@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
 )
 
@@ -16,9 +19,9 @@ func (h *Handler) GetWordReviewItems(c *gin.Context) {
 		return
 	}
 
-	reviews, err := h.repo.GetWordReviewItems(sessionID)
+	reviews, err := h.repo.GetWordReviewItems(c.Request.Context(), sessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -33,10 +36,119 @@ func (h *Handler) CreateWordReviewItem(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.CreateWordReviewItem(&review); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.CreateWordReviewItem(c.Request.Context(), &review); err != nil {
+		repositoryError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, review)
 }
+
+// IdempotencyKeyHeader lets clients safely retry a batch submission
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// StudySessionAction routes custom methods on a study session such as
+// reviews:batch. Gin cannot match a colon inside a static path segment, so
+// the whole segment is captured and dispatched here.
+func (h *Handler) StudySessionAction(c *gin.Context) {
+	switch c.Param("action") {
+	case "reviews:batch":
+		h.CreateReviewBatch(c)
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown study session action"})
+	}
+}
+
+// CreateReviewBatch records many reviews for a study session at once and
+// reports the outcome of each item. Retries are de-duplicated by the
+// Idempotency-Key header or by per-item client_id.
+func (h *Handler) CreateReviewBatch(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if len(key) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "idempotency key is too long"})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var batch models.ReviewBatch
+	if err := binding.JSON.BindBody(body, &batch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	result, replayed, err := h.repo.CreateReviewBatch(c.Request.Context(), sessionID, key, hex.EncodeToString(hash[:]), batch.Items)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	if replayed {
+		c.Header("Idempotent-Replayed", "true")
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSessionReviewAnalytics summarises accuracy, timing, hints and confidence
+// for the reviews of a study session
+func (h *Handler) GetSessionReviewAnalytics(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("session_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	analytics, err := h.repo.GetSessionReviewAnalytics(c.Request.Context(), sessionID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetWordReviews returns every review of a word, newest first
+func (h *Handler) GetWordReviews(c *gin.Context) {
+	wordID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+		return
+	}
+
+	reviews, err := h.repo.GetWordReviewItemsByWord(c.Request.Context(), wordID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// GetWordReviewAnalytics summarises accuracy, timing, hints and confidence
+// for the reviews of a word
+func (h *Handler) GetWordReviewAnalytics(c *gin.Context) {
+	wordID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+		return
+	}
+
+	analytics, err := h.repo.GetWordReviewAnalytics(c.Request.Context(), wordID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
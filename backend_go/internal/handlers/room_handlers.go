@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/logging"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/quiz"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/rooms"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Room question timing limits, in seconds
+const (
+	defaultQuestionSeconds = 20
+	minQuestionSeconds     = 5
+	maxQuestionSeconds     = 120
+	maxPlayerNameLength    = 40
+)
+
+// CreateRoomRequest represents the request body for opening a quiz room
+type CreateRoomRequest struct {
+	GroupID         int64  `json:"group_id" binding:"required"`
+	StudyActivityID int64  `json:"study_activity_id" binding:"required"`
+	Direction       string `json:"direction" binding:"required"`
+	Size            int    `json:"size"`
+	Choices         int    `json:"choices"`
+	QuestionSeconds int    `json:"question_seconds"`
+}
+
+// RoomHandler serves live multiplayer quiz rooms
+type RoomHandler struct {
+	repo     repositories.Repository
+	hub      *rooms.Hub
+	upgrader websocket.Upgrader
+}
+
+// NewRoomHandler creates a new room handler. Browsers may open room
+// WebSockets from the server's own origin or one of allowedOrigins.
+func NewRoomHandler(repo repositories.Repository, hub *rooms.Hub, allowedOrigins []string) *RoomHandler {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimRight(origin, "/"))] = true
+	}
+	return &RoomHandler{
+		repo: repo,
+		hub:  hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					return true
+				}
+				if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+					return true
+				}
+				return allowed[strings.ToLower(origin)]
+			},
+		},
+	}
+}
+
+// AllowedOriginsFromEnv reads the origins other than the server's own that
+// may open room WebSockets from ALLOWED_ORIGINS, a comma-separated list
+func AllowedOriginsFromEnv() []string {
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// CreateRoom opens a quiz room for a group and returns its join code and
+// the host token
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	var req CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !quiz.ValidDirection(req.Direction) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "direction must be one of ar-en, en-ar, romaji-ar"})
+		return
+	}
+	if req.Size < 1 || req.Size > quiz.MaxSize {
+		req.Size = quiz.DefaultSize
+	}
+	if req.Choices < 2 || req.Choices > quiz.MaxChoices {
+		req.Choices = quiz.DefaultChoices
+	}
+	if req.QuestionSeconds == 0 {
+		req.QuestionSeconds = defaultQuestionSeconds
+	}
+	if req.QuestionSeconds < minQuestionSeconds || req.QuestionSeconds > maxQuestionSeconds {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question_seconds must be between 5 and 120"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repo.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+	activity, err := h.repo.GetStudyActivity(ctx, req.StudyActivityID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if activity == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "study activity not found"})
+		return
+	}
+
+	candidates, err := h.repo.GetQuizCandidates(ctx, req.GroupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	confusions, err := h.repo.GetWordConfusions(ctx, req.GroupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	questions, err := quiz.Generate(candidates, confusions, quiz.Options{
+		Size:      req.Size,
+		Choices:   req.Choices,
+		Direction: req.Direction,
+	}, rng)
+	if errors.Is(err, quiz.ErrNotEnoughWords) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	room, err := h.hub.Create(rooms.Config{
+		GroupID:         req.GroupID,
+		StudyActivityID: req.StudyActivityID,
+		Direction:       req.Direction,
+		QuestionSeconds: req.QuestionSeconds,
+		Questions:       questions,
+	})
+	if errors.Is(err, rooms.ErrTooManyRooms) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	snapshot, ok := room.Snapshot()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
+		return
+	}
+	c.JSON(http.StatusCreated, models.RoomCreated{Room: snapshot, HostToken: room.HostToken()})
+}
+
+// GetRoom returns a room's status and scoreboard
+func (h *RoomHandler) GetRoom(c *gin.Context) {
+	room, ok := h.hub.Get(strings.ToUpper(c.Param("code")))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
+		return
+	}
+
+	snapshot, ok := room.Snapshot()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// JoinRoom upgrades to a WebSocket on a room. The host connects with
+// ?token=; players connect with ?name= and are identified by the
+// X-User-ID header or, for browsers that cannot set it, ?learner_id=.
+func (h *RoomHandler) JoinRoom(c *gin.Context) {
+	room, ok := h.hub.Get(strings.ToUpper(c.Param("code")))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
+		return
+	}
+
+	host := false
+	if token := c.Query("token"); token != "" {
+		if !room.IsHost(token) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid host token"})
+			return
+		}
+		host = true
+	}
+
+	name := strings.TrimSpace(c.Query("name"))
+	learner := c.GetHeader(logging.UserIDHeader)
+	if learner == "" {
+		learner = c.Query("learner_id")
+	}
+	if !host {
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			return
+		}
+		if len(name) > maxPlayerNameLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name must be at most 40 characters"})
+			return
+		}
+		if learner == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "X-User-ID header or learner_id is required"})
+			return
+		}
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written the error response
+		return
+	}
+	room.Serve(conn, host, learner, name)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+// DueQueueQueryParams represents query parameters for the due queue endpoint
+type DueQueueQueryParams struct {
+	GroupID int64 `form:"group_id" json:"group_id"`
+	Limit   int   `form:"limit,default=20" json:"limit" binding:"min=1,max=100"`
+}
+
+// GetDueWords returns the words the learner should study next under
+// whichever scheduler is in effect for them
+func (h *Handler) GetDueWords(c *gin.Context) {
+	var params DueQueueQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	choice, err := h.schedulerChoice(ctx, params.GroupID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	words, err := h.repo.GetDueWords(ctx, choice.Scheduler, params.GroupID, params.Limit)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DueQueue{SchedulerChoice: choice, Words: words})
+}
+
+// GetMyScheduler returns the scheduler in effect for the learner
+func (h *Handler) GetMyScheduler(c *gin.Context) {
+	choice, err := h.schedulerChoice(c.Request.Context(), 0)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, choice)
+}
+
+// SetMyScheduler chooses the learner's scheduler, overriding group choices
+func (h *Handler) SetMyScheduler(c *gin.Context) {
+	var setting models.SchedulerSetting
+	if err := c.ShouldBindJSON(&setting); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.repo.SetLearnerScheduler(ctx, setting.Scheduler); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	h.GetMyScheduler(c)
+}
+
+// GetGroupScheduler returns the scheduler in effect for a group's learners
+// who have not chosen their own
+func (h *Handler) GetGroupScheduler(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repo.GetGroupByID(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+
+	choice, err := h.groupSchedulerChoice(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, choice)
+}
+
+// SetGroupScheduler chooses the scheduler for a group
+func (h *Handler) SetGroupScheduler(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	var setting models.SchedulerSetting
+	if err := c.ShouldBindJSON(&setting); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.repo.SetGroupScheduler(ctx, id, setting.Scheduler); err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	choice, err := h.groupSchedulerChoice(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, choice)
+}
+
+// schedulerChoice resolves the learner's scheduler: their own choice, then
+// the group's when studying a group, then the server default
+func (h *Handler) schedulerChoice(ctx context.Context, groupID int64) (models.SchedulerChoice, error) {
+	name, err := h.repo.GetLearnerScheduler(ctx)
+	if err != nil {
+		return models.SchedulerChoice{}, err
+	}
+	if _, ok := scheduler.Get(name); ok {
+		return models.SchedulerChoice{Scheduler: name, Source: models.SchedulerSourceLearner}, nil
+	}
+
+	if groupID > 0 {
+		return h.groupSchedulerChoice(ctx, groupID)
+	}
+	return models.SchedulerChoice{Scheduler: scheduler.Default().Name(), Source: models.SchedulerSourceDefault}, nil
+}
+
+// groupSchedulerChoice resolves a group's scheduler, falling back to the
+// server default
+func (h *Handler) groupSchedulerChoice(ctx context.Context, groupID int64) (models.SchedulerChoice, error) {
+	name, err := h.repo.GetGroupScheduler(ctx, groupID)
+	if err != nil {
+		return models.SchedulerChoice{}, err
+	}
+	if _, ok := scheduler.Get(name); ok {
+		return models.SchedulerChoice{Scheduler: name, Source: models.SchedulerSourceGroup}, nil
+	}
+	return models.SchedulerChoice{Scheduler: scheduler.Default().Name(), Source: models.SchedulerSourceDefault}, nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/scheduler"
+)
+
+// schedulerSettings is a repository holding one learner and group
+// scheduler setting; its other methods are not used
+type schedulerSettings struct {
+	repositories.Repository
+	learner string
+	groups  map[int64]string
+}
+
+func (s schedulerSettings) GetLearnerScheduler(context.Context) (string, error) {
+	return s.learner, nil
+}
+
+func (s schedulerSettings) GetGroupScheduler(_ context.Context, groupID int64) (string, error) {
+	return s.groups[groupID], nil
+}
+
+func TestSchedulerChoice(t *testing.T) {
+	cfg := scheduler.DefaultConfig()
+	cfg.Default = models.SchedulerLeitner
+	scheduler.Init(cfg)
+	t.Cleanup(func() { scheduler.Init(scheduler.DefaultConfig()) })
+
+	groups := map[int64]string{1: models.SchedulerSM2, 2: "retired"}
+	tests := []struct {
+		name    string
+		learner string
+		groupID int64
+		want    models.SchedulerChoice
+	}{
+		{"learner choice", models.SchedulerSM2, 0, models.SchedulerChoice{Scheduler: models.SchedulerSM2, Source: models.SchedulerSourceLearner}},
+		{"learner choice overrides the group", models.SchedulerLeitner, 1, models.SchedulerChoice{Scheduler: models.SchedulerLeitner, Source: models.SchedulerSourceLearner}},
+		{"group choice", "", 1, models.SchedulerChoice{Scheduler: models.SchedulerSM2, Source: models.SchedulerSourceGroup}},
+		{"unknown learner choice falls back to the group", "retired", 1, models.SchedulerChoice{Scheduler: models.SchedulerSM2, Source: models.SchedulerSourceGroup}},
+		{"unknown group choice", "", 2, models.SchedulerChoice{Scheduler: models.SchedulerLeitner, Source: models.SchedulerSourceDefault}},
+		{"group without a choice", "", 3, models.SchedulerChoice{Scheduler: models.SchedulerLeitner, Source: models.SchedulerSourceDefault}},
+		{"no group", "", 0, models.SchedulerChoice{Scheduler: models.SchedulerLeitner, Source: models.SchedulerSourceDefault}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(schedulerSettings{learner: tt.learner, groups: groups})
+			got, err := h.schedulerChoice(context.Background(), tt.groupID)
+			if err != nil || got != tt.want {
+				t.Errorf("schedulerChoice = %+v, %v; want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
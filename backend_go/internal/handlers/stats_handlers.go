@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/stats"
+	"github.com/gin-gonic/gin"
+)
+
+// DifficultWordsQueryParams represents query parameters for the difficult words endpoint
+type DifficultWordsQueryParams struct {
+	GroupID     int64 `form:"group_id" json:"group_id"`
+	Limit       int   `form:"limit,default=20" json:"limit"`
+	MinAttempts int   `form:"min_attempts,default=1" json:"min_attempts"`
+}
+
+// CreateTroubleGroupRequest represents the request body for turning the
+// difficult words into a new group
+type CreateTroubleGroupRequest struct {
+	DifficultWordsQueryParams
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GetWordStats returns the learner's statistics for a word
+func (h *Handler) GetWordStats(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	word, err := h.repo.GetWordByID(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if word == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "word not found"})
+		return
+	}
+
+	reviews, err := h.repo.GetLearnerWordReviews(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	schedules, err := h.repo.GetWordSchedules(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	wordStats := stats.ForWord(id, reviews)
+	wordStats.Schedules = schedules
+	for _, schedule := range schedules {
+		if schedule.Scheduler == models.SchedulerLeitner {
+			box := schedule.Box
+			wordStats.Box = &box
+		}
+	}
+
+	c.JSON(http.StatusOK, wordStats)
+}
+
+// GetGroupStats returns learning progress for the words of a group with a
+// daily trend over the last days days (default 30)
+func (h *Handler) GetGroupStats(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repo.GetGroupByID(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+
+	_, wordCount, err := h.repo.GetWords(ctx, id, "", 1, 1)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	reviews, err := h.repo.GetGroupReviews(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats.ForGroup(id, wordCount, reviews, days, time.Now()))
+}
+
+// GetDifficultWords ranks words by their smoothed error rate
+func (h *Handler) GetDifficultWords(c *gin.Context) {
+	var params DifficultWordsQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	words, ok := h.difficultWords(c, params)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, words)
+}
+
+// CreateTroubleGroup creates a group holding the currently most difficult words
+func (h *Handler) CreateTroubleGroup(c *gin.Context) {
+	req := CreateTroubleGroupRequest{
+		DifficultWordsQueryParams: DifficultWordsQueryParams{Limit: 20, MinAttempts: 1},
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name == "" {
+		req.Name = "Trouble words"
+	}
+
+	words, ok := h.difficultWords(c, req.DifficultWordsQueryParams)
+	if !ok {
+		return
+	}
+	if len(words) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "no reviewed words to add"})
+		return
+	}
+
+	wordIDs := make([]int64, len(words))
+	for i, word := range words {
+		wordIDs[i] = word.Word.ID
+	}
+
+	group := models.Group{Name: req.Name, Description: req.Description}
+	if err := h.repo.CreateGroupWithWords(c.Request.Context(), &group, wordIDs); err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.TroubleGroup{Group: group, Words: words})
+}
+
+// difficultWords loads and ranks the difficult words for params, writing an
+// error response and returning false on failure
+func (h *Handler) difficultWords(c *gin.Context, params DifficultWordsQueryParams) ([]models.DifficultWord, bool) {
+	if params.Limit < 1 || params.Limit > 100 {
+		params.Limit = 20
+	}
+
+	counts, err := h.repo.GetWordErrorCounts(c.Request.Context(), params.GroupID)
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+
+	return stats.RankDifficult(counts, params.MinAttempts, params.Limit), true
+}
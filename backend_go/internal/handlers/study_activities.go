@@ -14,9 +14,9 @@ type CreateStudyActivityRequest struct {
 
 // GetStudyActivities returns all study activities
 func (h *Handler) GetStudyActivities(c *gin.Context) {
-	activities, err := h.repo.GetStudyActivities()
+	activities, err := h.repo.GetStudyActivities(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, activities)
@@ -30,9 +30,9 @@ func (h *Handler) GetStudyActivity(c *gin.Context) {
 		return
 	}
 
-	activity, err := h.repo.GetStudyActivity(id)
+	activity, err := h.repo.GetStudyActivity(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 	if activity == nil {
@@ -51,9 +51,9 @@ func (h *Handler) GetStudyActivitySessions(c *gin.Context) {
 		return
 	}
 
-	sessions, err := h.repo.GetStudySessionsByActivityID(id)
+	sessions, err := h.repo.GetStudySessionsByActivityID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -68,8 +68,8 @@ func (h *Handler) CreateStudyActivity(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.CreateStudyActivity(&activity); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.CreateStudyActivity(c.Request.Context(), &activity); err != nil {
+		internalError(c, err)
 		return
 	}
 
@@ -91,8 +91,8 @@ func (h *Handler) CreateStudySession(c *gin.Context) {
 	}
 	session.StudyActivityID = activityID
 
-	if err := h.repo.CreateStudySession(&session); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.CreateStudySession(c.Request.Context(), &session); err != nil {
+		internalError(c, err)
 		return
 	}
 
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// PullChanges returns the content changed since the token query parameter
+func (h *Handler) PullChanges(c *gin.Context) {
+	resp, err := h.repo.Sync(c.Request.Context(), &models.SyncRequest{Token: c.Query("token")})
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Sync stores the sessions and reviews a client queued offline and returns
+// the content changed since its token
+func (h *Handler) Sync(c *gin.Context) {
+	var req models.SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.repo.Sync(c.Request.Context(), &req)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
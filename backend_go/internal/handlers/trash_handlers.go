@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrash returns all soft-deleted words and groups
+func (h *Handler) GetTrash(c *gin.Context) {
+	trash, err := h.repo.GetTrash(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// RestoreWord takes a word back out of the trash
+func (h *Handler) RestoreWord(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+		return
+	}
+
+	word, err := h.repo.RestoreWord(c.Request.Context(), id)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, word)
+}
+
+// RestoreGroup takes a group back out of the trash
+func (h *Handler) RestoreGroup(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	group, err := h.repo.RestoreGroup(c.Request.Context(), id)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
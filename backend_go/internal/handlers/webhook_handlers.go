@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRequest represents the request body for registering or updating
+// a webhook
+type WebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	GroupID     *int64   `json:"group_id"`
+	Description string   `json:"description" binding:"max=200"`
+	Secret      string   `json:"secret" binding:"omitempty,min=16,max=200"`
+	Active      *bool    `json:"active"`
+}
+
+// WebhookDeliveriesQueryParams represents query parameters for the delivery log
+type WebhookDeliveriesQueryParams struct {
+	Status string `form:"status" json:"status" binding:"omitempty,oneof=pending delivered failed"`
+	Limit  int    `form:"limit,default=50" json:"limit" binding:"min=1,max=200"`
+}
+
+// GetWebhooks lists the registered webhooks
+func (h *Handler) GetWebhooks(c *gin.Context) {
+	hooks, err := h.repo.GetWebhooks(c.Request.Context())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hooks)
+}
+
+// GetWebhook returns a webhook
+func (h *Handler) GetWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return
+	}
+
+	webhook, err := h.repo.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if webhook == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// CreateWebhook registers a webhook. The signing secret is generated
+// unless given and is only returned here.
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	webhook, ok := h.bindWebhook(c)
+	if !ok {
+		return
+	}
+	if webhook.Secret == "" {
+		secret, err := webhooks.NewSecret()
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		webhook.Secret = secret
+	}
+
+	if err := h.repo.CreateWebhook(c.Request.Context(), &webhook); err != nil {
+		repositoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, models.WebhookCreated{Webhook: webhook, Secret: webhook.Secret})
+}
+
+// UpdateWebhook replaces a webhook's settings; a new secret is optional
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return
+	}
+
+	webhook, ok := h.bindWebhook(c)
+	if !ok {
+		return
+	}
+	webhook.ID = id
+
+	ctx := c.Request.Context()
+	if err := h.repo.UpdateWebhook(ctx, &webhook); err != nil {
+		repositoryError(c, err)
+		return
+	}
+	updated, err := h.repo.GetWebhook(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return
+	}
+
+	if err := h.repo.DeleteWebhook(c.Request.Context(), id); err != nil {
+		repositoryError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries returns a webhook's delivery log, newest first
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return
+	}
+	var params WebhookDeliveriesQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	webhook, err := h.repo.GetWebhook(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if webhook == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+		return
+	}
+
+	deliveries, err := h.repo.GetWebhookDeliveries(ctx, id, params.Status, params.Limit)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverWebhookDelivery queues a past delivery to be sent again
+func (h *Handler) RedeliverWebhookDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return
+	}
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery id"})
+		return
+	}
+
+	delivery, err := h.repo.RedeliverWebhookDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		repositoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// bindWebhook validates a webhook request body
+func (h *Handler) bindWebhook(c *gin.Context) (models.Webhook, bool) {
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return models.Webhook{}, false
+	}
+
+	if err := webhooks.CheckURL(c.Request.Context(), req.URL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return models.Webhook{}, false
+	}
+	for _, eventType := range req.Events {
+		if !webhooks.ValidEvent(eventType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "events must be among " + strings.Join(webhooks.EventTypes, ", ")})
+			return models.Webhook{}, false
+		}
+	}
+	if req.GroupID != nil {
+		group, err := h.repo.GetGroupByID(c.Request.Context(), *req.GroupID)
+		if err != nil {
+			internalError(c, err)
+			return models.Webhook{}, false
+		}
+		if group == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+			return models.Webhook{}, false
+		}
+	}
+
+	webhook := models.Webhook{
+		URL:         req.URL,
+		Events:      req.Events,
+		GroupID:     req.GroupID,
+		Description: req.Description,
+		Secret:      req.Secret,
+		Active:      req.Active == nil || *req.Active,
+	}
+	return webhook, true
+}
@@ -32,19 +32,15 @@ func (h *Handler) GetWords(c *gin.Context) {
 		params.PageSize = 20
 	}
 
-	words, total, err := h.repo.GetWords(params.GroupID, params.Search, params.Page, params.PageSize)
+	words, total, err := h.repo.GetWords(c.Request.Context(), params.GroupID, params.Search, params.Page, params.PageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"words": words,
-		"pagination": gin.H{
-			"total":     total,
-			"page":      params.Page,
-			"page_size": params.PageSize,
-		},
+	c.JSON(http.StatusOK, models.WordsPage{
+		Words:      words,
+		Pagination: models.Pagination{Total: total, Page: params.Page, PageSize: params.PageSize},
 	})
 }
 
@@ -56,9 +52,9 @@ func (h *Handler) GetWordByID(c *gin.Context) {
 		return
 	}
 
-	word, err := h.repo.GetWordByID(id)
+	word, err := h.repo.GetWordByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 	if word == nil {
@@ -77,8 +73,8 @@ func (h *Handler) CreateWord(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.CreateWord(&word); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.CreateWord(c.Request.Context(), &word); err != nil {
+		internalError(c, err)
 		return
 	}
 
@@ -100,8 +96,8 @@ func (h *Handler) UpdateWord(c *gin.Context) {
 	}
 	word.ID = id
 
-	if err := h.repo.UpdateWord(&word); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.UpdateWord(c.Request.Context(), &word); err != nil {
+		repositoryError(c, err)
 		return
 	}
 
@@ -116,8 +112,8 @@ func (h *Handler) DeleteWord(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteWord(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.DeleteWord(c.Request.Context(), id); err != nil {
+		repositoryError(c, err)
 		return
 	}
 
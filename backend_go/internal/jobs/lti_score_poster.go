@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/events"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/lti"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+)
+
+const (
+	// DefaultScoreRetryInterval is how often scores that failed to post are
+	// retried
+	DefaultScoreRetryInterval = 5 * time.Minute
+	// MaxScoreAttempts is how many times a score is posted before giving up
+	MaxScoreAttempts = 10
+	// scoreBatch is how many scores are posted per pass
+	scoreBatch = 50
+)
+
+// LTIScorePoster posts the scores of finished LTI-launched sessions to the
+// platform's gradebook through the Assignment and Grade Services
+type LTIScorePoster struct {
+	repo     repositories.Repository
+	tool     *lti.Tool
+	interval time.Duration
+}
+
+// NewLTIScorePoster creates a score poster
+func NewLTIScorePoster(repo repositories.Repository, tool *lti.Tool) *LTIScorePoster {
+	return &LTIScorePoster{repo: repo, tool: tool, interval: DefaultScoreRetryInterval}
+}
+
+// Run posts new scores as soon as a session finishes and retries failed
+// ones every interval until ctx is done
+func (p *LTIScorePoster) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	finished, cancel := events.Subscribe(func(event events.Event) bool {
+		return event.Type == events.SessionFinished
+	}, 0)
+	defer cancel()
+
+	retry := true
+	for {
+		p.post(ctx, retry)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			retry = true
+		case _, ok := <-finished:
+			if !ok {
+				finished = nil
+			}
+			retry = false
+		}
+	}
+}
+
+// post sends pending scores; without retry only scores never tried yet
+// are sent, so a flood of finished sessions does not burn the attempts of
+// failing ones
+func (p *LTIScorePoster) post(ctx context.Context, retry bool) {
+	scores, err := p.repo.GetPendingLTIScores(ctx, scoreBatch)
+	if err != nil {
+		slog.Error("error loading LTI scores", "error", err)
+		return
+	}
+
+	for _, score := range scores {
+		if ctx.Err() != nil {
+			return
+		}
+		if !retry && score.Attempts > 0 {
+			continue
+		}
+
+		given := 0.0
+		if score.WordsReviewed > 0 {
+			given = 100 * float64(score.CorrectCount) / float64(score.WordsReviewed)
+		}
+		err := p.tool.PostScore(ctx, score.Platform, score.LineItemURL, lti.Score{
+			UserID:           score.Subject,
+			ScoreGiven:       given,
+			ScoreMaximum:     100,
+			Timestamp:        score.FinishedAt.UTC(),
+			ActivityProgress: "Completed",
+			GradingProgress:  "FullyGraded",
+		})
+
+		status, message := models.LTIScorePosted, ""
+		if err != nil {
+			message = err.Error()
+			status = models.LTIScorePending
+			if score.Attempts+1 >= MaxScoreAttempts {
+				status = models.LTIScoreFailed
+			}
+			slog.Warn("error posting LTI score", "launch_id", score.LaunchID, "attempts", score.Attempts+1, "error", err)
+		}
+		if err := p.repo.RecordLTIScoreAttempt(ctx, score.LaunchID, status, message); err != nil {
+			slog.Error("error recording LTI score attempt", "launch_id", score.LaunchID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+)
+
+const (
+	// DefaultTrashRetention is how long trashed words and groups are kept
+	DefaultTrashRetention = 30 * 24 * time.Hour
+	// DefaultPurgeInterval is how often the trash is checked for expired rows
+	DefaultPurgeInterval = time.Hour
+)
+
+// TrashPurger permanently deletes trashed rows once their retention expires
+type TrashPurger struct {
+	repo      repositories.Repository
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewTrashPurger creates a trash purger. The retention window is read from
+// TRASH_RETENTION as a Go duration (e.g. "720h"), defaulting to 30 days.
+func NewTrashPurger(repo repositories.Repository) *TrashPurger {
+	retention := DefaultTrashRetention
+	if value := os.Getenv("TRASH_RETENTION"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			slog.Warn("invalid TRASH_RETENTION, using default", "value", value, "default", retention.String())
+		} else {
+			retention = parsed
+		}
+	}
+
+	return &TrashPurger{repo: repo, retention: retention, interval: DefaultPurgeInterval}
+}
+
+// Run purges expired trash immediately and then every interval until ctx is done
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge removes rows trashed longer than the retention window
+func (p *TrashPurger) purge(ctx context.Context) {
+	purged, err := p.repo.PurgeTrash(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		slog.Error("error purging trash", "error", err)
+		return
+	}
+	if purged > 0 {
+		slog.Info("purged trash", "rows", purged, "retention", p.retention.String())
+	}
+}
@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/events"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/webhooks"
+)
+
+const (
+	// DefaultDispatchInterval is how often the outbox is checked for due
+	// deliveries when no new events arrive
+	DefaultDispatchInterval = 10 * time.Second
+	// dispatchBatch is how many deliveries are sent per pass
+	dispatchBatch = 50
+	// deliveryTimeout bounds each request to a webhook
+	deliveryTimeout = 10 * time.Second
+	// dispatchWorkers is how many webhooks are sent to at once; each
+	// webhook's deliveries are sent one after another, in order
+	dispatchWorkers = 8
+)
+
+// WebhookDispatcher sends the deliveries queued in the webhook outbox,
+// retrying failures with exponential backoff
+type WebhookDispatcher struct {
+	repo     repositories.Repository
+	client   *http.Client
+	interval time.Duration
+
+	// held maps deliveries whose attempt could not be recorded to when
+	// they may be sent again, so a failing database does not resend them
+	// on every pass
+	mu   sync.Mutex
+	held map[int64]time.Time
+}
+
+// NewWebhookDispatcher creates a webhook dispatcher
+func NewWebhookDispatcher(repo repositories.Repository) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:     repo,
+		client:   webhooks.NewClient(deliveryTimeout),
+		interval: DefaultDispatchInterval,
+		held:     make(map[int64]time.Time),
+	}
+}
+
+// Run sends due deliveries immediately, whenever an event is published and
+// at least every interval until ctx is done. Deliveries left pending at
+// shutdown are sent on the next start.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	published, cancel := events.Subscribe(nil, 0)
+	defer cancel()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case _, ok := <-published:
+			if !ok {
+				published = nil
+			}
+		}
+	}
+}
+
+// dispatch sends due deliveries until none are left
+func (d *WebhookDispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := d.repo.GetDueWebhookDeliveries(ctx, time.Now(), dispatchBatch)
+		if err != nil {
+			slog.Error("error loading webhook deliveries", "error", err)
+			return
+		}
+		sending := d.release(deliveries)
+		d.sendAll(ctx, sending)
+		if len(deliveries) < dispatchBatch || len(sending) == 0 {
+			return
+		}
+	}
+}
+
+// release drops held deliveries whose hold has not expired
+func (d *WebhookDispatcher) release(deliveries []models.OutboxDelivery) []models.OutboxDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for id, until := range d.held {
+		if !now.Before(until) {
+			delete(d.held, id)
+		}
+	}
+	var due []models.OutboxDelivery
+	for _, delivery := range deliveries {
+		if _, ok := d.held[delivery.ID]; !ok {
+			due = append(due, delivery)
+		}
+	}
+	return due
+}
+
+// hold keeps a delivery from being sent again until a later pass
+func (d *WebhookDispatcher) hold(delivery models.OutboxDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.held[delivery.ID] = time.Now().Add(webhooks.Backoff(delivery.Attempts + 1))
+}
+
+// sendAll sends deliveries on up to dispatchWorkers webhooks at once. A
+// webhook's deliveries keep their order and are sent one at a time, so a
+// slow receiver only holds up its own deliveries.
+func (d *WebhookDispatcher) sendAll(ctx context.Context, deliveries []models.OutboxDelivery) {
+	var order []int64
+	byWebhook := make(map[int64][]models.OutboxDelivery)
+	for _, delivery := range deliveries {
+		if _, ok := byWebhook[delivery.WebhookID]; !ok {
+			order = append(order, delivery.WebhookID)
+		}
+		byWebhook[delivery.WebhookID] = append(byWebhook[delivery.WebhookID], delivery)
+	}
+
+	queue := make(chan []models.OutboxDelivery, len(order))
+	for _, webhookID := range order {
+		queue <- byWebhook[webhookID]
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(dispatchWorkers, len(order)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				for _, delivery := range group {
+					if ctx.Err() != nil {
+						return
+					}
+					d.send(ctx, delivery)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// send attempts one delivery and records the outcome
+func (d *WebhookDispatcher) send(ctx context.Context, delivery models.OutboxDelivery) {
+	status, err := webhooks.Deliver(ctx, d.client, delivery)
+	if ctx.Err() != nil {
+		// Shutting down; the delivery stays due and is retried on restart
+		return
+	}
+
+	attempt := models.WebhookAttempt{
+		DeliveryID:     delivery.ID,
+		Status:         models.DeliveryDelivered,
+		ResponseStatus: status,
+		AttemptedAt:    time.Now(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+		attempts := delivery.Attempts + 1
+		if attempts >= webhooks.MaxAttempts {
+			attempt.Status = models.DeliveryFailed
+			slog.Warn("webhook delivery failed", "webhook_id", delivery.WebhookID, "delivery_id", delivery.ID, "attempts", attempts, "error", err)
+		} else {
+			next := attempt.AttemptedAt.Add(webhooks.Backoff(attempts))
+			attempt.Status = models.DeliveryPending
+			attempt.NextAttemptAt = &next
+		}
+	}
+
+	if err := d.repo.RecordWebhookAttempt(ctx, attempt); err != nil {
+		slog.Error("error recording webhook attempt", "delivery_id", delivery.ID, "error", err)
+		d.hold(delivery)
+	}
+}
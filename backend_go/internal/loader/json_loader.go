@@ -1,10 +1,11 @@
 package loader
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
@@ -23,6 +24,15 @@ func NewJSONLoader(repo repositories.Repository) *JSONLoader {
 
 // LoadInitialData loads initial data from JSON files
 func (l *JSONLoader) LoadInitialData() error {
+	// Skip loading when the database already holds words
+	_, total, err := l.repo.GetWords(context.Background(), 0, "", 1, 1)
+	if err != nil {
+		return fmt.Errorf("error checking existing words: %v", err)
+	}
+	if total > 0 {
+		return nil
+	}
+
 	// Load groups
 	groups, err := l.loadGroups()
 	if err != nil {
@@ -46,7 +56,7 @@ func (l *JSONLoader) loadGroups() (map[string]int64, error) {
 	data, err := ioutil.ReadFile("data/groups.json")
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Println("groups.json not found, skipping")
+			slog.Warn("seed file not found, skipping", "file", "data/groups.json")
 			return groupMap, nil
 		}
 		return nil, fmt.Errorf("error reading groups.json: %v", err)
@@ -59,7 +69,7 @@ func (l *JSONLoader) loadGroups() (map[string]int64, error) {
 
 	// Create groups
 	for _, group := range groups {
-		if err := l.repo.CreateGroup(&group); err != nil {
+		if err := l.repo.CreateGroup(context.Background(), &group); err != nil {
 			return nil, fmt.Errorf("error creating group: %v", err)
 		}
 		groupMap[group.Name] = group.ID
@@ -74,7 +84,7 @@ func (l *JSONLoader) loadWords(groupMap map[string]int64) error {
 	data, err := ioutil.ReadFile("data/words.json")
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Println("words.json not found, skipping")
+			slog.Warn("seed file not found, skipping", "file", "data/words.json")
 			return nil
 		}
 		return fmt.Errorf("error reading words.json: %v", err)
@@ -87,7 +97,7 @@ func (l *JSONLoader) loadWords(groupMap map[string]int64) error {
 
 	// Create words
 	for _, word := range words {
-		if err := l.repo.CreateWord(&word); err != nil {
+		if err := l.repo.CreateWord(context.Background(), &word); err != nil {
 			return fmt.Errorf("error creating word: %v", err)
 		}
 	}
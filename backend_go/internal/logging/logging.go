@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type contextKey struct{}
+
+// Config controls the log level and how many successful requests are logged
+type Config struct {
+	Level      slog.Level
+	SampleRate float64
+}
+
+// ConfigFromEnv reads LOG_LEVEL (debug, info, warn, error) and
+// LOG_SAMPLE_RATE (0 to 1, share of successful requests that are logged)
+func ConfigFromEnv() Config {
+	cfg := Config{Level: slog.LevelInfo, SampleRate: 1}
+
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := cfg.Level.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+			slog.Warn("invalid LOG_LEVEL, using info", "value", level)
+		}
+	}
+	if rate := os.Getenv("LOG_SAMPLE_RATE"); rate != "" {
+		parsed, err := strconv.ParseFloat(rate, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			slog.Warn("invalid LOG_SAMPLE_RATE, logging every request", "value", rate)
+		} else {
+			cfg.SampleRate = parsed
+		}
+	}
+
+	return cfg
+}
+
+// Init installs a JSON logger as the slog and log package default
+func Init(cfg Config) {
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: cfg.Level})
+	slog.SetDefault(slog.New(handler))
+}
+
+// WithLogger returns a context carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
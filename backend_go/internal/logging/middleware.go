@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	mathrand "math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader carries the request ID between services and clients
+	RequestIDHeader = "X-Request-ID"
+	// UserIDHeader identifies the user acting on behalf of a request
+	UserIDHeader = "X-User-ID"
+
+	requestIDKey = "request_id"
+)
+
+// RequestID reuses the incoming X-Request-ID or generates one, echoes it in
+// the response and stores it on the context
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID assigned by the RequestID middleware
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// Middleware attaches a request-scoped logger to the context and writes one
+// access log line per request. Failed requests are always logged along with
+// their errors; successful ones are sampled at cfg.SampleRate.
+func Middleware(cfg Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		attrs := []any{
+			slog.String("request_id", GetRequestID(c)),
+			slog.String("route", route),
+		}
+		if userID := c.GetHeader(UserIDHeader); userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		if sessionID := c.Param("session_id"); sessionID != "" {
+			attrs = append(attrs, slog.String("session_id", sessionID))
+		}
+
+		logger := slog.Default().With(attrs...)
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		fields := []any{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+		}
+
+		switch {
+		case len(c.Errors) > 0:
+			fields = append(fields, slog.String("error", strings.Join(c.Errors.Errors(), "; ")))
+			logger.Error("request failed", fields...)
+		case status >= 500:
+			logger.Error("request failed", fields...)
+		case status >= 400:
+			logger.Warn("request rejected", fields...)
+		case sampled(cfg.SampleRate):
+			logger.Info("request completed", fields...)
+		}
+	}
+}
+
+// sampled reports whether an event should be kept at the given rate
+func sampled(rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	return mathrand.Float64() < math.Max(rate, 0)
+}
+
+// newRequestID returns a random 128-bit hex identifier
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
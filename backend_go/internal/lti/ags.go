@@ -0,0 +1,166 @@
+package lti
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// assertionTTL is how long a client credentials assertion stays valid
+	assertionTTL = 5 * time.Minute
+	// scoreMediaType is the content type of AGS score submissions
+	scoreMediaType = "application/vnd.ims.lis.v1.score+json"
+)
+
+// Score is a result posted to a platform's line item
+type Score struct {
+	UserID           string    `json:"userId"`
+	ScoreGiven       float64   `json:"scoreGiven"`
+	ScoreMaximum     float64   `json:"scoreMaximum"`
+	Comment          string    `json:"comment,omitempty"`
+	Timestamp        time.Time `json:"timestamp"`
+	ActivityProgress string    `json:"activityProgress"`
+	GradingProgress  string    `json:"gradingProgress"`
+}
+
+// accessToken is a cached platform access token
+type accessToken struct {
+	value   string
+	expires time.Time
+}
+
+// PostScore sends a score to a line item, authenticating with the
+// platform's OAuth2 client credentials grant
+func (t *Tool) PostScore(ctx context.Context, platform models.LTIPlatform, lineItemURL string, score Score) error {
+	token, err := t.accessToken(ctx, platform, ScopeScore)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(lineItemURL)
+	if err != nil {
+		return fmt.Errorf("invalid line item URL: %v", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/scores"
+
+	body, err := json.Marshal(score)
+	if err != nil {
+		return fmt.Errorf("error encoding score: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating score request: %v", err)
+	}
+	req.Header.Set("Content-Type", scoreMediaType)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting score: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		t.mu.Lock()
+		delete(t.tokens, tokenKey(platform, ScopeScore))
+		t.mu.Unlock()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("error posting score: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// accessToken returns a cached token for a scope or requests a new one
+func (t *Tool) accessToken(ctx context.Context, platform models.LTIPlatform, scope string) (string, error) {
+	key := tokenKey(platform, scope)
+	t.mu.Lock()
+	cached, ok := t.tokens[key]
+	t.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	now := time.Now()
+	assertion, err := t.sign(jwt.RegisteredClaims{
+		Issuer:    platform.ClientID,
+		Subject:   platform.ClientID,
+		Audience:  jwt.ClaimStrings{platform.AuthTokenURL},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(assertionTTL)),
+		ID:        randomID(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error signing client assertion: %v", err)
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {assertion},
+		"scope":                 {scope},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, platform.AuthTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error creating token request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting access token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error requesting access token: status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.AccessToken == "" {
+		return "", fmt.Errorf("error decoding access token response: %v", err)
+	}
+
+	// Renew a minute early so a token never expires mid-request
+	lifetime := time.Duration(body.ExpiresIn)*time.Second - time.Minute
+	if lifetime > 0 {
+		t.mu.Lock()
+		t.tokens[key] = accessToken{value: body.AccessToken, expires: now.Add(lifetime)}
+		t.mu.Unlock()
+	}
+	return body.AccessToken, nil
+}
+
+func tokenKey(platform models.LTIPlatform, scope string) string {
+	return strconv.FormatInt(platform.ID, 10) + " " + scope
+}
+
+// randomID returns a random hex identifier for nonces, states and JWT IDs
+func randomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewState returns a random login state or nonce
+func NewState() string {
+	return randomID()
+}
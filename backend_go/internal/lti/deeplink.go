@@ -0,0 +1,57 @@
+package lti
+
+import (
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// deepLinkTTL is how long a deep linking response stays valid
+const deepLinkTTL = 5 * time.Minute
+
+// ContentItem is a link returned to the platform by deep linking
+type ContentItem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title,omitempty"`
+	Text     string            `json:"text,omitempty"`
+	URL      string            `json:"url,omitempty"`
+	Custom   map[string]string `json:"custom,omitempty"`
+	LineItem *LineItem         `json:"lineItem,omitempty"`
+}
+
+// LineItem asks the platform to create a gradebook column for a link
+type LineItem struct {
+	Label        string  `json:"label,omitempty"`
+	ScoreMaximum float64 `json:"scoreMaximum"`
+}
+
+type deepLinkingResponse struct {
+	jwt.RegisteredClaims
+	Nonce        string        `json:"nonce"`
+	MessageType  string        `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version      string        `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentID string        `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	ContentItems []ContentItem `json:"https://purl.imsglobal.org/spec/lti-dl/claim/content_items"`
+	Data         string        `json:"https://purl.imsglobal.org/spec/lti-dl/claim/data,omitempty"`
+}
+
+// DeepLinkResponse signs the message that returns the chosen items to the
+// platform. data must echo the request's data claim.
+func (t *Tool) DeepLinkResponse(platform models.LTIPlatform, deploymentID, data string, items []ContentItem) (string, error) {
+	now := time.Now()
+	return t.sign(deepLinkingResponse{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    platform.ClientID,
+			Audience:  jwt.ClaimStrings{platform.Issuer},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(deepLinkTTL)),
+		},
+		Nonce:        randomID(),
+		MessageType:  MessageDeepLinkingResponse,
+		Version:      Version,
+		DeploymentID: deploymentID,
+		ContentItems: items,
+		Data:         data,
+	})
+}
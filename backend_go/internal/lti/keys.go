@@ -0,0 +1,177 @@
+package lti
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+// jwksRefresh is how long a platform's key set is trusted before it is
+// fetched again; unknown key IDs trigger an earlier refresh
+const (
+	jwksRefresh    = 10 * time.Minute
+	jwksMinRefresh = 10 * time.Second
+)
+
+// KeyStore persists the tool's signing key
+type KeyStore interface {
+	GetLTIKey(ctx context.Context) (kid, privateKey string, err error)
+	CreateLTIKey(ctx context.Context, kid, privateKey string) error
+}
+
+// LoadKey returns the tool's signing key, generating and storing one on
+// first use so the published key survives restarts
+func LoadKey(ctx context.Context, store KeyStore) (string, *rsa.PrivateKey, error) {
+	kid, encoded, err := store.GetLTIKey(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	if encoded != "" {
+		block, _ := pem.Decode([]byte(encoded))
+		if block == nil {
+			return "", nil, errors.New("error decoding LTI key: no PEM block")
+		}
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return "", nil, fmt.Errorf("error decoding LTI key: %v", err)
+		}
+		return kid, key, nil
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", nil, fmt.Errorf("error generating LTI key: %v", err)
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("error generating LTI key id: %v", err)
+	}
+	kid = hex.EncodeToString(b)
+	encoded = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	if err := store.CreateLTIKey(ctx, kid, encoded); err != nil {
+		return "", nil, err
+	}
+	return kid, key, nil
+}
+
+// JWK is an RSA public key in JSON Web Key form
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the tool's public key set
+func (t *Tool) JWKS() JWKS {
+	return JWKS{Keys: []JWK{{
+		Kty: "RSA",
+		Kid: t.kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(t.key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(t.key.E)).Bytes()),
+	}}}
+}
+
+// publicKey decodes an RSA JWK
+func (k JWK) publicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key modulus: %v", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key exponent: %v", err)
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid key exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// keySet is a platform's cached public keys
+type keySet struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// platformKey returns a platform's public key by ID, fetching its key set
+// when it is stale or does not hold the key yet
+func (t *Tool) platformKey(ctx context.Context, jwksURL, kid string) (*rsa.PublicKey, error) {
+	t.mu.Lock()
+	set := t.jwks[jwksURL]
+	t.mu.Unlock()
+
+	if set != nil {
+		age := time.Since(set.fetchedAt)
+		if key, ok := set.keys[kid]; ok && age < jwksRefresh {
+			return key, nil
+		}
+		if age < jwksMinRefresh {
+			return nil, fmt.Errorf("unknown platform key %q", kid)
+		}
+	}
+
+	set, err := t.fetchKeySet(ctx, jwksURL)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	t.jwks[jwksURL] = set
+	t.mu.Unlock()
+
+	key, ok := set.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown platform key %q", kid)
+	}
+	return key, nil
+}
+
+func (t *Tool) fetchKeySet(ctx context.Context, jwksURL string) (*keySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating platform key request: %v", err)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching platform keys: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching platform keys: status %d", resp.StatusCode)
+	}
+
+	var jwks JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("error decoding platform keys: %v", err)
+	}
+	set := &keySet{keys: make(map[string]*rsa.PublicKey), fetchedAt: time.Now()}
+	for _, jwk := range jwks.Keys {
+		if key, err := jwk.publicKey(); err == nil {
+			set.keys[jwk.Kid] = key
+		}
+	}
+	return set, nil
+}
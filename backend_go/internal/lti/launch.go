@@ -0,0 +1,163 @@
+package lti
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidLaunch is returned for launches that fail validation
+var ErrInvalidLaunch = errors.New("invalid launch")
+
+// Claims are the claims of a launch id_token the tool uses
+type Claims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty string                 `json:"azp,omitempty"`
+	Nonce           string                 `json:"nonce"`
+	Name            string                 `json:"name,omitempty"`
+	GivenName       string                 `json:"given_name,omitempty"`
+	FamilyName      string                 `json:"family_name,omitempty"`
+	Email           string                 `json:"email,omitempty"`
+	MessageType     string                 `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version         string                 `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentID    string                 `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	TargetLinkURI   string                 `json:"https://purl.imsglobal.org/spec/lti/claim/target_link_uri"`
+	Roles           []string               `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+	Custom          map[string]interface{} `json:"https://purl.imsglobal.org/spec/lti/claim/custom,omitempty"`
+	AGS             *AGSEndpoint           `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint,omitempty"`
+	DeepLinking     *DeepLinkingSettings   `json:"https://purl.imsglobal.org/spec/lti-dl/claim/deep_linking_settings,omitempty"`
+}
+
+// AGSEndpoint is the Assignment and Grade Services claim
+type AGSEndpoint struct {
+	Scope     []string `json:"scope"`
+	LineItems string   `json:"lineitems,omitempty"`
+	LineItem  string   `json:"lineitem,omitempty"`
+}
+
+// CanPostScores reports whether the launch allows posting a score
+func (a *AGSEndpoint) CanPostScores() bool {
+	if a == nil || a.LineItem == "" {
+		return false
+	}
+	for _, scope := range a.Scope {
+		if scope == ScopeScore {
+			return true
+		}
+	}
+	return false
+}
+
+// DeepLinkingSettings is the deep linking request claim
+type DeepLinkingSettings struct {
+	ReturnURL      string   `json:"deep_link_return_url"`
+	AcceptTypes    []string `json:"accept_types"`
+	AcceptMultiple bool     `json:"accept_multiple,omitempty"`
+	AcceptLineItem bool     `json:"accept_lineitem,omitempty"`
+	AutoCreate     bool     `json:"auto_create,omitempty"`
+	Title          string   `json:"title,omitempty"`
+	Data           string   `json:"data,omitempty"`
+}
+
+// DisplayName returns the user's name from whichever claims are present
+func (c *Claims) DisplayName() string {
+	switch {
+	case c.Name != "":
+		return c.Name
+	case c.GivenName != "" || c.FamilyName != "":
+		if c.GivenName != "" && c.FamilyName != "" {
+			return c.GivenName + " " + c.FamilyName
+		}
+		return c.GivenName + c.FamilyName
+	}
+	return c.Subject
+}
+
+// CustomInt returns an integer custom parameter, falling back to the
+// target link URI's query string
+func (c *Claims) CustomInt(name string) int64 {
+	if value, ok := c.Custom[name]; ok {
+		if n, err := strconv.ParseInt(fmt.Sprint(value), 10, 64); err == nil {
+			return n
+		}
+	}
+	if u, err := url.Parse(c.TargetLinkURI); err == nil {
+		if n, err := strconv.ParseInt(u.Query().Get(name), 10, 64); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// LoginRedirect builds the authentication request the browser is sent to
+// in answer to a third-party login initiation
+func (t *Tool) LoginRedirect(platform models.LTIPlatform, loginHint, messageHint, state, nonce string) (string, error) {
+	u, err := url.Parse(platform.AuthLoginURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid platform login URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("scope", "openid")
+	query.Set("response_type", "id_token")
+	query.Set("response_mode", "form_post")
+	query.Set("prompt", "none")
+	query.Set("client_id", platform.ClientID)
+	query.Set("redirect_uri", t.cfg.LaunchURL())
+	query.Set("login_hint", loginHint)
+	if messageHint != "" {
+		query.Set("lti_message_hint", messageHint)
+	}
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// ParseLaunch verifies a launch id_token against the platform's keys and
+// checks it was issued for this tool in answer to the login with nonce
+func (t *Tool) ParseLaunch(ctx context.Context, platform models.LTIPlatform, idToken, nonce string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return t.platformKey(ctx, platform.JWKSURL, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(platform.Issuer),
+		jwt.WithAudience(platform.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLaunch, err)
+	}
+
+	switch {
+	case claims.Nonce == "" || claims.Nonce != nonce:
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidLaunch)
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != platform.ClientID:
+		return nil, fmt.Errorf("%w: azp must be the tool's client id", ErrInvalidLaunch)
+	case claims.Version != Version:
+		return nil, fmt.Errorf("%w: unsupported LTI version %q", ErrInvalidLaunch, claims.Version)
+	case platform.DeploymentID != "" && claims.DeploymentID != platform.DeploymentID:
+		return nil, fmt.Errorf("%w: unknown deployment %q", ErrInvalidLaunch, claims.DeploymentID)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: anonymous launches are not supported", ErrInvalidLaunch)
+	}
+
+	switch claims.MessageType {
+	case MessageResourceLink:
+	case MessageDeepLinking:
+		if claims.DeepLinking == nil || claims.DeepLinking.ReturnURL == "" {
+			return nil, fmt.Errorf("%w: missing deep linking settings", ErrInvalidLaunch)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported message type %q", ErrInvalidLaunch, claims.MessageType)
+	}
+	return &claims, nil
+}
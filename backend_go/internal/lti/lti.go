@@ -0,0 +1,99 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Message types
+const (
+	MessageResourceLink        = "LtiResourceLinkRequest"
+	MessageDeepLinking         = "LtiDeepLinkingRequest"
+	MessageDeepLinkingResponse = "LtiDeepLinkingResponse"
+)
+
+// ScopeScore lets the tool post scores to a line item
+const ScopeScore = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+
+// Version is the LTI version the tool speaks
+const Version = "1.3.0"
+
+// clockSkew is the leeway allowed when checking token times
+const clockSkew = time.Minute
+
+// Config holds the tool's public addresses
+type Config struct {
+	// BaseURL is where platforms reach this server, e.g. https://lang.example.com
+	BaseURL string
+	// AppURL is the study app launches are redirected to; without it a
+	// launch answers with JSON
+	AppURL string
+}
+
+// ConfigFromEnv reads LTI_BASE_URL (default http://localhost:8080) and
+// LTI_APP_URL
+func ConfigFromEnv() Config {
+	cfg := Config{BaseURL: "http://localhost:8080"}
+	if value := os.Getenv("LTI_BASE_URL"); value != "" {
+		if u, err := url.Parse(value); err == nil && u.Host != "" {
+			cfg.BaseURL = strings.TrimRight(value, "/")
+		} else {
+			slog.Warn("invalid LTI_BASE_URL, using default", "value", value, "default", cfg.BaseURL)
+		}
+	}
+	cfg.AppURL = os.Getenv("LTI_APP_URL")
+	return cfg
+}
+
+// LoginURL is the OIDC login initiation endpoint registered with platforms
+func (c Config) LoginURL() string { return c.BaseURL + "/lti/login" }
+
+// LaunchURL is the redirect URI launches are posted to
+func (c Config) LaunchURL() string { return c.BaseURL + "/lti/launch" }
+
+// JWKSURL publishes the tool's public key
+func (c Config) JWKSURL() string { return c.BaseURL + "/lti/jwks" }
+
+// Tool signs the tool's messages and verifies platforms' tokens
+type Tool struct {
+	cfg    Config
+	key    *rsa.PrivateKey
+	kid    string
+	client *http.Client
+
+	mu     sync.Mutex
+	jwks   map[string]*keySet
+	tokens map[string]accessToken
+}
+
+// NewTool creates a tool signing with key, published under kid
+func NewTool(cfg Config, kid string, key *rsa.PrivateKey) *Tool {
+	return &Tool{
+		cfg:    cfg,
+		key:    key,
+		kid:    kid,
+		client: &http.Client{Timeout: 10 * time.Second},
+		jwks:   make(map[string]*keySet),
+		tokens: make(map[string]accessToken),
+	}
+}
+
+// Config returns the tool's configuration
+func (t *Tool) Config() Config {
+	return t.cfg
+}
+
+// sign signs claims with the tool's key
+func (t *Tool) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = t.kid
+	return token.SignedString(t.key)
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"database/sql"
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// activeWindow is how recently a session must have recorded a review to
+// count as active
+const activeWindow = "-15 minutes"
+
+// learningCollector reports learning statistics computed from the database
+// at scrape time, so they survive restarts
+type learningCollector struct {
+	db *sql.DB
+
+	correctRatio   *prometheus.Desc
+	activeLearners *prometheus.Desc
+}
+
+func newLearningCollector(db *sql.DB) *learningCollector {
+	return &learningCollector{
+		db: db,
+		correctRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "review_correct_ratio"),
+			"Share of correct reviews over the last 24 hours.",
+			nil, nil,
+		),
+		activeLearners: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "active_learners"),
+			"Study sessions that recorded a review in the last 15 minutes.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *learningCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.correctRatio
+	ch <- c.activeLearners
+}
+
+// Collect implements prometheus.Collector
+func (c *learningCollector) Collect(ch chan<- prometheus.Metric) {
+	var ratio float64
+	var active int
+	err := c.db.QueryRow(`
+		SELECT
+			COALESCE((
+				SELECT AVG(CASE WHEN is_correct THEN 1.0 ELSE 0.0 END)
+				FROM word_review_items
+				WHERE created_at >= datetime('now', '-1 day')
+			), 0),
+			(
+				SELECT COUNT(DISTINCT study_session_id)
+				FROM word_review_items
+				WHERE created_at >= datetime('now', ?)
+			)
+	`, activeWindow).Scan(&ratio, &active)
+	if err != nil {
+		slog.Error("error collecting learning metrics", "error", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.correctRatio, prometheus.GaugeValue, ratio)
+	ch <- prometheus.MustNewConstMetric(c.activeLearners, prometheus.GaugeValue, float64(active))
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "genia"
+
+var (
+	// HTTPRequestDuration tracks request latency per route and status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// QueryDuration tracks latency per repository method
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "query_duration_seconds",
+		Help:      "Repository method latency.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"method"})
+
+	// ReviewsRecorded counts word reviews by result
+	ReviewsRecorded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_recorded_total",
+		Help:      "Word reviews recorded, by result.",
+	}, []string{"result"})
+
+	// SessionsStarted counts study sessions created
+	SessionsStarted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "study_sessions_started_total",
+		Help:      "Study sessions started.",
+	})
+)
+
+// Register registers all collectors, including database pool and
+// learning statistics gathered from db, with the default registry
+func Register(db *sql.DB) {
+	prometheus.MustRegister(
+		HTTPRequestDuration,
+		QueryDuration,
+		ReviewsRecorded,
+		SessionsStarted,
+		collectors.NewDBStatsCollector(db, "sqlite"),
+		newLearningCollector(db),
+	)
+}
+
+// Handler serves the registered metrics
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// Middleware records the latency of every request by its route template
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveQuery starts timing a repository method; call the returned
+// function when the method completes
+func ObserveQuery(method string) func() {
+	timer := prometheus.NewTimer(QueryDuration.WithLabelValues(method))
+	return func() { timer.ObserveDuration() }
+}
+
+// RecordReview counts a recorded word review
+func RecordReview(correct bool) {
+	result := "wrong"
+	if correct {
+		result = "correct"
+	}
+	ReviewsRecorded.WithLabelValues(result).Inc()
+}
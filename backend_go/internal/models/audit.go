@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited entity types
+const (
+	EntityWord      = "word"
+	EntityGroup     = "group"
+	EntityWordGroup = "word_group"
+)
+
+// Audit actions
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRevert  = "revert"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// AuditEvent represents a single recorded change to a word, group or membership
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int64           `json:"entity_id"`
+	Version    int             `json:"version"`
+	Action     string          `json:"action"`
+	Actor      string          `json:"actor,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -9,4 +9,38 @@ type DashboardStats struct {
 	CorrectCount    int     `json:"correct_count"`
 	AccuracyRate    float64 `json:"accuracy_rate"`
 	LastSessionDate string  `json:"last_session_date"`
+
+	// Fields named as in the dashboard spec
+	SuccessRate        int     `json:"success_rate"`
+	TotalStudySessions int     `json:"total_study_sessions"`
+	TotalActiveGroups  int     `json:"total_active_groups"`
+	StudyStreak        int     `json:"study_streak"`
+	MasteryPercentage  float64 `json:"mastery_percentage"`
+}
+
+// StudyProgress represents how much of the vocabulary has been studied
+type StudyProgress struct {
+	TotalAvailableWords int             `json:"total_available_words"`
+	TotalWordsStudied   int             `json:"total_words_studied"`
+	MasteredWords       int             `json:"mastered_words"`
+	MasteryPercentage   float64         `json:"mastery_percentage"`
+	Groups              []GroupProgress `json:"groups,omitempty"`
+}
+
+// GroupProgress represents study progress for the words of one group
+type GroupProgress struct {
+	GroupID             int64   `json:"group_id"`
+	GroupName           string  `json:"group_name"`
+	TotalAvailableWords int     `json:"total_available_words"`
+	TotalWordsStudied   int     `json:"total_words_studied"`
+	MasteredWords       int     `json:"mastered_words"`
+	MasteryPercentage   float64 `json:"mastery_percentage"`
+}
+
+// WordProgress holds the review facts mastery is judged from
+type WordProgress struct {
+	WordID        int64 `json:"word_id"`
+	Attempts      int   `json:"attempts"`
+	CorrectCount  int   `json:"correct_count"`
+	CurrentStreak int   `json:"current_streak"`
 }
@@ -0,0 +1,40 @@
+package models
+
+// Forecast projects a learner's review load and retention
+type Forecast struct {
+	Days      int              `json:"days"`
+	Retention float64          `json:"retention"`
+	Model     ForecastModel    `json:"model"`
+	TotalDue  int              `json:"total_due"`
+	PeakDate  string           `json:"peak_date,omitempty"`
+	PeakDue   int              `json:"peak_due"`
+	Daily     []ForecastDay    `json:"daily"`
+	Groups    []GroupRetention `json:"groups"`
+}
+
+// ForecastModel describes the forgetting curve fitted to a learner. A
+// multiplier above 1 means the learner forgets more slowly than the
+// default curve.
+type ForecastModel struct {
+	Fitted     bool    `json:"fitted"`
+	Multiplier float64 `json:"multiplier"`
+	Samples    int     `json:"samples"`
+	LogLoss    float64 `json:"log_loss"`
+}
+
+// ForecastDay is the number of reviews projected to come due on a day
+type ForecastDay struct {
+	Date string `json:"date"`
+	Due  int    `json:"due"`
+}
+
+// GroupRetention is the estimated recall of a group's studied words now
+// and at the end of the forecast without further reviews
+type GroupRetention struct {
+	GroupID            int64   `json:"group_id"`
+	GroupName          string  `json:"group_name"`
+	WordCount          int     `json:"word_count"`
+	WordsStudied       int     `json:"words_studied"`
+	Retention          float64 `json:"retention"`
+	RetentionAtHorizon float64 `json:"retention_at_horizon"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Achievement codes
+const (
+	AchievementFirst100Words  = "first_100_words"
+	AchievementStreak7Days    = "streak_7_days"
+	AchievementPerfectSession = "perfect_session"
+	AchievementRootFamily     = "root_family_completed"
+)
+
+// XP sources
+const (
+	XPSourceReview      = "review"
+	XPSourceSession     = "session"
+	XPSourceAchievement = "achievement"
+)
+
+// Achievement is an achievement a learner has unlocked or can unlock.
+// Detail names the instance of a repeatable achievement, such as the root
+// of a completed root family.
+type Achievement struct {
+	Code        string     `json:"code"`
+	Detail      string     `json:"detail,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	XP          int        `json:"xp"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+}
+
+// Achievements summarises a learner's XP, streak and achievements
+type Achievements struct {
+	XP           int           `json:"xp"`
+	XPToday      int           `json:"xp_today"`
+	StreakDays   int           `json:"streak_days"`
+	Achievements []Achievement `json:"achievements"`
+}
+
+// GoalSettings are a learner's daily targets; 0 disables a target
+type GoalSettings struct {
+	DailyReviews int `json:"daily_reviews" binding:"min=0,max=1000"`
+	DailyMinutes int `json:"daily_minutes" binding:"min=0,max=600"`
+}
+
+// DailyActivity is how much a learner studied on one day
+type DailyActivity struct {
+	Date    string  `json:"date"`
+	Reviews int     `json:"reviews"`
+	Minutes float64 `json:"minutes"`
+}
+
+// GoalProgress is a day's activity measured against the daily goals
+type GoalProgress struct {
+	DailyActivity
+	ReviewsMet bool `json:"reviews_met"`
+	MinutesMet bool `json:"minutes_met"`
+	Completed  bool `json:"completed"`
+}
+
+// Goals reports a learner's daily goals, today's progress and recent days
+type Goals struct {
+	GoalSettings
+	Today      GoalProgress   `json:"today"`
+	StreakDays int            `json:"streak_days"`
+	History    []GoalProgress `json:"history"`
+}
@@ -4,8 +4,16 @@ import "time"
 
 // Group represents a group of words
 type Group struct {
-	ID          int64     `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// Trash holds soft-deleted words and groups awaiting restore or purge
+type Trash struct {
+	Words  []Word  `json:"words"`
+	Groups []Group `json:"groups"`
 }
@@ -0,0 +1,14 @@
+package models
+
+// ComponentHealth represents the health of a single dependency
+type ComponentHealth struct {
+	Status  string                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthStatus represents the response of a health or readiness probe
+type HealthStatus struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+// Leaderboard periods
+const (
+	PeriodWeek = "week"
+	PeriodAll  = "all"
+)
+
+// Leaderboard metrics
+const (
+	MetricXP            = "xp"
+	MetricWordsMastered = "words_mastered"
+	MetricAccuracy      = "accuracy"
+)
+
+// Leaderboard ranks learners on a group's board, or everyone's when
+// GroupID is 0
+type Leaderboard struct {
+	GroupID     int64              `json:"group_id"`
+	Period      string             `json:"period"`
+	PeriodStart string             `json:"period_start,omitempty"`
+	Metric      string             `json:"metric"`
+	Entries     []LeaderboardEntry `json:"entries"`
+	Me          *LeaderboardEntry  `json:"me"`
+}
+
+// LeaderboardEntry is one learner's standing, shown under their pseudonym
+// when they have one
+type LeaderboardEntry struct {
+	Rank          int     `json:"rank"`
+	Name          string  `json:"name"`
+	Me            bool    `json:"me,omitempty"`
+	XP            int     `json:"xp"`
+	WordsMastered int     `json:"words_mastered"`
+	Reviews       int     `json:"reviews"`
+	CorrectCount  int     `json:"correct_count"`
+	Accuracy      float64 `json:"accuracy"`
+}
+
+// LearnerProfile holds a learner's leaderboard preferences
+type LearnerProfile struct {
+	Pseudonym         string `json:"pseudonym" binding:"max=40"`
+	LeaderboardOptOut bool   `json:"leaderboard_opt_out"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// LTIPlatform is an LMS registered to launch activities through LTI 1.3
+type LTIPlatform struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Issuer       string    `json:"issuer" binding:"required"`
+	ClientID     string    `json:"client_id" binding:"required"`
+	DeploymentID string    `json:"deployment_id"`
+	AuthLoginURL string    `json:"auth_login_url" binding:"required"`
+	AuthTokenURL string    `json:"auth_token_url" binding:"required"`
+	JWKSURL      string    `json:"jwks_url" binding:"required"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LTI score statuses of a launch
+const (
+	LTIScorePending = "pending"
+	LTIScorePosted  = "posted"
+	LTIScoreFailed  = "failed"
+)
+
+// LTILaunch is a validated launch. Resource link launches start a study
+// session whose score is posted back to LineItemURL when it finishes;
+// deep linking launches keep what is needed to answer the platform.
+type LTILaunch struct {
+	ID                int64     `json:"id"`
+	LaunchKey         string    `json:"-"`
+	PlatformID        int64     `json:"platform_id"`
+	Subject           string    `json:"subject"`
+	LearnerID         string    `json:"learner_id"`
+	MessageType       string    `json:"message_type"`
+	DeploymentID      string    `json:"deployment_id"`
+	GroupID           int64     `json:"group_id,omitempty"`
+	StudyActivityID   int64     `json:"study_activity_id,omitempty"`
+	StudySessionID    int64     `json:"study_session_id,omitempty"`
+	LineItemURL       string    `json:"lineitem_url,omitempty"`
+	DeepLinkReturnURL string    `json:"-"`
+	DeepLinkData      string    `json:"-"`
+	ScoreStatus       string    `json:"score_status,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// LTIUser maps a platform user to a local learner
+type LTIUser struct {
+	PlatformID int64  `json:"platform_id"`
+	Subject    string `json:"subject"`
+	LearnerID  string `json:"learner_id"`
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+}
+
+// LTILaunchResult is returned by a resource link launch when no study app
+// is configured to redirect to
+type LTILaunchResult struct {
+	LearnerID    string        `json:"learner_id"`
+	Name         string        `json:"name"`
+	StudySession *StudySession `json:"study_session"`
+}
+
+// LTIScore is a finished session's result waiting to be posted to the
+// platform's gradebook
+type LTIScore struct {
+	LaunchID      int64
+	Attempts      int
+	Platform      LTIPlatform
+	Subject       string
+	LineItemURL   string
+	WordsReviewed int
+	CorrectCount  int
+	FinishedAt    time.Time
+}
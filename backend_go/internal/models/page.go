@@ -0,0 +1,18 @@
+package models
+
+// PageRequest asks for a page of rows after a keyset cursor
+type PageRequest struct {
+	// After is the ID of the last row of the previous page, or 0 for the
+	// first page
+	After int64
+	// Limit is the most rows returned for each parent
+	Limit int
+}
+
+// Parents a page of study sessions or reviews can be partitioned by
+const (
+	PageByGroup    = "group"
+	PageByActivity = "activity"
+	PageByWord     = "word"
+	PageBySession  = "session"
+)
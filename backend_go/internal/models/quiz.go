@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Quiz directions: the prompt language followed by the answer language
+const (
+	DirectionArabicToEnglish = "ar-en"
+	DirectionEnglishToArabic = "en-ar"
+	DirectionRomajiToArabic  = "romaji-ar"
+)
+
+// Quiz represents a generated multiple-choice quiz for a group
+type Quiz struct {
+	ID             int64          `json:"id"`
+	GroupID        int64          `json:"group_id"`
+	StudySessionID int64          `json:"study_session_id"`
+	Direction      string         `json:"direction"`
+	Questions      []QuizQuestion `json:"questions"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// QuizQuestion represents one prompt and its answer options. The word
+// being asked is kept server-side so clients cannot read the answer.
+type QuizQuestion struct {
+	ID       int64        `json:"id"`
+	Position int          `json:"position"`
+	WordID   int64        `json:"-"`
+	Prompt   string       `json:"prompt"`
+	Options  []QuizOption `json:"options"`
+}
+
+// QuizOption represents a single answer choice
+type QuizOption struct {
+	WordID int64  `json:"word_id"`
+	Text   string `json:"text"`
+}
+
+// QuizCandidate is a word that can be asked or offered as a distractor
+type QuizCandidate struct {
+	Word     Word
+	Mistakes int
+}
+
+// WordConfusion counts how often a word was answered with another word
+type WordConfusion struct {
+	WordID         int64
+	ConfusedWithID int64
+	Count          int
+}
+
+// QuizAnswer represents a submitted answer to a quiz question
+type QuizAnswer struct {
+	QuestionID int64 `json:"question_id" binding:"required"`
+	WordID     int64 `json:"word_id" binding:"required"`
+	ResponseMS *int  `json:"response_ms" binding:"omitempty,min=0"`
+	HintsUsed  int   `json:"hints_used" binding:"min=0"`
+	Confidence *int  `json:"confidence" binding:"omitempty,min=1,max=5"`
+}
+
+// QuizResults holds the graded answers to a quiz
+type QuizResults struct {
+	Results []QuizAnswerResult `json:"results"`
+}
+
+// QuizAnswerResult represents the outcome of grading a quiz answer
+type QuizAnswerResult struct {
+	QuestionID    int64 `json:"question_id"`
+	Correct       bool  `json:"correct"`
+	CorrectWordID int64 `json:"correct_word_id"`
+	ReviewID      int64 `json:"word_review_item_id"`
+}
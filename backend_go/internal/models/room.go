@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Quiz room statuses
+const (
+	RoomLobby    = "lobby"
+	RoomQuestion = "question"
+	RoomReveal   = "reveal"
+	RoomFinished = "finished"
+	RoomClosed   = "closed"
+)
+
+// Room is a live multiplayer quiz that students join with its code
+type Room struct {
+	Code            string            `json:"code"`
+	GroupID         int64             `json:"group_id"`
+	Direction       string            `json:"direction"`
+	Status          string            `json:"status"`
+	QuestionCount   int               `json:"question_count"`
+	QuestionSeconds int               `json:"question_seconds"`
+	CurrentQuestion int               `json:"current_question"`
+	Players         int               `json:"players"`
+	Scoreboard      []ScoreboardEntry `json:"scoreboard"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+// RoomCreated is returned to the teacher who opened a room. The host
+// token lets them start and end the game over the room's WebSocket.
+type RoomCreated struct {
+	Room
+	HostToken string `json:"host_token"`
+}
+
+// ScoreboardEntry is a participant's standing in a room
+type ScoreboardEntry struct {
+	Rank     int    `json:"rank"`
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	Correct  int    `json:"correct"`
+	Answered int    `json:"answered"`
+}
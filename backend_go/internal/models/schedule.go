@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Scheduler names
+const (
+	SchedulerSM2     = "sm2"
+	SchedulerLeitner = "leitner"
+)
+
+// Scheduler sources tell where the active scheduler was chosen
+const (
+	SchedulerSourceLearner = "learner"
+	SchedulerSourceGroup   = "group"
+	SchedulerSourceDefault = "default"
+)
+
+// WordSchedule is a learner's spaced-repetition state for a word under one
+// scheduler. Box is only used by Leitner; ease factor by SM-2.
+type WordSchedule struct {
+	WordID         int64     `json:"word_id"`
+	Scheduler      string    `json:"scheduler"`
+	Box            int       `json:"box,omitempty"`
+	EaseFactor     float64   `json:"ease_factor,omitempty"`
+	IntervalDays   float64   `json:"interval_days"`
+	Repetitions    int       `json:"repetitions"`
+	DueAt          time.Time `json:"due_at"`
+	LastReviewedAt time.Time `json:"last_reviewed_at"`
+}
+
+// DueWord is a word waiting in a learner's study queue
+type DueWord struct {
+	Word     Word          `json:"word"`
+	Schedule *WordSchedule `json:"schedule"`
+}
+
+// DueQueue lists the words a learner should study next
+type DueQueue struct {
+	SchedulerChoice
+	Words []DueWord `json:"words"`
+}
+
+// SchedulerSetting selects a scheduler for a learner or a group. An empty
+// scheduler clears the choice.
+type SchedulerSetting struct {
+	Scheduler string `json:"scheduler" binding:"omitempty,oneof=sm2 leitner"`
+}
+
+// SchedulerChoice is the scheduler in effect and where it was chosen
+type SchedulerChoice struct {
+	Scheduler string `json:"scheduler"`
+	Source    string `json:"source"`
+}
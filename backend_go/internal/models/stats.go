@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Mastery levels summarise how well a word is known
+const (
+	MasteryNew      = "new"
+	MasteryLearning = "learning"
+	MasteryFamiliar = "familiar"
+	MasteryMastered = "mastered"
+)
+
+// WordStats represents learning statistics for a word
+type WordStats struct {
+	WordID        int64      `json:"word_id"`
+	Attempts      int        `json:"attempts"`
+	CorrectCount  int        `json:"correct_count"`
+	Accuracy      float64    `json:"accuracy"`
+	LastSeen      *time.Time `json:"last_seen"`
+	CurrentStreak int        `json:"current_streak"`
+	LongestStreak int        `json:"longest_streak"`
+	Mastery       string     `json:"mastery"`
+	// Box is the word's Leitner box for the learner, once reviewed
+	Box       *int           `json:"box,omitempty"`
+	Schedules []WordSchedule `json:"schedules,omitempty"`
+}
+
+// DifficultWord represents a word ranked by how often it is answered wrongly
+type DifficultWord struct {
+	Word              Word    `json:"word"`
+	Attempts          int     `json:"attempts"`
+	Errors            int     `json:"errors"`
+	ErrorRate         float64 `json:"error_rate"`
+	SmoothedErrorRate float64 `json:"smoothed_error_rate"`
+}
+
+// TroubleGroup is a group created from the learner's difficult words
+type TroubleGroup struct {
+	Group Group           `json:"group"`
+	Words []DifficultWord `json:"words"`
+}
+
+// GroupStats represents learning progress across the words of a group
+type GroupStats struct {
+	GroupID       int64           `json:"group_id"`
+	WordCount     int             `json:"word_count"`
+	WordsStudied  int             `json:"words_studied"`
+	MasteredCount int             `json:"mastered_count"`
+	Accuracy      float64         `json:"accuracy"`
+	LastStudied   *time.Time      `json:"last_studied"`
+	Trend         []DailyProgress `json:"trend"`
+}
+
+// DailyProgress represents the reviews made on one day
+type DailyProgress struct {
+	Date         string  `json:"date"`
+	Reviews      int     `json:"reviews"`
+	CorrectCount int     `json:"correct_count"`
+	Accuracy     float64 `json:"accuracy"`
+}
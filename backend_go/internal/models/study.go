@@ -14,20 +14,88 @@ type StudyActivity struct {
 
 // StudySession represents a study session
 type StudySession struct {
-	ID              int64     `json:"id"`
-	StudyActivityID int64     `json:"study_activity_id"`
-	GroupID         int64     `json:"group_id"`
-	Group           *Group    `json:"group,omitempty"`
-	WordsReviewed   int       `json:"words_reviewed"`
-	CorrectCount    int       `json:"correct_count"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              int64      `json:"id"`
+	StudyActivityID int64      `json:"study_activity_id"`
+	GroupID         int64      `json:"group_id"`
+	GroupName       string     `json:"group_name,omitempty"`
+	Group           *Group     `json:"group,omitempty"`
+	WordsReviewed   int        `json:"words_reviewed"`
+	CorrectCount    int        `json:"correct_count"`
+	WrongCount      int        `json:"wrong_count"`
+	CreatedAt       time.Time  `json:"created_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
 }
 
 // WordReviewItem represents a word review in a study session
 type WordReviewItem struct {
 	ID             int64     `json:"id"`
+	ClientID       *string   `json:"client_id,omitempty" binding:"omitempty,max=64"`
 	WordID         int64     `json:"word_id"`
 	StudySessionID int64     `json:"study_session_id"`
 	IsCorrect      bool      `json:"is_correct"`
+	AnswerText     *string   `json:"answer_text,omitempty"`
+	Score          *float64  `json:"score,omitempty"`
+	ResponseMS     *int      `json:"response_ms,omitempty" binding:"omitempty,min=0"`
+	Direction      string    `json:"direction,omitempty" binding:"omitempty,oneof=ar-en en-ar romaji-ar"`
+	Mode           string    `json:"mode,omitempty" binding:"omitempty,oneof=multiple_choice typing flashcard"`
+	HintsUsed      int       `json:"hints_used" binding:"min=0"`
+	Confidence     *int      `json:"confidence,omitempty" binding:"omitempty,min=1,max=5"`
+	LearnerID      string    `json:"learner_id,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 }
+
+// Review modes describe how a word was presented to the learner
+const (
+	ModeMultipleChoice = "multiple_choice"
+	ModeTyping         = "typing"
+	ModeFlashcard      = "flashcard"
+)
+
+// Batch item statuses
+const (
+	BatchItemCreated   = "created"
+	BatchItemDuplicate = "duplicate"
+	BatchItemRejected  = "rejected"
+)
+
+// ReviewBatch is a set of up to 500 reviews submitted for one study
+// session; each item's study_session_id is taken from the URL
+type ReviewBatch struct {
+	Items []WordReviewItem `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+// ReviewBatchItemResult reports what happened to one item of a batch
+type ReviewBatchItemResult struct {
+	Index    int     `json:"index"`
+	ClientID *string `json:"client_id,omitempty"`
+	Status   string  `json:"status"`
+	ReviewID int64   `json:"review_id,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// ReviewBatchResult reports the outcome of a batch submission
+type ReviewBatchResult struct {
+	Created    int                     `json:"created"`
+	Duplicates int                     `json:"duplicates"`
+	Rejected   int                     `json:"rejected"`
+	Results    []ReviewBatchItemResult `json:"results"`
+}
+
+// ReviewAnalytics summarises reviews of a word or a study session
+type ReviewAnalytics struct {
+	Attempts      int                            `json:"attempts"`
+	CorrectCount  int                            `json:"correct_count"`
+	Accuracy      float64                        `json:"accuracy"`
+	AvgScore      *float64                       `json:"avg_score"`
+	AvgResponseMS *float64                       `json:"avg_response_ms"`
+	HintsUsed     int                            `json:"hints_used"`
+	AvgConfidence *float64                       `json:"avg_confidence"`
+	ByMode        map[string]ReviewModeAnalytics `json:"by_mode"`
+}
+
+// ReviewModeAnalytics summarises reviews answered in one mode
+type ReviewModeAnalytics struct {
+	Attempts      int      `json:"attempts"`
+	CorrectCount  int      `json:"correct_count"`
+	AvgResponseMS *float64 `json:"avg_response_ms"`
+}
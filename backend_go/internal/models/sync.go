@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// SyncRequest carries the changes a client queued while offline and its
+// position in the server's change stream
+type SyncRequest struct {
+	// Token is the opaque change token from the previous sync; empty for a full sync
+	Token    string        `json:"token"`
+	Sessions []SyncSession `json:"sessions" binding:"max=500,dive"`
+	Reviews  []SyncReview  `json:"reviews" binding:"max=5000,dive"`
+}
+
+// SyncSession is a study session started on a client
+type SyncSession struct {
+	ClientID        string     `json:"client_id" binding:"required,max=64"`
+	StudyActivityID int64      `json:"study_activity_id" binding:"required"`
+	GroupID         int64      `json:"group_id" binding:"required"`
+	StartedAt       *time.Time `json:"started_at"`
+}
+
+// SyncReview is a review recorded on a client. It belongs either to a
+// server session (study_session_id) or to a session queued in the same or
+// an earlier sync (session_client_id).
+type SyncReview struct {
+	WordReviewItem
+	SessionClientID string     `json:"session_client_id" binding:"max=64"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+}
+
+// SyncResponse returns the content changed since the request's token, the
+// outcome of each pushed session and review, and the token for the next sync
+type SyncResponse struct {
+	Token string `json:"token"`
+	// Reset is set when the token was not recognised and a full snapshot
+	// was returned instead; clients should replace their local content
+	Reset       bool             `json:"reset"`
+	Words       []Word           `json:"words"`
+	Groups      []Group          `json:"groups"`
+	Memberships []WordGroup      `json:"memberships"`
+	Deleted     []SyncTombstone  `json:"deleted"`
+	Sessions    []SyncItemResult `json:"sessions"`
+	Reviews     []SyncItemResult `json:"reviews"`
+}
+
+// SyncTombstone reports a row that was permanently deleted on the server
+type SyncTombstone struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   int64     `json:"entity_id"`
+	DeletedAt  time.Time `json:"deleted_at"`
+}
+
+// SyncItemResult reports what happened to one pushed session or review,
+// using the batch item statuses
+type SyncItemResult struct {
+	ClientID string `json:"client_id"`
+	Status   string `json:"status"`
+	ID       int64  `json:"id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is an endpoint notified of learning events. An empty GroupID
+// subscribes to every group.
+type Webhook struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	GroupID     *int64    `json:"group_id"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	Secret      string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WebhookCreated is returned once when a webhook is registered; the secret
+// signs every payload sent to it
+type WebhookCreated struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery is one event sent, or still to be sent, to a webhook
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseStatus *int            `json:"response_status"`
+	LastError      *string         `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// OutboxDelivery is a due delivery with what is needed to send it
+type OutboxDelivery struct {
+	ID        int64
+	WebhookID int64
+	EventID   string
+	EventType string
+	Payload   []byte
+	Attempts  int
+	URL       string
+	Secret    string
+}
+
+// WebhookPayload is the JSON body posted to webhooks
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	SessionID int64       `json:"session_id,omitempty"`
+	GroupID   int64       `json:"group_id,omitempty"`
+	LearnerID string      `json:"learner_id,omitempty"`
+	Data      interface{} `json:"data"`
+}
+
+// GroupMastery is the data of a group.mastered event
+type GroupMastery struct {
+	GroupID   int64  `json:"group_id"`
+	LearnerID string `json:"learner_id"`
+	Words     int    `json:"words"`
+}
+
+// WebhookAttempt is the outcome of one attempt to send a delivery
+type WebhookAttempt struct {
+	DeliveryID     int64
+	Status         string
+	ResponseStatus int
+	Error          string
+	AttemptedAt    time.Time
+	NextAttemptAt  *time.Time
+}
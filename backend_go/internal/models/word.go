@@ -13,20 +13,64 @@ type Word struct {
 	English   string         `json:"english"`
 	Parts     json.RawMessage `json:"parts"` // JSON data for additional word metadata
 	CreatedAt time.Time      `json:"created_at"`
+	DeletedAt *time.Time     `json:"deleted_at,omitempty"`
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
 	
 	// Relations
 	Groups          []Group          `json:"groups,omitempty"`
 	WordReviewItems []WordReviewItem `json:"word_review_items,omitempty"`
 }
 
+// WordsPage is one page of the word list
+type WordsPage struct {
+	Words      []Word     `json:"words"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination locates a page within a paginated list
+type Pagination struct {
+	Total    int `json:"total"`
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
 // WordGroup represents the many-to-many relationship between words and groups
 type WordGroup struct {
 	ID        int64     `json:"id"`
 	WordID    int64     `json:"word_id"`
 	GroupID   int64     `json:"group_id"`
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 	
 	// Relations
 	Word  *Word  `json:"word,omitempty"`
 	Group *Group `json:"group,omitempty"`
 }
+
+// PartOfSpeech returns the word's part of speech from its parts metadata,
+// read from "part_of_speech" or, failing that, "type"
+func (w Word) PartOfSpeech() string {
+	var parts struct {
+		PartOfSpeech string `json:"part_of_speech"`
+		Type         string `json:"type"`
+	}
+	if len(w.Parts) == 0 || json.Unmarshal(w.Parts, &parts) != nil {
+		return ""
+	}
+	if parts.PartOfSpeech != "" {
+		return parts.PartOfSpeech
+	}
+	return parts.Type
+}
+
+// Synonyms returns alternative English answers listed under "synonyms" in
+// the word's parts metadata
+func (w Word) Synonyms() []string {
+	var parts struct {
+		Synonyms []string `json:"synonyms"`
+	}
+	if len(w.Parts) == 0 || json.Unmarshal(w.Parts, &parts) != nil {
+		return nil
+	}
+	return parts.Synonyms
+}
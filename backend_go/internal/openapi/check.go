@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Check compares the document with the routes gin actually serves and
+// returns one problem per route that is registered but undocumented, or
+// documented but not registered. Custom methods count as registered when
+// the route dispatching them is.
+func (s *Spec) Check(routes gin.RoutesInfo) []string {
+	dispatched := make(map[string]bool)
+	for _, route := range s.actions {
+		dispatched[route] = true
+	}
+
+	registered := make(map[string]bool)
+	var problems []string
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		if s.hidden[key] || dispatched[key] {
+			continue
+		}
+		path, _ := pathParams(route.Path)
+		if item, ok := s.doc.Paths[path]; !ok || (*item)[strings.ToLower(route.Method)] == nil {
+			problems = append(problems, fmt.Sprintf("%s %s is registered but not documented", route.Method, path))
+		}
+	}
+
+	for path, item := range s.doc.Paths {
+		for method := range *item {
+			method = strings.ToUpper(method)
+			route, ok := s.actions[method+" "+path]
+			if !ok {
+				route = method + " " + ginPath(path)
+			}
+			if !registered[route] {
+				problems = append(problems, fmt.Sprintf("%s %s is documented but not registered", method, path))
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// ginPath converts OpenAPI {name} segments back to gin's :name segments
+func ginPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + segment[1:len(segment)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,165 @@
+// Package openapi builds the API's OpenAPI 3 document from typed route
+// definitions registered alongside the gin routes, so the document cannot
+// drift from the handlers it describes
+package openapi
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the OpenAPI version of the generated document
+const Version = "3.0.3"
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL the API is served from
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag groups operations in the UI
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations of one path, keyed by lower-case method
+type PathItem map[string]*Operation
+
+// Operation describes one route
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation's body
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response status
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how callers identify themselves
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Spec collects the operations registered through its routers
+type Spec struct {
+	doc       Document
+	schemas   *generator
+	errorBody *Schema
+	hidden    map[string]bool
+	// actions maps the documented "METHOD path" of each custom method to
+	// the gin route serving it
+	actions map[string]string
+}
+
+// New starts an empty document; errorType is the body returned with every
+// 4xx and 5xx response
+func New(info Info, errorType any) *Spec {
+	s := &Spec{
+		doc: Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]*PathItem),
+			Components: Components{
+				SecuritySchemes: make(map[string]*SecurityScheme),
+			},
+		},
+		schemas: newGenerator(),
+		hidden:  make(map[string]bool),
+		actions: make(map[string]string),
+	}
+	s.errorBody = s.schemas.schema(errorType)
+	return s
+}
+
+// AddServer lists a base URL in the document
+func (s *Spec) AddServer(url string) {
+	s.doc.Servers = append(s.doc.Servers, Server{URL: url})
+}
+
+// AddSecurityScheme registers a named security scheme that operations can
+// accept through Router.Security
+func (s *Spec) AddSecurityScheme(name string, scheme SecurityScheme) {
+	s.doc.Components.SecuritySchemes[name] = &scheme
+}
+
+// Document returns the document with the schemas collected so far
+func (s *Spec) Document() Document {
+	doc := s.doc
+	doc.Components.Schemas = s.schemas.components
+
+	seen := make(map[string]bool)
+	for _, item := range doc.Paths {
+		for _, op := range *item {
+			for _, tag := range op.Tags {
+				if !seen[tag] {
+					seen[tag] = true
+					doc.Tags = append(doc.Tags, Tag{Name: tag})
+				}
+			}
+		}
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+// Handler serves the document as JSON
+func (s *Spec) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.Document())
+	}
+}
@@ -0,0 +1,211 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type Timestamps struct {
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type widget struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name" binding:"required,min=1,max=40"`
+	Kind string `json:"kind" binding:"oneof=small large"`
+	Note *string
+	Timestamps
+	secret string
+}
+
+type widgetQuery struct {
+	Limit int    `form:"limit,default=20" binding:"min=1,max=100"`
+	Kind  string `form:"kind" binding:"required"`
+	Skip  string `json:"skip"`
+}
+
+type widgetHandlers struct{}
+
+func (widgetHandlers) GetWidgets(c *gin.Context)   { c.Status(http.StatusOK) }
+func (widgetHandlers) GetWidget(c *gin.Context)    { c.Status(http.StatusOK) }
+func (widgetHandlers) DeleteWidget(c *gin.Context) { c.Status(http.StatusNoContent) }
+func (widgetHandlers) WidgetAction(c *gin.Context) { c.String(http.StatusOK, c.Param("action")) }
+func (widgetHandlers) Unregistered(c *gin.Context) { c.Status(http.StatusOK) }
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newWidgetSpec documents a small API on a fresh engine
+func newWidgetSpec() (*Spec, *gin.Engine) {
+	engine := gin.New()
+	spec := New(Info{Title: "Widgets", Version: "1"}, apiError{})
+	spec.AddSecurityScheme("learner", SecurityScheme{Type: "apiKey", In: "header", Name: "X-User-ID"})
+
+	var h widgetHandlers
+	api := spec.Router(engine.Group("/api")).Tag("Widgets")
+	api.GET("/widgets", h.GetWidgets, Op{Summary: "List widgets", Query: widgetQuery{}, Response: []widget{}})
+	api.POST("/widgets", h.GetWidgets, Op{Body: widget{}, Response: widget{}, Status: http.StatusCreated})
+	api.Security("learner", true).GET("/widgets/:id", h.GetWidget, Op{Response: widget{}, Errors: []int{http.StatusConflict}})
+	api.DELETE("/widgets/:widget_id", h.DeleteWidget, Op{})
+	api.Actions(http.MethodPost, "/widgets/:id", h.WidgetAction, map[string]Op{
+		"parts:batch": {ID: "CreateParts", Body: widget{}, Response: ""},
+	})
+	spec.Router(engine).Hidden(http.MethodGet, "/docs/*filepath", h.GetWidgets)
+	return spec, engine
+}
+
+func TestRouterDocumentsOperations(t *testing.T) {
+	spec, _ := newWidgetSpec()
+	doc := spec.Document()
+
+	list := (*doc.Paths["/api/widgets"])["get"]
+	if list.OperationID != "GetWidgets" || list.Summary != "List widgets" || !reflect.DeepEqual(list.Tags, []string{"Widgets"}) {
+		t.Errorf("list operation = %+v", list)
+	}
+	wantParams := []Parameter{
+		{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Format: "int32", Default: int64(20), Minimum: ptr(1.0), Maximum: ptr(100.0)}},
+		{Name: "kind", In: "query", Required: true, Schema: &Schema{Type: "string"}},
+	}
+	if !reflect.DeepEqual(list.Parameters, wantParams) {
+		t.Errorf("query parameters = %+v, want %+v", list.Parameters, wantParams)
+	}
+	if got := responseCodes(list); !reflect.DeepEqual(got, []string{"200", "400", "500"}) {
+		t.Errorf("list responses = %v", got)
+	}
+	if schema := list.Responses["200"].Content["application/json"].Schema; schema.Type != "array" || schema.Items.Ref != "#/components/schemas/widget" {
+		t.Errorf("list response schema = %+v", schema)
+	}
+
+	// A second route on the same handler gets a method suffix
+	create := (*doc.Paths["/api/widgets"])["post"]
+	if create.OperationID != "GetWidgetsPost" || create.RequestBody == nil || !reflect.DeepEqual(responseCodes(create), []string{"201", "400", "500"}) {
+		t.Errorf("create operation = %+v", create)
+	}
+
+	get := (*doc.Paths["/api/widgets/{id}"])["get"]
+	idParam := []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer", Format: "int64"}}}
+	if !reflect.DeepEqual(get.Parameters, idParam) {
+		t.Errorf("path parameters = %+v", get.Parameters)
+	}
+	if got := responseCodes(get); !reflect.DeepEqual(got, []string{"200", "400", "404", "409", "500"}) {
+		t.Errorf("get responses = %v", got)
+	}
+	if want := []map[string][]string{{"learner": {}}, {}}; !reflect.DeepEqual(get.Security, want) {
+		t.Errorf("security = %v, want %v", get.Security, want)
+	}
+	if errorBody := get.Responses["404"].Content["application/json"].Schema; errorBody.Ref != "#/components/schemas/apiError" {
+		t.Errorf("error body = %+v", errorBody)
+	}
+
+	remove := (*doc.Paths["/api/widgets/{widget_id}"])["delete"]
+	if got := responseCodes(remove); !reflect.DeepEqual(got, []string{"204", "400", "404", "500"}) || remove.Responses["204"].Content != nil {
+		t.Errorf("delete responses = %v", got)
+	}
+
+	if _, ok := doc.Paths["/docs/{filepath}"]; ok {
+		t.Error("hidden route was documented")
+	}
+	if !reflect.DeepEqual(doc.Tags, []Tag{{Name: "Widgets"}}) {
+		t.Errorf("tags = %v", doc.Tags)
+	}
+}
+
+func TestRouterDocumentsActions(t *testing.T) {
+	spec, engine := newWidgetSpec()
+
+	item, ok := spec.Document().Paths["/api/widgets/{id}/parts:batch"]
+	if !ok {
+		t.Fatal("action is not documented under its own path")
+	}
+	op := (*item)["post"]
+	if op.OperationID != "CreateParts" || len(op.Parameters) != 1 || op.Parameters[0].Name != "id" || op.RequestBody == nil {
+		t.Errorf("action operation = %+v", op)
+	}
+	if _, ok := spec.Document().Paths["/api/widgets/{id}/{action}"]; ok {
+		t.Error("the dispatching route was documented")
+	}
+
+	// Gin serves the action through the dispatching route
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/widgets/7/parts:batch", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "parts:batch" {
+		t.Errorf("POST parts:batch = %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	spec, _ := newWidgetSpec()
+	got := spec.Document().Components.Schemas["widget"]
+	if got == nil {
+		t.Fatal("widget is not a component")
+	}
+
+	want := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":         {Type: "integer", Format: "int64"},
+			"name":       {Type: "string", MinLength: ptr(1), MaxLength: ptr(40)},
+			"kind":       {Type: "string", Enum: []any{"small", "large"}},
+			"Note":       {Type: "string", Nullable: true},
+			"created_at": {Type: "string", Format: "date-time"},
+			"deleted_at": {Type: "string", Format: "date-time", Nullable: true},
+		},
+		Required: []string{"name"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for name, prop := range got.Properties {
+			t.Logf("%s: %+v", name, *prop)
+		}
+		t.Errorf("widget schema = %+v, want %+v", *got, *want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	spec, engine := newWidgetSpec()
+	if problems := spec.Check(engine.Routes()); len(problems) != 0 {
+		t.Fatalf("Check = %v, want no problems", problems)
+	}
+
+	var h widgetHandlers
+	engine.GET("/api/undocumented", h.Unregistered)
+	spec.Router(gin.New()).PUT("/api/unregistered/:id", h.Unregistered, Op{})
+	spec.Router(gin.New()).Actions(http.MethodPost, "/api/gadgets/:id", h.WidgetAction, map[string]Op{"spin": {}})
+
+	want := []string{
+		"GET /api/undocumented is registered but not documented",
+		"POST /api/gadgets/{id}/spin is documented but not registered",
+		"PUT /api/unregistered/{id} is documented but not registered",
+	}
+	if problems := spec.Check(engine.Routes()); !reflect.DeepEqual(problems, want) {
+		t.Errorf("Check = %q, want %q", problems, want)
+	}
+}
+
+// responseCodes returns an operation's response statuses in order
+func responseCodes(op *Operation) []string {
+	var codes []string
+	for _, code := range []string{"200", "201", "204", "400", "404", "409", "500"} {
+		if _, ok := op.Responses[code]; ok {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) != len(op.Responses) {
+		return append(codes, "unexpected")
+	}
+	return codes
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
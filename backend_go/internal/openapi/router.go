@@ -0,0 +1,277 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Op describes a route for the document
+type Op struct {
+	// ID overrides the operation ID, which defaults to the handler's name
+	ID          string
+	Summary     string
+	Description string
+	// Query is the struct the handler binds with ShouldBindQuery
+	Query any
+	// Params lists query and header parameters the handler reads directly
+	Params []Parameter
+	Body   any
+	// Response is the success body; without one the route answers 204
+	Response any
+	// Status overrides the success status
+	Status int
+	// ContentType overrides application/json for the success body
+	ContentType string
+	// Errors lists error statuses beyond the 400, 404 and 500 inferred
+	// from the route's inputs
+	Errors []int
+}
+
+// QueryParam describes a query parameter the handler reads directly
+func QueryParam(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+// HeaderParam describes a request header the handler reads
+func HeaderParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// Routes is the part of a gin engine or group a Router registers on
+type Routes interface {
+	gin.IRoutes
+	BasePath() string
+}
+
+// Router registers gin routes and documents them in the same call
+type Router struct {
+	spec     *Spec
+	routes   Routes
+	tag      string
+	security []map[string][]string
+}
+
+// Router wraps routes so registrations through it are documented
+func (s *Spec) Router(routes Routes) *Router {
+	return &Router{spec: s, routes: routes}
+}
+
+// Tag returns a router that files its operations under tag
+func (r *Router) Tag(tag string) *Router {
+	tagged := *r
+	tagged.tag = tag
+	return &tagged
+}
+
+// Security returns a router whose operations accept the named security
+// scheme, or no credentials at all when optional is set
+func (r *Router) Security(scheme string, optional bool) *Router {
+	secured := *r
+	secured.security = []map[string][]string{{scheme: {}}}
+	if optional {
+		secured.security = append(secured.security, map[string][]string{})
+	}
+	return &secured
+}
+
+// GET registers and documents a GET route
+func (r *Router) GET(path string, handler gin.HandlerFunc, op Op) {
+	r.Handle(http.MethodGet, path, handler, op)
+}
+
+// POST registers and documents a POST route
+func (r *Router) POST(path string, handler gin.HandlerFunc, op Op) {
+	r.Handle(http.MethodPost, path, handler, op)
+}
+
+// PUT registers and documents a PUT route
+func (r *Router) PUT(path string, handler gin.HandlerFunc, op Op) {
+	r.Handle(http.MethodPut, path, handler, op)
+}
+
+// DELETE registers and documents a DELETE route
+func (r *Router) DELETE(path string, handler gin.HandlerFunc, op Op) {
+	r.Handle(http.MethodDelete, path, handler, op)
+}
+
+// Hidden registers a route that is deliberately left out of the document,
+// such as the documentation UI itself
+func (r *Router) Hidden(method, path string, handler gin.HandlerFunc) {
+	r.routes.Handle(method, path, handler)
+	r.spec.hidden[method+" "+joinPath(r.routes.BasePath(), path)] = true
+}
+
+// Handle registers a route and adds its operation to the document
+func (r *Router) Handle(method, path string, handler gin.HandlerFunc, op Op) {
+	r.routes.Handle(method, path, handler)
+
+	docPath, params := pathParams(joinPath(r.routes.BasePath(), path))
+	r.document(method, docPath, params, handler, op)
+}
+
+// Actions registers custom methods on a resource, such as
+// POST /study-sessions/:id/reviews:batch. Gin cannot match a colon inside
+// a path segment, so the last segment is registered as the :action
+// parameter and dispatch picks the handler; each action is documented as
+// its own path with the given operation.
+func (r *Router) Actions(method, path string, dispatch gin.HandlerFunc, actions map[string]Op) {
+	route := joinPath(r.routes.BasePath(), path) + "/:action"
+	r.routes.Handle(method, path+"/:action", dispatch)
+
+	base, params := pathParams(joinPath(r.routes.BasePath(), path))
+	for action, op := range actions {
+		docPath := base + "/" + action
+		r.spec.actions[method+" "+docPath] = method + " " + route
+		r.document(method, docPath, params, dispatch, op)
+	}
+}
+
+// document adds an operation to the document under docPath
+func (r *Router) document(method, docPath string, params []Parameter, handler gin.HandlerFunc, op Op) {
+	operation := &Operation{
+		OperationID: r.spec.operationID(method, op.ID, handler),
+		Summary:     op.Summary,
+		Description: op.Description,
+		Parameters:  append(params, r.spec.queryParams(op.Query)...),
+		Responses:   r.spec.responses(op, len(params) > 0),
+		Security:    r.security,
+	}
+	operation.Parameters = append(operation.Parameters, op.Params...)
+	if r.tag != "" {
+		operation.Tags = []string{r.tag}
+	}
+	if op.Body != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: r.spec.schemas.schema(op.Body)}},
+		}
+	}
+
+	item, ok := r.spec.doc.Paths[docPath]
+	if !ok {
+		item = &PathItem{}
+		r.spec.doc.Paths[docPath] = item
+	}
+	(*item)[strings.ToLower(method)] = operation
+}
+
+// operationID returns id, or the handler's method name, made unique by
+// the HTTP method when one handler serves several routes
+func (s *Spec) operationID(method, id string, handler gin.HandlerFunc) string {
+	if id == "" {
+		id = runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+		id = strings.TrimSuffix(id[strings.LastIndex(id, ".")+1:], "-fm")
+	}
+	for _, item := range s.doc.Paths {
+		for _, op := range *item {
+			if op.OperationID == id {
+				return id + strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+			}
+		}
+	}
+	return id
+}
+
+// queryParams describes the form-tagged fields of a query struct
+func (s *Spec) queryParams(query any) []Parameter {
+	if query == nil {
+		return nil
+	}
+	var params []Parameter
+	for _, f := range fields(reflect.TypeOf(query), "form") {
+		schema := s.schemas.typeSchema(f.typ)
+		applyBinding(schema, f.binding)
+		if f.def != "" {
+			schema.Default = defaultValue(schema.Type, f.def)
+		}
+		params = append(params, Parameter{Name: f.name, In: "query", Required: f.required, Schema: schema})
+	}
+	return params
+}
+
+// responses lists the success response and the error responses the route
+// can produce given its inputs
+func (s *Spec) responses(op Op, hasPathParams bool) map[string]*Response {
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+		if op.Response == nil {
+			status = http.StatusNoContent
+		}
+	}
+
+	success := &Response{Description: http.StatusText(status)}
+	if op.Response != nil {
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success.Content = map[string]MediaType{contentType: {Schema: s.schemas.schema(op.Response)}}
+	}
+
+	responses := map[string]*Response{strconv.Itoa(status): success}
+	errorResponse := func(status int) {
+		responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{"application/json": {Schema: s.errorBody}},
+		}
+	}
+	if op.Body != nil || op.Query != nil || len(op.Params) > 0 || hasPathParams {
+		errorResponse(http.StatusBadRequest)
+	}
+	if hasPathParams {
+		errorResponse(http.StatusNotFound)
+	}
+	for _, status := range op.Errors {
+		errorResponse(status)
+	}
+	errorResponse(http.StatusInternalServerError)
+	return responses
+}
+
+// pathParams converts gin's :name and *name segments to OpenAPI {name}
+// segments and describes them; names ending in "id" are integers
+func pathParams(path string) (string, []Parameter) {
+	segments := strings.Split(path, "/")
+	var params []Parameter
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "_id") || name == "version" {
+			schema = &Schema{Type: "integer", Format: "int64"}
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		segments[i] = "{" + name + "}"
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func joinPath(base, path string) string {
+	return strings.TrimSuffix(base, "/") + path
+}
+
+func defaultValue(typ, value string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
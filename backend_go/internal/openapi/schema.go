@@ -0,0 +1,226 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema used by OpenAPI 3.0
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// generator turns Go types into schemas, collecting named structs as
+// components so they are described once and referenced everywhere
+type generator struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{components: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+// schema returns the schema of v's type, or nil when v is nil
+func (g *generator) schema(v any) *Schema {
+	if v == nil {
+		return nil
+	}
+	return g.typeSchema(reflect.TypeOf(v))
+}
+
+func (g *generator) typeSchema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{Description: "Free-form JSON"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.typeSchema(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t)}
+	}
+	return &Schema{}
+}
+
+// component registers a named struct and returns its component name,
+// qualifying it with the package name when two packages share a type name
+func (g *generator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	for other := range g.names {
+		if other.Name() == name {
+			pkg := t.PkgPath()
+			pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+			name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+			break
+		}
+	}
+	g.names[t] = name
+	g.components[name] = &Schema{}
+	*g.components[name] = *g.structSchema(t)
+	return name
+}
+
+// structSchema describes a struct through its JSON fields, inlining
+// embedded structs the way encoding/json does
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range fields(t, "json") {
+		prop := g.typeSchema(field.typ)
+		if prop.Ref == "" {
+			applyBinding(prop, field.binding)
+		}
+		s.Properties[field.name] = prop
+		if field.required {
+			s.Required = append(s.Required, field.name)
+		}
+	}
+	return s
+}
+
+// field is a struct field as seen by a JSON or form binding
+type field struct {
+	name     string
+	typ      reflect.Type
+	binding  []string
+	required bool
+	def      string
+}
+
+// fields lists t's exported fields under the names given by tag ("json"
+// or "form"), descending into embedded structs
+func fields(t reflect.Type, tag string) []field {
+	var out []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			out = append(out, fields(f.Type, tag)...)
+			continue
+		}
+		if name == "" {
+			if tag == "form" {
+				continue
+			}
+			name = f.Name
+		}
+
+		binding := strings.Split(f.Tag.Get("binding"), ",")
+		fd := field{name: name, typ: f.Type, binding: binding}
+		for _, rule := range binding {
+			if rule == "required" {
+				fd.required = true
+			}
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			if value, ok := strings.CutPrefix(opt, "default="); ok {
+				fd.def = value
+			}
+		}
+		out = append(out, fd)
+	}
+	return out
+}
+
+// applyBinding carries validator rules over to the schema so clients see
+// the same limits the handlers enforce
+func applyBinding(s *Schema, rules []string) {
+	for _, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				s.Enum = append(s.Enum, option)
+			}
+		case "min", "max", "gte", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			low := key == "min" || key == "gte"
+			switch s.Type {
+			case "integer", "number":
+				if low {
+					s.Minimum = &n
+				} else {
+					s.Maximum = &n
+				}
+			case "string":
+				setLimit(&s.MinLength, &s.MaxLength, low, int(n))
+			case "array":
+				setLimit(&s.MinItems, &s.MaxItems, low, int(n))
+			}
+		}
+	}
+}
+
+func setLimit(min, max **int, low bool, n int) {
+	if low {
+		*min = &n
+	} else {
+		*max = &n
+	}
+}
@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// initializer replaces the bundled swagger-initializer.js, which points at
+// the Swagger petstore, with one that loads the given document
+const initializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: %q,
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// UI serves the embedded Swagger UI for the document at specURL; register
+// it on a wildcard route such as /docs/*filepath
+func UI(specURL string) gin.HandlerFunc {
+	files := http.FS(swaggerFiles.FS)
+	return func(c *gin.Context) {
+		switch name := strings.TrimPrefix(c.Param("filepath"), "/"); name {
+		case "", "index.html":
+			c.FileFromFS("/", files)
+		case "swagger-initializer.js":
+			c.Header("Content-Type", "application/javascript")
+			c.String(http.StatusOK, initializer, specURL)
+		default:
+			c.FileFromFS(name, files)
+		}
+	}
+}
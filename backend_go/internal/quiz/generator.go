@@ -0,0 +1,140 @@
+package quiz
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+const (
+	// DefaultSize is the number of questions when none is requested
+	DefaultSize = 10
+	// MaxSize is the largest quiz that can be generated
+	MaxSize = 50
+	// DefaultChoices is the number of options per question, including the answer
+	DefaultChoices = 4
+	// MaxChoices is the largest number of options per question
+	MaxChoices = 6
+)
+
+var (
+	// ErrInvalidDirection is returned for an unknown quiz direction
+	ErrInvalidDirection = errors.New("invalid direction")
+	// ErrNotEnoughWords is returned when a group cannot fill a question
+	ErrNotEnoughWords = errors.New("group needs at least two words")
+)
+
+// Options controls quiz generation
+type Options struct {
+	Size      int
+	Choices   int
+	Direction string
+}
+
+// Generate builds multiple-choice questions from a group's words. Each
+// question's distractors prefer words with the same part of speech, then
+// words previously confused with the answer, then words the learner often
+// gets wrong; remaining ties are broken randomly.
+func Generate(candidates []models.QuizCandidate, confusions []models.WordConfusion, opts Options, rng *rand.Rand) ([]models.QuizQuestion, error) {
+	if !ValidDirection(opts.Direction) {
+		return nil, ErrInvalidDirection
+	}
+	if len(candidates) < 2 {
+		return nil, ErrNotEnoughWords
+	}
+
+	size := opts.Size
+	if size > len(candidates) {
+		size = len(candidates)
+	}
+
+	confused := make(map[[2]int64]int)
+	for _, c := range confusions {
+		confused[[2]int64{c.WordID, c.ConfusedWithID}] += c.Count
+		confused[[2]int64{c.ConfusedWithID, c.WordID}] += c.Count
+	}
+
+	order := rng.Perm(len(candidates))[:size]
+	questions := make([]models.QuizQuestion, 0, size)
+	for i, idx := range order {
+		target := candidates[idx]
+		prompt, answer := texts(target.Word, opts.Direction)
+
+		options := []models.QuizOption{{WordID: target.Word.ID, Text: answer}}
+		for _, d := range distractors(target, candidates, confused, opts, rng) {
+			_, text := texts(d.Word, opts.Direction)
+			options = append(options, models.QuizOption{WordID: d.Word.ID, Text: text})
+		}
+		rng.Shuffle(len(options), func(a, b int) { options[a], options[b] = options[b], options[a] })
+
+		questions = append(questions, models.QuizQuestion{
+			Position: i + 1,
+			WordID:   target.Word.ID,
+			Prompt:   prompt,
+			Options:  options,
+		})
+	}
+
+	return questions, nil
+}
+
+// distractors ranks the other candidates for a target and returns the best
+func distractors(target models.QuizCandidate, candidates []models.QuizCandidate, confused map[[2]int64]int, opts Options, rng *rand.Rand) []models.QuizCandidate {
+	_, answer := texts(target.Word, opts.Direction)
+	pos := target.Word.PartOfSpeech()
+
+	pool := make([]models.QuizCandidate, 0, len(candidates))
+	seen := map[string]bool{answer: true}
+	for _, c := range rng.Perm(len(candidates)) {
+		candidate := candidates[c]
+		_, text := texts(candidate.Word, opts.Direction)
+		if candidate.Word.ID == target.Word.ID || seen[text] {
+			continue
+		}
+		seen[text] = true
+		pool = append(pool, candidate)
+	}
+
+	samePOS := func(c models.QuizCandidate) bool {
+		return pos != "" && c.Word.PartOfSpeech() == pos
+	}
+	sort.SliceStable(pool, func(a, b int) bool {
+		if samePOS(pool[a]) != samePOS(pool[b]) {
+			return samePOS(pool[a])
+		}
+		ca := confused[[2]int64{target.Word.ID, pool[a].Word.ID}]
+		cb := confused[[2]int64{target.Word.ID, pool[b].Word.ID}]
+		if ca != cb {
+			return ca > cb
+		}
+		return pool[a].Mistakes > pool[b].Mistakes
+	})
+
+	if len(pool) > opts.Choices-1 {
+		pool = pool[:opts.Choices-1]
+	}
+	return pool
+}
+
+// ValidDirection reports whether direction is a supported quiz direction
+func ValidDirection(direction string) bool {
+	switch direction {
+	case models.DirectionArabicToEnglish, models.DirectionEnglishToArabic, models.DirectionRomajiToArabic:
+		return true
+	}
+	return false
+}
+
+// texts returns the prompt and answer text of a word for a direction
+func texts(word models.Word, direction string) (prompt, answer string) {
+	switch direction {
+	case models.DirectionEnglishToArabic:
+		return word.English, word.Arabic
+	case models.DirectionRomajiToArabic:
+		return word.Romaji, word.Arabic
+	default:
+		return word.Arabic, word.English
+	}
+}
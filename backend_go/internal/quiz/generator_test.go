@@ -0,0 +1,142 @@
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// candidate returns a quiz candidate with an English text, part of speech
+// and mistake count
+func candidate(id int64, english, pos string, mistakes int) models.QuizCandidate {
+	word := models.Word{ID: id, English: english, Arabic: "ar-" + english, Romaji: "ro-" + english}
+	if pos != "" {
+		word.Parts = json.RawMessage(`{"part_of_speech":"` + pos + `"}`)
+	}
+	return models.QuizCandidate{Word: word, Mistakes: mistakes}
+}
+
+func TestGenerateRejectsBadInput(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	two := []models.QuizCandidate{candidate(1, "one", "", 0), candidate(2, "two", "", 0)}
+
+	if _, err := Generate(two, nil, Options{Size: 1, Choices: 2, Direction: "xx"}, rng); !errors.Is(err, ErrInvalidDirection) {
+		t.Errorf("unknown direction: err = %v, want ErrInvalidDirection", err)
+	}
+	if _, err := Generate(two[:1], nil, Options{Size: 1, Choices: 2, Direction: models.DirectionArabicToEnglish}, rng); !errors.Is(err, ErrNotEnoughWords) {
+		t.Errorf("one word: err = %v, want ErrNotEnoughWords", err)
+	}
+}
+
+func TestGenerateBuildsQuestions(t *testing.T) {
+	candidates := []models.QuizCandidate{
+		candidate(1, "one", "", 0),
+		candidate(2, "two", "", 0),
+		candidate(3, "three", "", 0),
+		candidate(4, "four", "", 0),
+		candidate(5, "four", "", 0), // same answer text as word 4
+	}
+	for _, direction := range []string{models.DirectionArabicToEnglish, models.DirectionEnglishToArabic, models.DirectionRomajiToArabic} {
+		questions, err := Generate(candidates, nil, Options{Size: 10, Choices: 3, Direction: direction}, rand.New(rand.NewSource(7)))
+		if err != nil {
+			t.Fatalf("%s: Generate: %v", direction, err)
+		}
+		if len(questions) != len(candidates) {
+			t.Fatalf("%s: %d questions, want the size capped at %d", direction, len(questions), len(candidates))
+		}
+
+		asked := map[int64]bool{}
+		for i, question := range questions {
+			if question.Position != i+1 || asked[question.WordID] {
+				t.Errorf("%s: question %d is at position %d for word %d, asked before: %v", direction, i, question.Position, question.WordID, asked[question.WordID])
+			}
+			asked[question.WordID] = true
+
+			target := candidates[question.WordID-1].Word
+			prompt, answer := texts(target, direction)
+			if question.Prompt != prompt {
+				t.Errorf("%s: prompt %q, want %q", direction, question.Prompt, prompt)
+			}
+			if len(question.Options) != 3 {
+				t.Errorf("%s: %d options, want 3", direction, len(question.Options))
+			}
+			seen := map[string]bool{}
+			hasAnswer := false
+			for _, option := range question.Options {
+				if seen[option.Text] {
+					t.Errorf("%s: option %q offered twice", direction, option.Text)
+				}
+				seen[option.Text] = true
+				if option.WordID == target.ID && option.Text == answer {
+					hasAnswer = true
+				}
+			}
+			if !hasAnswer {
+				t.Errorf("%s: options %+v miss the answer %q", direction, question.Options, answer)
+			}
+		}
+	}
+}
+
+func TestGenerateRanksDistractors(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		candidates []models.QuizCandidate
+		confusions []models.WordConfusion
+		want       int64
+	}{
+		{
+			name: "same part of speech first",
+			candidates: []models.QuizCandidate{
+				candidate(1, "run", "verb", 0),
+				candidate(2, "walk", "verb", 0),
+				candidate(3, "house", "noun", 9),
+				candidate(4, "cat", "noun", 0),
+			},
+			confusions: []models.WordConfusion{{WordID: 4, ConfusedWithID: 1, Count: 5}},
+			want:       2,
+		},
+		{
+			name: "then confusions in either direction",
+			candidates: []models.QuizCandidate{
+				candidate(1, "run", "", 0),
+				candidate(2, "walk", "", 9),
+				candidate(3, "house", "", 0),
+			},
+			confusions: []models.WordConfusion{{WordID: 3, ConfusedWithID: 1, Count: 2}},
+			want:       3,
+		},
+		{
+			name: "then mistakes",
+			candidates: []models.QuizCandidate{
+				candidate(1, "run", "", 0),
+				candidate(2, "walk", "", 1),
+				candidate(3, "house", "", 4),
+				candidate(4, "cat", "", 0),
+			},
+			want: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				questions, err := Generate(tc.candidates, tc.confusions, Options{Size: len(tc.candidates), Choices: 2, Direction: models.DirectionArabicToEnglish}, rand.New(rand.NewSource(seed)))
+				if err != nil {
+					t.Fatalf("Generate: %v", err)
+				}
+				for _, question := range questions {
+					if question.WordID != 1 {
+						continue
+					}
+					for _, option := range question.Options {
+						if option.WordID != 1 && option.WordID != tc.want {
+							t.Fatalf("seed %d: distractor %d, want %d", seed, option.WordID, tc.want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
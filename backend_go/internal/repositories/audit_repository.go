@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+type auditContextKey struct{}
+
+// AuditInfo identifies who made a change and in which request
+type AuditInfo struct {
+	Actor     string
+	RequestID string
+}
+
+// WithAuditInfo returns a context whose mutations are attributed to info
+func WithAuditInfo(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, info)
+}
+
+// auditInfoFromContext returns the audit attribution stored on ctx
+func auditInfoFromContext(ctx context.Context) AuditInfo {
+	info, _ := ctx.Value(auditContextKey{}).(AuditInfo)
+	return info
+}
+
+// recordAudit appends an audit event for an entity inside tx. before and
+// after are snapshots of the entity and may be nil for creates and deletes.
+func recordAudit(ctx context.Context, tx *sql.Tx, entityType string, entityID int64, action string, before, after interface{}) error {
+	beforeJSON, err := snapshotJSON(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := snapshotJSON(after)
+	if err != nil {
+		return err
+	}
+
+	info := auditInfoFromContext(ctx)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_events (entity_type, entity_id, version, action, actor, request_id, before, after)
+		VALUES (
+			?, ?,
+			(SELECT COALESCE(MAX(version), 0) + 1 FROM audit_events WHERE entity_type = ? AND entity_id = ?),
+			?, NULLIF(?, ''), NULLIF(?, ''), ?, ?
+		)
+	`, entityType, entityID, entityType, entityID, action, info.Actor, info.RequestID, beforeJSON, afterJSON)
+	if err != nil {
+		return fmt.Errorf("error recording audit event: %v", err)
+	}
+
+	return nil
+}
+
+// snapshotJSON encodes an entity snapshot, mapping nil to NULL
+func snapshotJSON(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding audit snapshot: %v", err)
+	}
+	return string(data), nil
+}
+
+// GetAuditHistory returns all audit events of an entity, oldest first
+func (r *SQLiteRepository) GetAuditHistory(ctx context.Context, entityType string, entityID int64) (_ []models.AuditEvent, err error) {
+	ctx, op := instrument(ctx, "GetAuditHistory")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, entity_type, entity_id, version, action,
+			COALESCE(actor, ''), COALESCE(request_id, ''),
+			COALESCE(before, 'null'), COALESCE(after, 'null'), created_at
+		FROM audit_events
+		WHERE entity_type = ? AND entity_id = ?
+		ORDER BY version
+	`, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying audit events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit events: %v", err)
+	}
+
+	op.rows(int64(len(events)))
+	return events, nil
+}
+
+// getAuditEvent returns a single version of an entity's history
+func getAuditEvent(ctx context.Context, tx *sql.Tx, entityType string, entityID int64, version int) (*models.AuditEvent, error) {
+	row := tx.QueryRowContext(ctx, `
+		SELECT id, entity_type, entity_id, version, action,
+			COALESCE(actor, ''), COALESCE(request_id, ''),
+			COALESCE(before, 'null'), COALESCE(after, 'null'), created_at
+		FROM audit_events
+		WHERE entity_type = ? AND entity_id = ? AND version = ?
+	`, entityType, entityID, version)
+
+	event, err := scanAuditEvent(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return event, err
+}
+
+// scanAuditEvent reads an audit event from a row
+func scanAuditEvent(row interface{ Scan(...interface{}) error }) (*models.AuditEvent, error) {
+	var event models.AuditEvent
+	var before, after string
+	err := row.Scan(
+		&event.ID,
+		&event.EntityType,
+		&event.EntityID,
+		&event.Version,
+		&event.Action,
+		&event.Actor,
+		&event.RequestID,
+		&before,
+		&after,
+		&event.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning audit event: %v", err)
+	}
+
+	event.Before = json.RawMessage(before)
+	event.After = json.RawMessage(after)
+	return &event, nil
+}
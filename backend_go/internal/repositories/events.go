@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/events"
+)
+
+// eventQueue collects the events raised inside a transaction so they are
+// only published once it commits
+type eventQueue []events.Event
+
+// add queues an event
+func (q *eventQueue) add(event events.Event) {
+	*q = append(*q, event)
+}
+
+// raise queues an event and writes its webhook deliveries to the outbox in
+// the same transaction, so they survive a restart
+func (q *eventQueue) raise(ctx context.Context, tx *sql.Tx, event events.Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if err := enqueueWebhooks(ctx, tx, event); err != nil {
+		return err
+	}
+	q.add(event)
+	return nil
+}
+
+// publish sends the queued events to subscribers
+func (q eventQueue) publish() {
+	for _, event := range q {
+		events.Publish(event)
+	}
+}
@@ -0,0 +1,415 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/events"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/gamification"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// learnerEvent is a review or session credited to a learner, in the group
+// it was studied in (0 if none) and at the time it happened
+type learnerEvent struct {
+	learner string
+	groupID int64
+	at      time.Time
+}
+
+// onReviewRecorded runs everything a new review triggers for the learner:
+// rescheduling the word, updating leaderboards, awarding XP, unlocking
+// achievements and raising review.recorded and group.mastered events.
+// Anonymous reviews earn no XP or achievements.
+func onReviewRecorded(ctx context.Context, tx *sql.Tx, queue *eventQueue, review *models.WordReviewItem) error {
+	if err := scheduleReview(ctx, tx, review); err != nil {
+		return err
+	}
+
+	event := learnerEvent{learner: learnerFromContext(ctx), at: review.CreatedAt}
+	err := tx.QueryRowContext(ctx, "SELECT COALESCE((SELECT group_id FROM study_sessions WHERE id = ?), 0)",
+		review.StudySessionID).Scan(&event.groupID)
+	if err != nil {
+		return fmt.Errorf("error querying study session group: %v", err)
+	}
+
+	err = queue.raise(ctx, tx, events.Event{
+		Type:      events.ReviewRecorded,
+		SessionID: review.StudySessionID,
+		GroupID:   event.groupID,
+		LearnerID: event.learner,
+		Data:      review,
+	})
+	if err != nil {
+		return err
+	}
+
+	mastered, err := updateWordProgress(ctx, tx, event.learner, review)
+	if err != nil {
+		return err
+	}
+	if mastered > 0 && event.learner != "" {
+		if err := raiseGroupsMastered(ctx, tx, queue, event.learner, review.WordID); err != nil {
+			return err
+		}
+	}
+	delta := leaderboardDelta{reviews: 1, wordsMastered: mastered}
+	if review.IsCorrect {
+		delta.correct = 1
+	}
+	if err := bumpLeaderboards(ctx, tx, event, delta); err != nil {
+		return err
+	}
+
+	if event.learner == "" {
+		return nil
+	}
+	if err := recordReviewDay(ctx, tx, event); err != nil {
+		return err
+	}
+	if err := awardXP(ctx, tx, event, models.XPSourceReview, review.ID, gamification.ReviewXP(review.IsCorrect)); err != nil {
+		return err
+	}
+
+	facts, err := learnerFacts(ctx, tx, event.learner)
+	if err != nil {
+		return err
+	}
+
+	if review.IsCorrect {
+		facts.CompletedRoots, err = completedRoots(ctx, tx, event.learner, review.WordID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return unlockAchievements(ctx, tx, event, gamification.Evaluate(facts))
+}
+
+// onSessionStarted raises a session.started event and, unless the learner
+// is anonymous, awards XP for the session and re-checks their achievements
+func onSessionStarted(ctx context.Context, tx *sql.Tx, queue *eventQueue, session *models.StudySession) error {
+	event := learnerEvent{learner: learnerFromContext(ctx), groupID: session.GroupID, at: session.CreatedAt}
+	err := queue.raise(ctx, tx, events.Event{
+		Type:      events.SessionStarted,
+		SessionID: session.ID,
+		GroupID:   session.GroupID,
+		LearnerID: event.learner,
+		Data:      session,
+	})
+	if err != nil {
+		return err
+	}
+
+	if event.learner == "" {
+		return nil
+	}
+	if err := awardXP(ctx, tx, event, models.XPSourceSession, session.ID, gamification.XPSessionStarted); err != nil {
+		return err
+	}
+
+	facts, err := learnerFacts(ctx, tx, event.learner)
+	if err != nil {
+		return err
+	}
+	return unlockAchievements(ctx, tx, event, gamification.Evaluate(facts))
+}
+
+// onSessionFinished raises a session.finished event and judges the
+// finished session for the achievements of the learner who started it
+func onSessionFinished(ctx context.Context, tx *sql.Tx, queue *eventQueue, session *models.StudySession, learner string) error {
+	event := learnerEvent{learner: learner, groupID: session.GroupID, at: time.Now()}
+	if session.FinishedAt != nil {
+		event.at = *session.FinishedAt
+	}
+	err := queue.raise(ctx, tx, events.Event{
+		Type:      events.SessionFinished,
+		SessionID: session.ID,
+		GroupID:   session.GroupID,
+		LearnerID: learner,
+		Data:      session,
+	})
+	if err != nil {
+		return err
+	}
+
+	if learner == "" {
+		return nil
+	}
+
+	// Only the session rules can be met by facts about the session alone
+	var facts gamification.Facts
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN is_correct THEN 0 ELSE 1 END), 0)
+		FROM word_review_items
+		WHERE study_session_id = ? AND learner_id = ?
+	`, session.ID, learner).Scan(&facts.SessionReviews, &facts.SessionWrong)
+	if err != nil {
+		return fmt.Errorf("error querying session reviews: %v", err)
+	}
+	return unlockAchievements(ctx, tx, event, gamification.Evaluate(facts))
+}
+
+// awardXP adds an entry to the learner's XP ledger and leaderboards unless
+// the source already paid out
+func awardXP(ctx context.Context, tx *sql.Tx, event learnerEvent, source string, sourceID interface{}, xp int) error {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO xp_events (learner_id, source, source_id, xp, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(learner_id, source, source_id) DO NOTHING
+	`, event.learner, source, fmt.Sprint(sourceID), xp, event.at.UTC().Format(scheduleTimeLayout))
+	if err != nil {
+		return fmt.Errorf("error awarding xp: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	return bumpLeaderboards(ctx, tx, event, leaderboardDelta{xp: xp})
+}
+
+// unlockAchievements stores the achievements the learner does not hold
+// yet and pays out their XP
+func unlockAchievements(ctx context.Context, tx *sql.Tx, event learnerEvent, achievements []models.Achievement) error {
+	for _, achievement := range achievements {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO learner_achievements (learner_id, code, detail, xp) VALUES (?, ?, ?, ?)
+			ON CONFLICT(learner_id, code, detail) DO NOTHING
+		`, event.learner, achievement.Code, achievement.Detail, achievement.XP)
+		if err != nil {
+			return fmt.Errorf("error unlocking achievement: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			continue
+		}
+
+		if err := awardXP(ctx, tx, event, models.XPSourceAchievement, achievement.Code+":"+achievement.Detail, achievement.XP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// learnerFacts gathers the facts about a learner's history that the
+// achievement rules use, from the running tallies kept for each review
+func learnerFacts(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}, learner string) (gamification.Facts, error) {
+	var facts gamification.Facts
+	err := q.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM learner_word_progress
+		WHERE learner_id = ? AND correct_count > 0
+	`, learner).Scan(&facts.WordsLearned)
+	if err != nil {
+		return facts, fmt.Errorf("error counting learned words: %v", err)
+	}
+
+	facts.StreakDays, err = streakDays(ctx, q, learner)
+	return facts, err
+}
+
+// recordReviewDay adds the UTC day of a review to the learner's review days
+func recordReviewDay(ctx context.Context, tx *sql.Tx, event learnerEvent) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO learner_review_days (learner_id, day) VALUES (?, ?)
+		ON CONFLICT(learner_id, day) DO NOTHING
+	`, event.learner, event.at.UTC().Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("error recording review day: %v", err)
+	}
+	return nil
+}
+
+// streakDays counts the learner's consecutive UTC days with reviews ending
+// today, or ending yesterday when there are none yet today. It walks back
+// one day at a time, so it reads no more days than the streak holds.
+func streakDays(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}, learner string) (int, error) {
+	var streak int
+	err := q.QueryRowContext(ctx, `
+		WITH RECURSIVE streak (day) AS (
+			SELECT MAX(day)
+			FROM learner_review_days
+			WHERE learner_id = :learner AND day IN (date('now'), date('now', '-1 day'))
+			UNION ALL
+			SELECT date(streak.day, '-1 day')
+			FROM streak
+			WHERE EXISTS (
+				SELECT 1 FROM learner_review_days
+				WHERE learner_id = :learner AND day = date(streak.day, '-1 day')
+			)
+		)
+		SELECT COUNT(day) FROM streak
+	`, sql.Named("learner", learner)).Scan(&streak)
+	if err != nil {
+		return 0, fmt.Errorf("error counting streak days: %v", err)
+	}
+	return streak, nil
+}
+
+// completedRoots returns the root of a word when the learner has answered
+// every live word sharing it correctly. Roots are read from parts.root.
+func completedRoots(ctx context.Context, tx *sql.Tx, learner string, wordID int64) ([]string, error) {
+	var root sql.NullString
+	err := tx.QueryRowContext(ctx, `
+		SELECT CASE WHEN json_valid(parts) THEN json_extract(parts, '$.root') END
+		FROM words
+		WHERE id = ?
+	`, wordID).Scan(&root)
+	if err != nil {
+		return nil, fmt.Errorf("error querying word root: %v", err)
+	}
+	if !root.Valid || root.String == "" {
+		return nil, nil
+	}
+
+	var total, learned int
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(EXISTS(
+				SELECT 1 FROM word_review_items wri
+				WHERE wri.word_id = w.id AND wri.learner_id = ? AND wri.is_correct
+			)), 0)
+		FROM words w
+		WHERE w.deleted_at IS NULL
+			AND json_valid(w.parts)
+			AND json_extract(w.parts, '$.root') = ?
+	`, learner, root.String).Scan(&total, &learned)
+	if err != nil {
+		return nil, fmt.Errorf("error querying root family: %v", err)
+	}
+
+	if total < gamification.MinRootFamily || learned < total {
+		return nil, nil
+	}
+	return []string{root.String}, nil
+}
+
+// GetAchievements returns the learner's XP, review streak and unlocked
+// achievements
+func (r *SQLiteRepository) GetAchievements(ctx context.Context) (_ *models.Achievements, err error) {
+	ctx, op := instrument(ctx, "GetAchievements")
+	defer func() { op.end(err) }()
+
+	learner := learnerFromContext(ctx)
+	summary := &models.Achievements{}
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(xp), 0),
+			COALESCE(SUM(CASE WHEN created_at >= date('now') THEN xp ELSE 0 END), 0)
+		FROM xp_events
+		WHERE learner_id = ?
+	`, learner).Scan(&summary.XP, &summary.XPToday)
+	if err != nil {
+		return nil, fmt.Errorf("error querying xp: %v", err)
+	}
+
+	summary.StreakDays, err = streakDays(ctx, r.db, learner)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT code, detail, xp, unlocked_at
+		FROM learner_achievements
+		WHERE learner_id = ?
+		ORDER BY unlocked_at, code, detail
+	`, learner)
+	if err != nil {
+		return nil, fmt.Errorf("error querying achievements: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var achievement models.Achievement
+		var unlockedAt time.Time
+		if err := rows.Scan(&achievement.Code, &achievement.Detail, &achievement.XP, &unlockedAt); err != nil {
+			return nil, fmt.Errorf("error scanning achievement: %v", err)
+		}
+		achievement.UnlockedAt = &unlockedAt
+		summary.Achievements = append(summary.Achievements, achievement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating achievements: %v", err)
+	}
+
+	op.rows(int64(len(summary.Achievements)))
+	return summary, nil
+}
+
+// GetGoalSettings returns the learner's daily goals, or the defaults if
+// they have not set any
+func (r *SQLiteRepository) GetGoalSettings(ctx context.Context) (_ models.GoalSettings, err error) {
+	ctx, op := instrument(ctx, "GetGoalSettings")
+	defer func() { op.end(err) }()
+
+	settings := models.GoalSettings{
+		DailyReviews: gamification.DefaultDailyReviews,
+		DailyMinutes: gamification.DefaultDailyMinutes,
+	}
+	err = r.db.QueryRowContext(ctx, `
+		SELECT daily_reviews, daily_minutes FROM learner_goals WHERE learner_id = ?
+	`, learnerFromContext(ctx)).Scan(&settings.DailyReviews, &settings.DailyMinutes)
+	if err != nil && err != sql.ErrNoRows {
+		return settings, fmt.Errorf("error querying goals: %v", err)
+	}
+	return settings, nil
+}
+
+// SetGoalSettings stores the learner's daily goals
+func (r *SQLiteRepository) SetGoalSettings(ctx context.Context, settings models.GoalSettings) (err error) {
+	ctx, op := instrument(ctx, "SetGoalSettings")
+	defer func() { op.end(err) }()
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO learner_goals (learner_id, daily_reviews, daily_minutes) VALUES (?, ?, ?)
+		ON CONFLICT(learner_id) DO UPDATE SET
+			daily_reviews = excluded.daily_reviews,
+			daily_minutes = excluded.daily_minutes
+	`, learnerFromContext(ctx), settings.DailyReviews, settings.DailyMinutes)
+	if err != nil {
+		return fmt.Errorf("error storing goals: %v", err)
+	}
+	return nil
+}
+
+// GetDailyActivity returns the learner's reviews and minutes spent
+// answering per day over the last days days
+func (r *SQLiteRepository) GetDailyActivity(ctx context.Context, days int) (_ []models.DailyActivity, err error) {
+	ctx, op := instrument(ctx, "GetDailyActivity")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT date(created_at), COUNT(*), COALESCE(SUM(response_ms), 0) / 60000.0
+		FROM word_review_items
+		WHERE learner_id = ? AND created_at >= date('now', ?)
+		GROUP BY date(created_at)
+		ORDER BY date(created_at)
+	`, learnerFromContext(ctx), "-"+strconv.Itoa(days-1)+" days")
+	if err != nil {
+		return nil, fmt.Errorf("error querying daily activity: %v", err)
+	}
+	defer rows.Close()
+
+	var activity []models.DailyActivity
+	for rows.Next() {
+		var day models.DailyActivity
+		if err := rows.Scan(&day.Date, &day.Reviews, &day.Minutes); err != nil {
+			return nil, fmt.Errorf("error scanning daily activity: %v", err)
+		}
+		activity = append(activity, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily activity: %v", err)
+	}
+
+	op.rows(int64(len(activity)))
+	return activity, nil
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/gamification"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// unlocked returns the codes of the learner's unlocked achievements
+func unlocked(t *testing.T, r *SQLiteRepository, ctx context.Context) map[string]bool {
+	t.Helper()
+	summary, err := r.GetAchievements(ctx)
+	if err != nil {
+		t.Fatalf("GetAchievements: %v", err)
+	}
+	codes := map[string]bool{}
+	for _, achievement := range summary.Achievements {
+		codes[achievement.Code] = true
+	}
+	return codes
+}
+
+func TestPerfectSessionUnlocksWhenFinished(t *testing.T) {
+	r := newTestRepository(t)
+	alice := asLearner("alice")
+
+	perfect := createSession(t, r, alice, 1)
+	for i := 0; i < gamification.PerfectSessionReviews; i++ {
+		review(t, r, alice, perfect.ID, int64(i%5+1), true)
+	}
+	if unlocked(t, r, alice)[models.AchievementPerfectSession] {
+		t.Fatal("perfect session unlocked before the session finished")
+	}
+
+	if _, err := r.FinishStudySession(alice, perfect.ID); err != nil {
+		t.Fatalf("FinishStudySession: %v", err)
+	}
+	if !unlocked(t, r, alice)[models.AchievementPerfectSession] {
+		t.Error("perfect session not unlocked when the session finished")
+	}
+
+	bob := asLearner("bob")
+	flawed := createSession(t, r, bob, 1)
+	for i := 0; i < gamification.PerfectSessionReviews; i++ {
+		review(t, r, bob, flawed.ID, int64(i%5+1), i != 0)
+	}
+	if _, err := r.FinishStudySession(bob, flawed.ID); err != nil {
+		t.Fatalf("FinishStudySession: %v", err)
+	}
+	if unlocked(t, r, bob)[models.AchievementPerfectSession] {
+		t.Error("perfect session unlocked for a session with a mistake")
+	}
+}
+
+func TestAnonymousLearnerEarnsNothing(t *testing.T) {
+	r := newTestRepository(t)
+	anonymous := context.Background()
+
+	session := createSession(t, r, anonymous, 1)
+	for i := 0; i < gamification.PerfectSessionReviews; i++ {
+		review(t, r, anonymous, session.ID, int64(i%5+1), true)
+	}
+	if _, err := r.FinishStudySession(anonymous, session.ID); err != nil {
+		t.Fatalf("FinishStudySession: %v", err)
+	}
+
+	var xp, achievements, days int
+	err := r.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM xp_events WHERE learner_id = ''),
+			(SELECT COUNT(*) FROM learner_achievements WHERE learner_id = ''),
+			(SELECT COUNT(*) FROM learner_review_days WHERE learner_id = '')
+	`).Scan(&xp, &achievements, &days)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xp != 0 || achievements != 0 || days != 0 {
+		t.Errorf("anonymous learner has %d XP events, %d achievements and %d review days, want none", xp, achievements, days)
+	}
+}
+
+func TestStreakDays(t *testing.T) {
+	r := newTestRepository(t)
+	alice := asLearner("alice")
+
+	streak := func(ctx context.Context) int {
+		t.Helper()
+		summary, err := r.GetAchievements(ctx)
+		if err != nil {
+			t.Fatalf("GetAchievements: %v", err)
+		}
+		return summary.StreakDays
+	}
+
+	// Yesterday and the two days before, then a gap
+	for _, offset := range []string{"-1 days", "-2 days", "-3 days", "-5 days"} {
+		mustExec(t, r, "INSERT INTO learner_review_days (learner_id, day) VALUES ('alice', date('now', ?))", offset)
+	}
+	if got := streak(alice); got != 3 {
+		t.Errorf("streak ending yesterday = %d, want 3", got)
+	}
+
+	// Reviewing today extends it, however many reviews there are
+	session := createSession(t, r, alice, 1)
+	review(t, r, alice, session.ID, 1, true)
+	review(t, r, alice, session.ID, 2, false)
+	if got := streak(alice); got != 4 {
+		t.Errorf("streak ending today = %d, want 4", got)
+	}
+
+	if got := streak(asLearner("bob")); got != 0 {
+		t.Errorf("bob's streak = %d, want 0", got)
+	}
+}
@@ -0,0 +1,468 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// The methods in this file load rows for many parents at once, so the
+// GraphQL API can batch what would otherwise be one query per object.
+// Pages are keyset pages ordered by ID; for nested lists each parent gets
+// its own page, numbered with ROW_NUMBER() so a single query serves them all.
+
+// sessionParents and reviewParents map PageBy values to the column that
+// holds the parent ID
+var (
+	sessionParents = map[string]string{models.PageByGroup: "group_id", models.PageByActivity: "study_activity_id"}
+	reviewParents  = map[string]string{models.PageByWord: "word_id", models.PageBySession: "study_session_id"}
+)
+
+// GetWordsByIDs returns the words with the given IDs that are not in the
+// trash, in no particular order
+func (r *SQLiteRepository) GetWordsByIDs(ctx context.Context, ids []int64) (_ []models.Word, err error) {
+	ctx, op := instrument(ctx, "GetWordsByIDs")
+	defer func() { op.end(err) }()
+
+	in, args := inList(ids)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, arabic, romaji, english, COALESCE(parts, ''), created_at
+		FROM words
+		WHERE deleted_at IS NULL AND id IN `+in, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying words: %v", err)
+	}
+	defer rows.Close()
+
+	words, err := scanWords(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(words)))
+	return words, nil
+}
+
+// GetGroupsByIDs returns the groups with the given IDs that are not in the
+// trash, in no particular order
+func (r *SQLiteRepository) GetGroupsByIDs(ctx context.Context, ids []int64) (_ []models.Group, err error) {
+	ctx, op := instrument(ctx, "GetGroupsByIDs")
+	defer func() { op.end(err) }()
+
+	in, args := inList(ids)
+	groups, err := r.groups(ctx, "id IN "+in, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(groups)))
+	return groups, nil
+}
+
+// GetStudyActivitiesByIDs returns the study activities with the given IDs
+// and their tallies, in no particular order
+func (r *SQLiteRepository) GetStudyActivitiesByIDs(ctx context.Context, ids []int64) (_ []models.StudyActivity, err error) {
+	ctx, op := instrument(ctx, "GetStudyActivitiesByIDs")
+	defer func() { op.end(err) }()
+
+	in, args := inList(ids)
+	activities, err := r.studyActivities(ctx, "sa.id IN "+in, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(activities)))
+	return activities, nil
+}
+
+// GetStudySessionsByIDs returns the study sessions with the given IDs and
+// their tallies, in no particular order
+func (r *SQLiteRepository) GetStudySessionsByIDs(ctx context.Context, ids []int64) (_ []models.StudySession, err error) {
+	ctx, op := instrument(ctx, "GetStudySessionsByIDs")
+	defer func() { op.end(err) }()
+
+	in, args := inList(ids)
+	sessions, err := r.studySessions(ctx, r.db, "ss.id IN "+in, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(sessions)))
+	return sessions, nil
+}
+
+// GetWordGroupsByWordIDs returns the group memberships of the given words,
+// each with its group, skipping groups in the trash
+func (r *SQLiteRepository) GetWordGroupsByWordIDs(ctx context.Context, wordIDs []int64) (_ []models.WordGroup, err error) {
+	ctx, op := instrument(ctx, "GetWordGroupsByWordIDs")
+	defer func() { op.end(err) }()
+
+	in, args := inList(wordIDs)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT wg.word_id, g.id, g.name, COALESCE(g.description, ''), g.created_at
+		FROM words_groups wg
+		JOIN groups g ON g.id = wg.group_id
+		WHERE g.deleted_at IS NULL AND wg.word_id IN `+in+`
+		ORDER BY wg.word_id, g.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying word groups: %v", err)
+	}
+	defer rows.Close()
+
+	var memberships []models.WordGroup
+	for rows.Next() {
+		var group models.Group
+		membership := models.WordGroup{Group: &group}
+		err := rows.Scan(&membership.WordID, &group.ID, &group.Name, &group.Description, &group.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning word group: %v", err)
+		}
+		membership.GroupID = group.ID
+		memberships = append(memberships, membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word groups: %v", err)
+	}
+
+	op.rows(int64(len(memberships)))
+	return memberships, nil
+}
+
+// PageWords returns a page of the words not in the trash, optionally
+// matching search, in ID order
+func (r *SQLiteRepository) PageWords(ctx context.Context, search string, page models.PageRequest) (_ []models.Word, err error) {
+	ctx, op := instrument(ctx, "PageWords")
+	defer func() { op.end(err) }()
+
+	where := "deleted_at IS NULL"
+	var filterArgs []interface{}
+	if search != "" {
+		where += " AND (arabic LIKE ? OR romaji LIKE ? OR english LIKE ?)"
+		pattern := "%" + search + "%"
+		filterArgs = append(filterArgs, pattern, pattern, pattern)
+	}
+	ids, args := pageIDs("words", where, filterArgs, "", nil, page, false)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, arabic, romaji, english, COALESCE(parts, ''), created_at
+		FROM words
+		WHERE id IN (`+ids+`)
+		ORDER BY id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying words: %v", err)
+	}
+	defer rows.Close()
+
+	words, err := scanWords(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(words)))
+	return words, nil
+}
+
+// PageGroupWords returns a page of the words of each group, in word ID
+// order, as memberships carrying the word
+func (r *SQLiteRepository) PageGroupWords(ctx context.Context, groupIDs []int64, page models.PageRequest) (_ []models.WordGroup, err error) {
+	ctx, op := instrument(ctx, "PageGroupWords")
+	defer func() { op.end(err) }()
+
+	in, args := inList(groupIDs)
+	cursor := ""
+	if page.After > 0 {
+		cursor = " AND w.id > ?"
+		args = append(args, page.After)
+	}
+	args = append(args, page.Limit)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT group_id, id, arabic, romaji, english, parts, created_at
+		FROM (
+			SELECT wg.group_id, w.id, w.arabic, w.romaji, w.english, COALESCE(w.parts, '') AS parts, w.created_at,
+				ROW_NUMBER() OVER (PARTITION BY wg.group_id ORDER BY w.id) AS page_row
+			FROM words_groups wg
+			JOIN words w ON w.id = wg.word_id
+			WHERE w.deleted_at IS NULL AND wg.group_id IN `+in+cursor+`
+		)
+		WHERE page_row <= ?
+		ORDER BY group_id, id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying group words: %v", err)
+	}
+	defer rows.Close()
+
+	var memberships []models.WordGroup
+	for rows.Next() {
+		var membership models.WordGroup
+		word, err := scanWord(rows, &membership.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		membership.WordID = word.ID
+		membership.Word = &word
+		memberships = append(memberships, membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group words: %v", err)
+	}
+
+	op.rows(int64(len(memberships)))
+	return memberships, nil
+}
+
+// PageGroups returns a page of the groups not in the trash, in ID order
+func (r *SQLiteRepository) PageGroups(ctx context.Context, page models.PageRequest) (_ []models.Group, err error) {
+	ctx, op := instrument(ctx, "PageGroups")
+	defer func() { op.end(err) }()
+
+	ids, args := pageIDs("groups", "deleted_at IS NULL", nil, "", nil, page, false)
+	groups, err := r.groups(ctx, "id IN ("+ids+")", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(groups)))
+	return groups, nil
+}
+
+// PageStudyActivities returns a page of study activities in ID order, for
+// each of the given groups or across all groups when groupIDs is nil
+func (r *SQLiteRepository) PageStudyActivities(ctx context.Context, groupIDs []int64, page models.PageRequest) (_ []models.StudyActivity, err error) {
+	ctx, op := instrument(ctx, "PageStudyActivities")
+	defer func() { op.end(err) }()
+
+	parent := ""
+	if groupIDs != nil {
+		parent = "group_id"
+	}
+	ids, args := pageIDs("study_activities", "", nil, parent, groupIDs, page, false)
+	activities, err := r.studyActivities(ctx, "sa.id IN ("+ids+")", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(activities)))
+	return activities, nil
+}
+
+// PageStudySessions returns a page of study sessions, newest first, for
+// each parent of the kind named by by (PageByGroup or PageByActivity), or
+// across the context learner's sessions when by is empty
+func (r *SQLiteRepository) PageStudySessions(ctx context.Context, by string, parentIDs []int64, page models.PageRequest) (_ []models.StudySession, err error) {
+	ctx, op := instrument(ctx, "PageStudySessions")
+	defer func() { op.end(err) }()
+
+	parent, ok := sessionParents[by]
+	if by != "" && !ok {
+		return nil, fmt.Errorf("%w: cannot page study sessions by %q", ErrInvalidInput, by)
+	}
+	where, whereArgs := "", []interface{}(nil)
+	if by == "" {
+		where, whereArgs = "learner_id = ?", []interface{}{learnerFromContext(ctx)}
+	}
+	ids, args := pageIDs("study_sessions", where, whereArgs, parent, parentIDs, page, true)
+	sessions, err := r.studySessions(ctx, r.db, "ss.id IN ("+ids+")", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(sessions)))
+	return sessions, nil
+}
+
+// PageWordReviews returns a page of reviews, newest first, for each parent
+// of the kind named by by (PageByWord or PageBySession)
+func (r *SQLiteRepository) PageWordReviews(ctx context.Context, by string, parentIDs []int64, page models.PageRequest) (_ []models.WordReviewItem, err error) {
+	ctx, op := instrument(ctx, "PageWordReviews")
+	defer func() { op.end(err) }()
+
+	parent, ok := reviewParents[by]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot page reviews by %q", ErrInvalidInput, by)
+	}
+	ids, args := pageIDs("word_review_items", "", nil, parent, parentIDs, page, true)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM word_review_items
+		WHERE id IN (`+ids+`)
+		ORDER BY id DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying word review items: %v", err)
+	}
+	defer rows.Close()
+
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(reviews)))
+	return reviews, nil
+}
+
+// groups returns the groups not in the trash matching where, in ID order
+func (r *SQLiteRepository) groups(ctx context.Context, where string, args ...interface{}) ([]models.Group, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, COALESCE(description, ''), created_at
+		FROM groups
+		WHERE deleted_at IS NULL AND `+where+`
+		ORDER BY id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying groups: %v", err)
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		var group models.Group
+		if err := rows.Scan(&group.ID, &group.Name, &group.Description, &group.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning group: %v", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group rows: %v", err)
+	}
+	return groups, nil
+}
+
+// studyActivities returns the study activities matching where with their
+// tallies, in ID order
+func (r *SQLiteRepository) studyActivities(ctx context.Context, where string, args ...interface{}) ([]models.StudyActivity, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			sa.id,
+			sa.group_id,
+			COUNT(DISTINCT ss.id),
+			COUNT(wri.id),
+			COUNT(CASE WHEN wri.is_correct THEN 1 END),
+			sa.created_at
+		FROM study_activities sa
+		LEFT JOIN study_sessions ss ON ss.study_activity_id = sa.id
+		LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
+		WHERE `+where+`
+		GROUP BY sa.id
+		ORDER BY sa.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying study activities: %v", err)
+	}
+	defer rows.Close()
+
+	var activities []models.StudyActivity
+	for rows.Next() {
+		var activity models.StudyActivity
+		err := rows.Scan(
+			&activity.ID,
+			&activity.GroupID,
+			&activity.ActivityCount,
+			&activity.ReviewCount,
+			&activity.CorrectCount,
+			&activity.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning study activity: %v", err)
+		}
+		activities = append(activities, activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating study activities: %v", err)
+	}
+	return activities, nil
+}
+
+// pageIDs builds a subquery selecting the IDs of one keyset page of table:
+// at most page.Limit rows after page.After for each parent ID, or across
+// the whole table when parentColumn is empty. where and whereArgs filter
+// the rows before paging.
+func pageIDs(table, where string, whereArgs []interface{}, parentColumn string, parentIDs []int64, page models.PageRequest, desc bool) (string, []interface{}) {
+	conditions := []string{}
+	args := append([]interface{}{}, whereArgs...)
+	if where != "" {
+		conditions = append(conditions, where)
+	}
+
+	order, partition := "id", ""
+	if desc {
+		order = "id DESC"
+	}
+	if parentColumn != "" {
+		in, parentArgs := inList(parentIDs)
+		conditions = append(conditions, parentColumn+" IN "+in)
+		args = append(args, parentArgs...)
+		partition = "PARTITION BY " + parentColumn + " "
+	}
+	if page.After > 0 {
+		if desc {
+			conditions = append(conditions, "id < ?")
+		} else {
+			conditions = append(conditions, "id > ?")
+		}
+		args = append(args, page.After)
+	}
+	if len(conditions) == 0 {
+		conditions = append(conditions, "1 = 1")
+	}
+	args = append(args, page.Limit)
+
+	return `SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (` + partition + `ORDER BY ` + order + `) AS page_row
+			FROM ` + table + `
+			WHERE ` + strings.Join(conditions, " AND ") + `
+		) WHERE page_row <= ?`, args
+}
+
+// inList returns a parenthesised list of placeholders for ids and the ids
+// as query arguments. An empty list matches nothing.
+func inList(ids []int64) (string, []interface{}) {
+	if len(ids) == 0 {
+		return "(NULL)", nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return "(?" + strings.Repeat(", ?", len(ids)-1) + ")", args
+}
+
+// scanWords scans rows of id, arabic, romaji, english, parts, created_at
+func scanWords(rows *sql.Rows) ([]models.Word, error) {
+	var words []models.Word
+	for rows.Next() {
+		word, err := scanWord(rows)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word rows: %v", err)
+	}
+	return words, nil
+}
+
+// scanWord scans a row of id, arabic, romaji, english, parts, created_at
+// preceded by the columns scanned into lead
+func scanWord(rows *sql.Rows, lead ...interface{}) (models.Word, error) {
+	var word models.Word
+	var parts string
+	dest := append(lead, &word.ID, &word.Arabic, &word.Romaji, &word.English, &parts, &word.CreatedAt)
+	if err := rows.Scan(dest...); err != nil {
+		return word, fmt.Errorf("error scanning word row: %v", err)
+	}
+	if parts != "" {
+		word.Parts = json.RawMessage(parts)
+	}
+	return word, nil
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// sessionIDs returns the IDs of sessions in order
+func sessionIDs(sessions []models.StudySession) []int64 {
+	ids := make([]int64, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	return ids
+}
+
+func TestPageIDs(t *testing.T) {
+	r := newTestRepository(t)
+	alice, bob := asLearner("alice"), asLearner("bob")
+	// a1, a2 and b3 are in group 1, a4 and a5 in group 2; bob owns b3
+	a1 := createSession(t, r, alice, 1)
+	a2 := createSession(t, r, alice, 1)
+	b3 := createSession(t, r, bob, 1)
+	a4 := createSession(t, r, alice, 2)
+	a5 := createSession(t, r, alice, 2)
+
+	tests := []struct {
+		name         string
+		where        string
+		whereArgs    []interface{}
+		parentColumn string
+		parentIDs    []int64
+		page         models.PageRequest
+		desc         bool
+		want         []int64
+	}{
+		{"whole table", "", nil, "", nil, models.PageRequest{Limit: 3}, false, []int64{a1.ID, a2.ID, b3.ID}},
+		{"whole table, newest first", "", nil, "", nil, models.PageRequest{Limit: 3}, true, []int64{a5.ID, a4.ID, b3.ID}},
+		{"after a cursor", "", nil, "", nil, models.PageRequest{Limit: 2, After: a2.ID}, false, []int64{b3.ID, a4.ID}},
+		{"after a cursor, newest first", "", nil, "", nil, models.PageRequest{Limit: 2, After: a4.ID}, true, []int64{b3.ID, a2.ID}},
+		{"filtered", "learner_id = ?", []interface{}{"bob"}, "", nil, models.PageRequest{Limit: 5}, false, []int64{b3.ID}},
+		{"a page per parent", "", nil, "group_id", []int64{1, 2}, models.PageRequest{Limit: 1}, false, []int64{a1.ID, a4.ID}},
+		{"a page per parent after a cursor", "", nil, "group_id", []int64{1, 2}, models.PageRequest{Limit: 1, After: a1.ID}, false, []int64{a2.ID, a4.ID}},
+		{"filtered per parent", "learner_id = ?", []interface{}{"alice"}, "group_id", []int64{1}, models.PageRequest{Limit: 5}, true, []int64{a2.ID, a1.ID}},
+		{"no parents", "", nil, "group_id", nil, models.PageRequest{Limit: 5}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, args := pageIDs("study_sessions", tt.where, tt.whereArgs, tt.parentColumn, tt.parentIDs, tt.page, tt.desc)
+			rows, err := r.db.Query(ids+" ORDER BY page_row, id", args...)
+			if err != nil {
+				t.Fatalf("query: %v", err)
+			}
+			defer rows.Close()
+			var got []int64
+			for rows.Next() {
+				var id int64
+				if err := rows.Scan(&id); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, id)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageStudySessionsScopesTheRootToTheLearner(t *testing.T) {
+	r := newTestRepository(t)
+	alice, bob := asLearner("alice"), asLearner("bob")
+	first := createSession(t, r, alice, 1)
+	createSession(t, r, bob, 1)
+	second := createSession(t, r, alice, 2)
+
+	sessions, err := r.PageStudySessions(alice, "", nil, models.PageRequest{Limit: 10})
+	if err != nil {
+		t.Fatalf("PageStudySessions: %v", err)
+	}
+	if got, want := sessionIDs(sessions), []int64{second.ID, first.ID}; !slices.Equal(got, want) {
+		t.Errorf("sessions = %v, want alice's %v, newest first", got, want)
+	}
+
+	sessions, err = r.PageStudySessions(asLearner("carol"), "", nil, models.PageRequest{Limit: 10})
+	if err != nil {
+		t.Fatalf("PageStudySessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("sessions = %v, want none for a learner without sessions", sessionIDs(sessions))
+	}
+}
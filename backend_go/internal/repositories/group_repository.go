@@ -1,20 +1,25 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"time"
 
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
 )
 
 // GetGroups returns all groups
-func (r *SQLiteRepository) GetGroups() ([]models.Group, error) {
+func (r *SQLiteRepository) GetGroups(ctx context.Context) (_ []models.Group, err error) {
+	ctx, op := instrument(ctx, "GetGroups")
+	defer func() { op.end(err) }()
+
 	query := `
-		SELECT id, name, description, created_at
+		SELECT id, name, COALESCE(description, ''), created_at
 		FROM groups
+		WHERE deleted_at IS NULL
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("error querying groups: %v", err)
 	}
@@ -23,87 +28,124 @@ func (r *SQLiteRepository) GetGroups() ([]models.Group, error) {
 	var groups []models.Group
 	for rows.Next() {
 		var group models.Group
-		var createdAt string
 		err := rows.Scan(
 			&group.ID,
 			&group.Name,
 			&group.Description,
-			&createdAt,
+			&group.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning group: %v", err)
 		}
 
-		group.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing created_at: %v", err)
-		}
-
 		groups = append(groups, group)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group rows: %v", err)
+	}
+
+	op.rows(int64(len(groups)))
 	return groups, nil
 }
 
 // GetGroupByID returns a specific group
-func (r *SQLiteRepository) GetGroupByID(id int64) (*models.Group, error) {
+func (r *SQLiteRepository) GetGroupByID(ctx context.Context, id int64) (_ *models.Group, err error) {
+	ctx, op := instrument(ctx, "GetGroupByID")
+	defer func() { op.end(err) }()
+
 	query := `
-		SELECT id, name, description, created_at
+		SELECT id, name, COALESCE(description, ''), created_at
 		FROM groups
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`
 
 	var group models.Group
-	var createdAt string
-	err := r.db.QueryRow(query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&group.ID,
 		&group.Name,
 		&group.Description,
-		&createdAt,
+		&group.CreatedAt,
 	)
-	if err != nil {
-		return nil, fmt.Errorf("error querying group: %v", err)
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-
-	group.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing created_at: %v", err)
+		return nil, fmt.Errorf("error querying group: %v", err)
 	}
 
 	return &group, nil
 }
 
 // CreateGroup creates a new group
-func (r *SQLiteRepository) CreateGroup(group *models.Group) error {
-	query := `
-		INSERT INTO groups (name, description)
-		VALUES (?, ?)
-		RETURNING id, created_at
-	`
+func (r *SQLiteRepository) CreateGroup(ctx context.Context, group *models.Group) (err error) {
+	ctx, op := instrument(ctx, "CreateGroup")
+	defer func() { op.end(err) }()
 
-	var createdAt string
-	err := r.db.QueryRow(query, group.Name, group.Description).Scan(&group.ID, &createdAt)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("error creating group: %v", err)
+		return fmt.Errorf("error starting transaction: %v", err)
 	}
+	defer tx.Rollback()
 
-	group.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
+	if err := createGroupTx(ctx, tx, group); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateGroupWithWords creates a group and adds the given words to it in
+// one transaction
+func (r *SQLiteRepository) CreateGroupWithWords(ctx context.Context, group *models.Group, wordIDs []int64) (err error) {
+	ctx, op := instrument(ctx, "CreateGroupWithWords")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("error parsing created_at: %v", err)
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := createGroupTx(ctx, tx, group); err != nil {
+		return err
+	}
+	for _, wordID := range wordIDs {
+		if err := addWordToGroupTx(ctx, tx, group.ID, wordID); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	op.rows(int64(len(wordIDs)))
+	return tx.Commit()
 }
 
 // UpdateGroup updates an existing group
-func (r *SQLiteRepository) UpdateGroup(group *models.Group) error {
+func (r *SQLiteRepository) UpdateGroup(ctx context.Context, group *models.Group) (err error) {
+	ctx, op := instrument(ctx, "UpdateGroup")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getGroupTx(ctx, tx, group.ID)
+	if err != nil {
+		return err
+	}
+	if before == nil || before.DeletedAt != nil {
+		return fmt.Errorf("%w: group %d", ErrNotFound, group.ID)
+	}
+
 	query := `
 		UPDATE groups
 		SET name = ?, description = ?
 		WHERE id = ?
 	`
 
-	result, err := r.db.Exec(query, group.Name, group.Description, group.ID)
+	result, err := tx.ExecContext(ctx, query, group.Name, group.Description, group.ID)
 	if err != nil {
 		return fmt.Errorf("error updating group: %v", err)
 	}
@@ -112,22 +154,47 @@ func (r *SQLiteRepository) UpdateGroup(group *models.Group) error {
 	if err != nil {
 		return fmt.Errorf("error getting rows affected: %v", err)
 	}
+	op.rows(rowsAffected)
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("group not found")
+	after, err := getGroupTx(ctx, tx, group.ID)
+	if err != nil {
+		return err
+	}
+	group.CreatedAt = after.CreatedAt
+
+	if err := recordAudit(ctx, tx, models.EntityGroup, group.ID, models.AuditUpdate, before, after); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-// DeleteGroup deletes a group
-func (r *SQLiteRepository) DeleteGroup(id int64) error {
+// DeleteGroup moves a group to the trash
+func (r *SQLiteRepository) DeleteGroup(ctx context.Context, id int64) (err error) {
+	ctx, op := instrument(ctx, "DeleteGroup")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getGroupTx(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if before == nil || before.DeletedAt != nil {
+		return fmt.Errorf("%w: group %d", ErrNotFound, id)
+	}
+
 	query := `
-		DELETE FROM groups
-		WHERE id = ?
+		UPDATE groups
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("error deleting group: %v", err)
 	}
@@ -136,10 +203,204 @@ func (r *SQLiteRepository) DeleteGroup(id int64) error {
 	if err != nil {
 		return fmt.Errorf("error getting rows affected: %v", err)
 	}
+	op.rows(rowsAffected)
+
+	if err := recordAudit(ctx, tx, models.EntityGroup, id, models.AuditDelete, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddWordToGroup adds a word to a group
+func (r *SQLiteRepository) AddWordToGroup(ctx context.Context, groupID, wordID int64) (err error) {
+	ctx, op := instrument(ctx, "AddWordToGroup")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := addWordToGroupTx(ctx, tx, groupID, wordID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveWordFromGroup removes a word from a group
+func (r *SQLiteRepository) RemoveWordFromGroup(ctx context.Context, groupID, wordID int64) (err error) {
+	ctx, op := instrument(ctx, "RemoveWordFromGroup")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getWordGroupTx(ctx, tx, groupID, wordID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return fmt.Errorf("%w: word %d in group %d", ErrNotFound, wordID, groupID)
+	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("group not found")
+	if _, err := tx.ExecContext(ctx, "DELETE FROM words_groups WHERE id = ?", before.ID); err != nil {
+		return fmt.Errorf("error removing word from group: %v", err)
+	}
+
+	if err := recordAudit(ctx, tx, models.EntityWordGroup, before.ID, models.AuditDelete, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RestoreGroup takes a group back out of the trash
+func (r *SQLiteRepository) RestoreGroup(ctx context.Context, id int64) (_ *models.Group, err error) {
+	ctx, op := instrument(ctx, "RestoreGroup")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getGroupTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil || before.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: group %d in trash", ErrNotFound, id)
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE groups SET deleted_at = NULL WHERE id = ?", id); err != nil {
+		return nil, fmt.Errorf("error restoring group: %v", err)
+	}
+
+	after, err := getGroupTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, tx, models.EntityGroup, id, models.AuditRestore, nil, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing restore: %v", err)
+	}
+
+	return after, nil
+}
+
+// createGroupTx inserts a group inside a transaction and records its creation
+func createGroupTx(ctx context.Context, tx *sql.Tx, group *models.Group) error {
+	query := `
+		INSERT INTO groups (name, description)
+		VALUES (?, ?)
+		RETURNING id
+	`
+
+	err := tx.QueryRowContext(ctx, query, group.Name, group.Description).Scan(&group.ID)
+	if err != nil {
+		return fmt.Errorf("error creating group: %v", err)
+	}
+
+	created, err := getGroupTx(ctx, tx, group.ID)
+	if err != nil {
+		return err
+	}
+	group.CreatedAt = created.CreatedAt
+
+	return recordAudit(ctx, tx, models.EntityGroup, group.ID, models.AuditCreate, nil, created)
+}
+
+// addWordToGroupTx adds a word to a group inside a transaction and records
+// the new membership; adding a word that is already a member does nothing
+func addWordToGroupTx(ctx context.Context, tx *sql.Tx, groupID, wordID int64) error {
+	existing, err := getWordGroupTx(ctx, tx, groupID, wordID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	var membership models.WordGroup
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO words_groups (word_id, group_id)
+		SELECT w.id, g.id
+		FROM words w, groups g
+		WHERE w.id = ? AND g.id = ?
+			AND w.deleted_at IS NULL AND g.deleted_at IS NULL
+		RETURNING id, word_id, group_id, created_at
+	`, wordID, groupID).Scan(
+		&membership.ID,
+		&membership.WordID,
+		&membership.GroupID,
+		&membership.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: word %d or group %d", ErrNotFound, wordID, groupID)
+	}
+	if err != nil {
+		return fmt.Errorf("error adding word to group: %v", err)
+	}
+
+	return recordAudit(ctx, tx, models.EntityWordGroup, membership.ID, models.AuditCreate, nil, membership)
+}
+
+// getGroupTx loads a group inside a transaction, including trashed groups,
+// returning nil if it is missing
+func getGroupTx(ctx context.Context, tx *sql.Tx, id int64) (*models.Group, error) {
+	var group models.Group
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, name, COALESCE(description, ''), created_at, deleted_at
+		FROM groups
+		WHERE id = ?
+	`, id).Scan(
+		&group.ID,
+		&group.Name,
+		&group.Description,
+		&group.CreatedAt,
+		&group.DeletedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning group: %v", err)
+	}
+
+	return &group, nil
+}
+
+// getWordGroupTx loads a membership inside a transaction, returning nil if it is missing
+func getWordGroupTx(ctx context.Context, tx *sql.Tx, groupID, wordID int64) (*models.WordGroup, error) {
+	var membership models.WordGroup
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, word_id, group_id, created_at
+		FROM words_groups
+		WHERE group_id = ? AND word_id = ?
+		LIMIT 1
+	`, groupID, wordID).Scan(
+		&membership.ID,
+		&membership.WordID,
+		&membership.GroupID,
+		&membership.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning word group: %v", err)
 	}
 
-	return nil
+	return &membership, nil
 }
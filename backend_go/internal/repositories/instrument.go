@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories")
+
+// operation tracks a single repository method call in traces and metrics
+type operation struct {
+	span    trace.Span
+	observe func()
+}
+
+// instrument starts a span and latency timer for a repository method.
+// Callers defer end with the method's returned error.
+func instrument(ctx context.Context, method string) (context.Context, *operation) {
+	ctx, span := tracer.Start(ctx, "SQLiteRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "sqlite"),
+			attribute.String("db.statement.name", method),
+		),
+	)
+
+	return ctx, &operation{span: span, observe: metrics.ObserveQuery(method)}
+}
+
+// rows records how many rows the method returned or affected
+func (o *operation) rows(n int64) {
+	o.span.SetAttributes(attribute.Int64("db.rows", n))
+}
+
+// end finishes the span, recording err if the method failed
+func (o *operation) end(err error) {
+	if err != nil {
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	}
+	o.span.End()
+	o.observe()
+}
@@ -0,0 +1,308 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/gamification"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/stats"
+)
+
+// leaderboardDelta is a change to a learner's leaderboard totals
+type leaderboardDelta struct {
+	xp            int
+	reviews       int
+	correct       int
+	wordsMastered int
+}
+
+// bumpLeaderboards adds delta to the learner's all-time and weekly totals,
+// overall and for the event's group. Anonymous learners are not ranked.
+// A word can stop being mastered on a board that never counted it, such
+// as a week after the one it was mastered in, so words_mastered stops at 0.
+func bumpLeaderboards(ctx context.Context, tx *sql.Tx, event learnerEvent, delta leaderboardDelta) error {
+	if event.learner == "" || delta == (leaderboardDelta{}) {
+		return nil
+	}
+
+	// A new learner's profile gets the generated pseudonym shown until they
+	// choose one
+	_, err := tx.ExecContext(ctx, "INSERT INTO learner_profiles (learner_id) VALUES (?) ON CONFLICT(learner_id) DO NOTHING", event.learner)
+	if err != nil {
+		return fmt.Errorf("error creating learner profile: %v", err)
+	}
+
+	groups := []int64{0}
+	if event.groupID > 0 {
+		groups = append(groups, event.groupID)
+	}
+	for _, groupID := range groups {
+		for _, period := range []string{models.PeriodAll, gamification.WeekStart(event.at)} {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO leaderboard_stats (learner_id, group_id, period, xp, reviews, correct_count, words_mastered)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(learner_id, group_id, period) DO UPDATE SET
+					xp = xp + excluded.xp,
+					reviews = reviews + excluded.reviews,
+					correct_count = correct_count + excluded.correct_count,
+					words_mastered = MAX(0, words_mastered + ?)
+			`, event.learner, groupID, period, delta.xp, delta.reviews, delta.correct, max(0, delta.wordsMastered), delta.wordsMastered)
+			if err != nil {
+				return fmt.Errorf("error updating leaderboard: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// updateWordProgress adds a review to the learner's running tallies for
+// the word and returns 1 if the word became mastered, -1 if it stopped
+// being mastered and 0 otherwise
+func updateWordProgress(ctx context.Context, tx *sql.Tx, learner string, review *models.WordReviewItem) (int, error) {
+	var progress models.WordProgress
+	var wasMastered bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT attempts, correct_count, current_streak, mastered
+		FROM learner_word_progress
+		WHERE learner_id = ? AND word_id = ?
+	`, learner, review.WordID).Scan(&progress.Attempts, &progress.CorrectCount, &progress.CurrentStreak, &wasMastered)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("error querying word progress: %v", err)
+	}
+
+	progress.Attempts++
+	if review.IsCorrect {
+		progress.CorrectCount++
+		progress.CurrentStreak++
+	} else {
+		progress.CurrentStreak = 0
+	}
+	mastered := stats.Mastered(progress)
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO learner_word_progress (learner_id, word_id, attempts, correct_count, current_streak, mastered)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(learner_id, word_id) DO UPDATE SET
+			attempts = excluded.attempts,
+			correct_count = excluded.correct_count,
+			current_streak = excluded.current_streak,
+			mastered = excluded.mastered
+	`, learner, review.WordID, progress.Attempts, progress.CorrectCount, progress.CurrentStreak, mastered)
+	if err != nil {
+		return 0, fmt.Errorf("error updating word progress: %v", err)
+	}
+
+	switch {
+	case mastered && !wasMastered:
+		return 1, nil
+	case !mastered && wasMastered:
+		return -1, nil
+	}
+	return 0, nil
+}
+
+// BackfillLeaderboards replays the review and XP history into the running
+// word tallies and leaderboard totals, through the same updates new reviews
+// make. It does nothing once the tallies hold any rows.
+func (r *SQLiteRepository) BackfillLeaderboards(ctx context.Context) (err error) {
+	ctx, op := instrument(ctx, "BackfillLeaderboards")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var filled bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM learner_word_progress)").Scan(&filled); err != nil {
+		return fmt.Errorf("error querying word progress: %v", err)
+	}
+	if filled {
+		return nil
+	}
+
+	type replay struct {
+		event  learnerEvent
+		review models.WordReviewItem
+		xp     int
+	}
+	var history []replay
+	rows, err := tx.QueryContext(ctx, `
+		SELECT wri.learner_id, COALESCE(ss.group_id, 0), wri.created_at, wri.id, wri.word_id, wri.is_correct, 0
+		FROM word_review_items wri
+		LEFT JOIN study_sessions ss ON ss.id = wri.study_session_id
+		UNION ALL
+		SELECT xp.learner_id, COALESCE(ss.group_id, 0), xp.created_at, 0, 0, 0, xp.xp
+		FROM xp_events xp
+		LEFT JOIN word_review_items wri ON xp.source = 'review' AND wri.id = CAST(xp.source_id AS INTEGER)
+		LEFT JOIN study_sessions ss ON ss.id = CASE xp.source
+			WHEN 'review' THEN wri.study_session_id
+			WHEN 'session' THEN CAST(xp.source_id AS INTEGER)
+		END
+		ORDER BY 3, 4
+	`)
+	if err != nil {
+		return fmt.Errorf("error querying history: %v", err)
+	}
+	for rows.Next() {
+		var h replay
+		err := rows.Scan(&h.event.learner, &h.event.groupID, &h.event.at, &h.review.ID, &h.review.WordID, &h.review.IsCorrect, &h.xp)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning history: %v", err)
+		}
+		history = append(history, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating history: %v", err)
+	}
+
+	for _, h := range history {
+		delta := leaderboardDelta{xp: h.xp}
+		if h.review.ID > 0 {
+			mastered, err := updateWordProgress(ctx, tx, h.event.learner, &h.review)
+			if err != nil {
+				return err
+			}
+			delta = leaderboardDelta{reviews: 1, wordsMastered: mastered}
+			if h.review.IsCorrect {
+				delta.correct = 1
+			}
+		}
+		if err := bumpLeaderboards(ctx, tx, h.event, delta); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing leaderboard backfill: %v", err)
+	}
+
+	op.rows(int64(len(history)))
+	return nil
+}
+
+// leaderboardOrder maps a leaderboard metric to its ranking expression
+var leaderboardOrder = map[string]string{
+	models.MetricXP:            "ls.xp",
+	models.MetricWordsMastered: "ls.words_mastered",
+	models.MetricAccuracy:      "CAST(ls.correct_count AS REAL) / ls.reviews",
+}
+
+// GetLeaderboard ranks learners on a group's board (0 for everyone) for a
+// period by metric, from the incrementally maintained totals. Learners who
+// opted out are left out and learners are named by their pseudonym or, if
+// they have not chosen one, a generated one. The caller's own entry is
+// returned too, even outside the top limit.
+func (r *SQLiteRepository) GetLeaderboard(ctx context.Context, groupID int64, period, metric string, limit, minReviews int) (_ []models.LeaderboardEntry, _ *models.LeaderboardEntry, err error) {
+	ctx, op := instrument(ctx, "GetLeaderboard")
+	defer func() { op.end(err) }()
+
+	order, ok := leaderboardOrder[metric]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unknown leaderboard metric %q", ErrInvalidInput, metric)
+	}
+
+	learner := learnerFromContext(ctx)
+	rows, err := r.db.QueryContext(ctx, `
+		WITH ranked AS (
+			SELECT
+				ls.learner_id,
+				COALESCE(NULLIF(lp.pseudonym, ''), lp.generated_pseudonym, 'Anonymous learner') AS name,
+				ls.xp,
+				ls.words_mastered,
+				ls.reviews,
+				ls.correct_count,
+				RANK() OVER (ORDER BY `+order+` DESC) AS rank,
+				ROW_NUMBER() OVER (ORDER BY `+order+` DESC, ls.learner_id) AS position
+			FROM leaderboard_stats ls
+			LEFT JOIN learner_profiles lp ON lp.learner_id = ls.learner_id
+			WHERE ls.group_id = ? AND ls.period = ?
+				AND NOT COALESCE(lp.leaderboard_opt_out, 0)
+				AND ls.reviews >= ?
+		)
+		SELECT learner_id, name, xp, words_mastered, reviews, correct_count, rank, position
+		FROM ranked
+		WHERE position <= ? OR learner_id = ?
+		ORDER BY position
+	`, groupID, period, minReviews, limit, learner)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying leaderboard: %v", err)
+	}
+	defer rows.Close()
+
+	entries := []models.LeaderboardEntry{}
+	var me *models.LeaderboardEntry
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+		var entryLearner string
+		var position int
+		err := rows.Scan(
+			&entryLearner,
+			&entry.Name,
+			&entry.XP,
+			&entry.WordsMastered,
+			&entry.Reviews,
+			&entry.CorrectCount,
+			&entry.Rank,
+			&position,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error scanning leaderboard entry: %v", err)
+		}
+		if entry.Reviews > 0 {
+			entry.Accuracy = float64(entry.CorrectCount) / float64(entry.Reviews)
+		}
+		if entryLearner == learner {
+			entry.Me = true
+			mine := entry
+			me = &mine
+		}
+		if position <= limit {
+			entries = append(entries, entry)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating leaderboard: %v", err)
+	}
+
+	op.rows(int64(len(entries)))
+	return entries, me, nil
+}
+
+// GetProfile returns the learner's leaderboard preferences
+func (r *SQLiteRepository) GetProfile(ctx context.Context) (_ *models.LearnerProfile, err error) {
+	ctx, op := instrument(ctx, "GetProfile")
+	defer func() { op.end(err) }()
+
+	profile := &models.LearnerProfile{}
+	err = r.db.QueryRowContext(ctx, `
+		SELECT pseudonym, leaderboard_opt_out FROM learner_profiles WHERE learner_id = ?
+	`, learnerFromContext(ctx)).Scan(&profile.Pseudonym, &profile.LeaderboardOptOut)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error querying profile: %v", err)
+	}
+	return profile, nil
+}
+
+// UpdateProfile stores the learner's leaderboard preferences
+func (r *SQLiteRepository) UpdateProfile(ctx context.Context, profile *models.LearnerProfile) (err error) {
+	ctx, op := instrument(ctx, "UpdateProfile")
+	defer func() { op.end(err) }()
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO learner_profiles (learner_id, pseudonym, leaderboard_opt_out) VALUES (?, ?, ?)
+		ON CONFLICT(learner_id) DO UPDATE SET
+			pseudonym = excluded.pseudonym,
+			leaderboard_opt_out = excluded.leaderboard_opt_out
+	`, learnerFromContext(ctx), profile.Pseudonym, profile.LeaderboardOptOut)
+	if err != nil {
+		return fmt.Errorf("error storing profile: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// leaderboardRows returns every leaderboard and word tally row as strings,
+// in a stable order
+func leaderboardRows(t *testing.T, r *SQLiteRepository) []string {
+	t.Helper()
+	rows, err := r.db.Query(`
+		SELECT 'board', learner_id, group_id, period, xp, reviews, correct_count, words_mastered
+		FROM leaderboard_stats
+		UNION ALL
+		SELECT 'word', learner_id, word_id, '', attempts, correct_count, current_streak, mastered
+		FROM learner_word_progress
+		ORDER BY 1, 2, 3, 4
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var kind, learner, period string
+		var id int64
+		var a, b, c, d int
+		if err := rows.Scan(&kind, &learner, &id, &period, &a, &b, &c, &d); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, fmt.Sprint(kind, learner, id, period, a, b, c, d))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// wordsMastered returns the learner's words_mastered on every board
+func wordsMastered(t *testing.T, r *SQLiteRepository, learner string) map[string]int {
+	t.Helper()
+	rows, err := r.db.Query(`
+		SELECT group_id, period, words_mastered FROM leaderboard_stats WHERE learner_id = ?
+	`, learner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	boards := map[string]int{}
+	for rows.Next() {
+		var groupID int64
+		var period string
+		var mastered int
+		if err := rows.Scan(&groupID, &period, &mastered); err != nil {
+			t.Fatal(err)
+		}
+		boards[fmt.Sprintf("%d/%s", groupID, period)] = mastered
+	}
+	return boards
+}
+
+// masterThenForget masters word 1 in a group 1 session and then misses it
+// in a group 2 session
+func masterThenForget(t *testing.T, r *SQLiteRepository, ctx context.Context) {
+	t.Helper()
+	first := createSession(t, r, ctx, 1)
+	for i := 0; i < 5; i++ {
+		review(t, r, ctx, first.ID, 1, true)
+	}
+	review(t, r, ctx, first.ID, 2, true)
+	second := createSession(t, r, ctx, 2)
+	review(t, r, ctx, second.ID, 1, false)
+	review(t, r, ctx, second.ID, 2, true)
+}
+
+func TestWordsMasteredStopsAtZero(t *testing.T) {
+	r := newTestRepository(t)
+	masterThenForget(t, r, asLearner("alice"))
+
+	for board, mastered := range wordsMastered(t, r, "alice") {
+		want := 0
+		if strings.HasPrefix(board, "1/") {
+			want = 1
+		}
+		if mastered != want {
+			t.Errorf("words_mastered on board %s = %d, want %d", board, mastered, want)
+		}
+	}
+}
+
+func TestBackfillLeaderboardsMatchesIncrementalUpdates(t *testing.T) {
+	r := newTestRepository(t)
+	masterThenForget(t, r, asLearner("alice"))
+	bob := asLearner("bob")
+	session := createSession(t, r, bob, 3)
+	for i := 0; i < 6; i++ {
+		review(t, r, bob, session.ID, 3, i > 0)
+	}
+	anonymous := createSession(t, r, context.Background(), 1)
+	review(t, r, context.Background(), anonymous.ID, 4, true)
+
+	want := leaderboardRows(t, r)
+
+	// Backfilling leaves maintained tallies alone
+	if err := r.BackfillLeaderboards(context.Background()); err != nil {
+		t.Fatalf("BackfillLeaderboards: %v", err)
+	}
+	if got := leaderboardRows(t, r); !reflect.DeepEqual(got, want) {
+		t.Fatalf("backfill changed maintained tallies:\n got %v\nwant %v", got, want)
+	}
+
+	mustExec(t, r, "DELETE FROM leaderboard_stats")
+	mustExec(t, r, "DELETE FROM learner_word_progress")
+	if err := r.BackfillLeaderboards(context.Background()); err != nil {
+		t.Fatalf("BackfillLeaderboards: %v", err)
+	}
+	if got := leaderboardRows(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("backfilled tallies differ from incremental ones:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestLeaderboardNamesWithoutPseudonym(t *testing.T) {
+	r := newTestRepository(t)
+	alice, bob := asLearner("alice"), asLearner("bob")
+	review(t, r, alice, createSession(t, r, alice, 1).ID, 1, true)
+	review(t, r, bob, createSession(t, r, bob, 1).ID, 1, true)
+	if err := r.UpdateProfile(bob, &models.LearnerProfile{Pseudonym: "Bobby"}); err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+
+	names := func() map[string]string {
+		t.Helper()
+		_, me, err := r.GetLeaderboard(alice, 0, models.PeriodAll, models.MetricXP, 10, 0)
+		if err != nil || me == nil {
+			t.Fatalf("GetLeaderboard = %+v, %v", me, err)
+		}
+		entries, him, err := r.GetLeaderboard(bob, 0, models.PeriodAll, models.MetricXP, 10, 0)
+		if err != nil || him == nil || len(entries) != 2 {
+			t.Fatalf("GetLeaderboard = %+v, %+v, %v", entries, him, err)
+		}
+		return map[string]string{"alice": me.Name, "bob": him.Name}
+	}
+
+	got := names()
+	if !regexp.MustCompile(`^Learner [0-9A-F]{6}$`).MatchString(got["alice"]) {
+		t.Errorf("alice is shown as %q, want a generated pseudonym", got["alice"])
+	}
+	if got["bob"] != "Bobby" {
+		t.Errorf("bob is shown as %q, want his pseudonym", got["bob"])
+	}
+
+	// Clearing a pseudonym goes back to the same generated one
+	if err := r.UpdateProfile(bob, &models.LearnerProfile{}); err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	again := names()
+	if again["alice"] != got["alice"] || !strings.HasPrefix(again["bob"], "Learner ") || again["bob"] == again["alice"] {
+		t.Errorf("names after clearing bob's pseudonym = %v", again)
+	}
+}
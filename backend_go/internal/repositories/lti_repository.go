@@ -0,0 +1,392 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+const ltiPlatformColumns = `id, name, issuer, client_id, deployment_id, auth_login_url, auth_token_url, jwks_url, created_at`
+
+// scanLTIPlatform scans a row selected with ltiPlatformColumns
+func scanLTIPlatform(row interface{ Scan(...interface{}) error }) (models.LTIPlatform, error) {
+	var platform models.LTIPlatform
+	err := row.Scan(
+		&platform.ID,
+		&platform.Name,
+		&platform.Issuer,
+		&platform.ClientID,
+		&platform.DeploymentID,
+		&platform.AuthLoginURL,
+		&platform.AuthTokenURL,
+		&platform.JWKSURL,
+		&platform.CreatedAt,
+	)
+	return platform, err
+}
+
+// GetLTIPlatforms returns every registered platform
+func (r *SQLiteRepository) GetLTIPlatforms(ctx context.Context) (_ []models.LTIPlatform, err error) {
+	ctx, op := instrument(ctx, "GetLTIPlatforms")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+ltiPlatformColumns+` FROM lti_platforms ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying LTI platforms: %v", err)
+	}
+	defer rows.Close()
+
+	platforms := []models.LTIPlatform{}
+	for rows.Next() {
+		platform, err := scanLTIPlatform(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning LTI platform: %v", err)
+		}
+		platforms = append(platforms, platform)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating LTI platforms: %v", err)
+	}
+
+	op.rows(int64(len(platforms)))
+	return platforms, nil
+}
+
+// GetLTIPlatform returns a platform, or nil if it does not exist
+func (r *SQLiteRepository) GetLTIPlatform(ctx context.Context, id int64) (_ *models.LTIPlatform, err error) {
+	ctx, op := instrument(ctx, "GetLTIPlatform")
+	defer func() { op.end(err) }()
+
+	platform, err := scanLTIPlatform(r.db.QueryRowContext(ctx, `SELECT `+ltiPlatformColumns+` FROM lti_platforms WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying LTI platform: %v", err)
+	}
+	return &platform, nil
+}
+
+// FindLTIPlatform returns the platform registered for an issuer and
+// client ID, or nil. Without a client ID the issuer must be registered
+// only once.
+func (r *SQLiteRepository) FindLTIPlatform(ctx context.Context, issuer, clientID string) (_ *models.LTIPlatform, err error) {
+	ctx, op := instrument(ctx, "FindLTIPlatform")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+ltiPlatformColumns+` FROM lti_platforms
+		WHERE issuer = ? AND (? = '' OR client_id = ?)
+		LIMIT 2
+	`, issuer, clientID, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying LTI platform: %v", err)
+	}
+	defer rows.Close()
+
+	var platforms []models.LTIPlatform
+	for rows.Next() {
+		platform, err := scanLTIPlatform(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning LTI platform: %v", err)
+		}
+		platforms = append(platforms, platform)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating LTI platforms: %v", err)
+	}
+
+	if len(platforms) != 1 {
+		return nil, nil
+	}
+	return &platforms[0], nil
+}
+
+// CreateLTIPlatform registers a platform; an issuer and client ID may
+// only be registered once
+func (r *SQLiteRepository) CreateLTIPlatform(ctx context.Context, platform *models.LTIPlatform) (err error) {
+	ctx, op := instrument(ctx, "CreateLTIPlatform")
+	defer func() { op.end(err) }()
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO lti_platforms (name, issuer, client_id, deployment_id, auth_login_url, auth_token_url, jwks_url)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(issuer, client_id) DO NOTHING
+		RETURNING id, created_at
+	`, platform.Name, platform.Issuer, platform.ClientID, platform.DeploymentID,
+		platform.AuthLoginURL, platform.AuthTokenURL, platform.JWKSURL).Scan(&platform.ID, &platform.CreatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: platform %s is already registered for client %s", ErrConflict, platform.Issuer, platform.ClientID)
+	}
+	if err != nil {
+		return fmt.Errorf("error creating LTI platform: %v", err)
+	}
+	return nil
+}
+
+// DeleteLTIPlatform removes a platform and its pending logins. Launches
+// and users are kept so past sessions stay attributed.
+func (r *SQLiteRepository) DeleteLTIPlatform(ctx context.Context, id int64) (err error) {
+	ctx, op := instrument(ctx, "DeleteLTIPlatform")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM lti_login_states WHERE platform_id = ?`, id); err != nil {
+		return fmt.Errorf("error deleting LTI login states: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE lti_launches SET score_status = ? WHERE platform_id = ? AND score_status = ?
+	`, models.LTIScoreFailed, id, models.LTIScorePending); err != nil {
+		return fmt.Errorf("error cancelling LTI scores: %v", err)
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM lti_platforms WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting LTI platform: %v", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting LTI platform: %v", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: LTI platform %d", ErrNotFound, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+	op.rows(n)
+	return nil
+}
+
+// GetLTIKey returns the tool's signing key as PEM, or empty strings if
+// none has been generated yet
+func (r *SQLiteRepository) GetLTIKey(ctx context.Context) (_, _ string, err error) {
+	ctx, op := instrument(ctx, "GetLTIKey")
+	defer func() { op.end(err) }()
+
+	var kid, key string
+	err = r.db.QueryRowContext(ctx, `SELECT kid, private_key FROM lti_keys ORDER BY created_at DESC LIMIT 1`).Scan(&kid, &key)
+	if err == sql.ErrNoRows {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("error querying LTI key: %v", err)
+	}
+	return kid, key, nil
+}
+
+// CreateLTIKey stores the tool's signing key
+func (r *SQLiteRepository) CreateLTIKey(ctx context.Context, kid, privateKey string) (err error) {
+	ctx, op := instrument(ctx, "CreateLTIKey")
+	defer func() { op.end(err) }()
+
+	if _, err := r.db.ExecContext(ctx, `INSERT INTO lti_keys (kid, private_key) VALUES (?, ?)`, kid, privateKey); err != nil {
+		return fmt.Errorf("error storing LTI key: %v", err)
+	}
+	return nil
+}
+
+// CreateLTILoginState remembers a login until expires and drops expired ones
+func (r *SQLiteRepository) CreateLTILoginState(ctx context.Context, state, nonce string, platformID int64, expires time.Time) (err error) {
+	ctx, op := instrument(ctx, "CreateLTILoginState")
+	defer func() { op.end(err) }()
+
+	now := time.Now().UTC().Format(scheduleTimeLayout)
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM lti_login_states WHERE expires_at <= ?`, now); err != nil {
+		return fmt.Errorf("error purging LTI login states: %v", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO lti_login_states (state, nonce, platform_id, expires_at) VALUES (?, ?, ?, ?)
+	`, state, nonce, platformID, expires.UTC().Format(scheduleTimeLayout))
+	if err != nil {
+		return fmt.Errorf("error storing LTI login state: %v", err)
+	}
+	return nil
+}
+
+// ConsumeLTILoginState removes a pending login and returns its nonce and
+// platform. Unknown, used and expired states are not found.
+func (r *SQLiteRepository) ConsumeLTILoginState(ctx context.Context, state string) (_ string, _ int64, err error) {
+	ctx, op := instrument(ctx, "ConsumeLTILoginState")
+	defer func() { op.end(err) }()
+
+	var nonce string
+	var platformID int64
+	err = r.db.QueryRowContext(ctx, `
+		DELETE FROM lti_login_states WHERE state = ? AND expires_at > ?
+		RETURNING nonce, platform_id
+	`, state, time.Now().UTC().Format(scheduleTimeLayout)).Scan(&nonce, &platformID)
+	if err == sql.ErrNoRows {
+		return "", 0, fmt.Errorf("%w: login state", ErrNotFound)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("error consuming LTI login state: %v", err)
+	}
+	return nonce, platformID, nil
+}
+
+// UpsertLTIUser maps a platform user to a learner, keeping the learner ID
+// of earlier launches and refreshing the name and email
+func (r *SQLiteRepository) UpsertLTIUser(ctx context.Context, user *models.LTIUser) (err error) {
+	ctx, op := instrument(ctx, "UpsertLTIUser")
+	defer func() { op.end(err) }()
+
+	learner := fmt.Sprintf("lti-%d-%s", user.PlatformID, user.Subject)
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO lti_users (platform_id, subject, learner_id, name, email) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(platform_id, subject) DO UPDATE SET
+			name = excluded.name,
+			email = excluded.email,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING learner_id
+	`, user.PlatformID, user.Subject, learner, user.Name, user.Email).Scan(&user.LearnerID)
+	if err != nil {
+		return fmt.Errorf("error storing LTI user: %v", err)
+	}
+	return nil
+}
+
+// CreateLTILaunch records a validated launch
+func (r *SQLiteRepository) CreateLTILaunch(ctx context.Context, launch *models.LTILaunch) (err error) {
+	ctx, op := instrument(ctx, "CreateLTILaunch")
+	defer func() { op.end(err) }()
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO lti_launches (
+			launch_key, platform_id, subject, learner_id, message_type, deployment_id, group_id,
+			study_activity_id, study_session_id, lineitem_url, deep_link_return_url, deep_link_data, score_status
+		) VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, 0), NULLIF(?, 0), NULLIF(?, 0), ?, ?, ?, ?)
+		RETURNING id, created_at
+	`, launch.LaunchKey, launch.PlatformID, launch.Subject, launch.LearnerID, launch.MessageType, launch.DeploymentID,
+		launch.GroupID, launch.StudyActivityID, launch.StudySessionID, launch.LineItemURL,
+		launch.DeepLinkReturnURL, launch.DeepLinkData, launch.ScoreStatus).Scan(&launch.ID, &launch.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating LTI launch: %v", err)
+	}
+	return nil
+}
+
+// ConsumeLTIDeepLink marks a deep linking launch as answered and returns
+// it. Unknown launches, launches older than ttl and ones already answered
+// are not found, so each request signs at most one response.
+func (r *SQLiteRepository) ConsumeLTIDeepLink(ctx context.Context, key string, ttl time.Duration) (_ *models.LTILaunch, err error) {
+	ctx, op := instrument(ctx, "ConsumeLTIDeepLink")
+	defer func() { op.end(err) }()
+
+	var launch models.LTILaunch
+	err = r.db.QueryRowContext(ctx, `
+		UPDATE lti_launches SET deep_link_used_at = CURRENT_TIMESTAMP
+		WHERE launch_key = ? AND deep_link_return_url != '' AND deep_link_used_at IS NULL
+			AND created_at > datetime('now', ?)
+		RETURNING id, launch_key, platform_id, subject, learner_id, message_type, deployment_id,
+			COALESCE(group_id, 0), COALESCE(study_activity_id, 0), COALESCE(study_session_id, 0),
+			lineitem_url, deep_link_return_url, deep_link_data, score_status, created_at
+	`, key, fmt.Sprintf("-%d seconds", int(ttl.Seconds()))).Scan(
+		&launch.ID,
+		&launch.LaunchKey,
+		&launch.PlatformID,
+		&launch.Subject,
+		&launch.LearnerID,
+		&launch.MessageType,
+		&launch.DeploymentID,
+		&launch.GroupID,
+		&launch.StudyActivityID,
+		&launch.StudySessionID,
+		&launch.LineItemURL,
+		&launch.DeepLinkReturnURL,
+		&launch.DeepLinkData,
+		&launch.ScoreStatus,
+		&launch.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: deep linking request", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error consuming LTI deep linking request: %v", err)
+	}
+	op.rows(1)
+	return &launch, nil
+}
+
+// GetPendingLTIScores returns the scores of finished sessions that still
+// have to be posted to their platform
+func (r *SQLiteRepository) GetPendingLTIScores(ctx context.Context, limit int) (_ []models.LTIScore, err error) {
+	ctx, op := instrument(ctx, "GetPendingLTIScores")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			l.id, l.score_attempts, l.subject, l.lineitem_url, ss.finished_at,
+			(SELECT COUNT(*) FROM word_review_items WHERE study_session_id = ss.id),
+			(SELECT COUNT(*) FROM word_review_items WHERE study_session_id = ss.id AND is_correct),
+			p.id, p.name, p.issuer, p.client_id, p.deployment_id, p.auth_login_url, p.auth_token_url, p.jwks_url, p.created_at
+		FROM lti_launches l
+		JOIN study_sessions ss ON ss.id = l.study_session_id
+		JOIN lti_platforms p ON p.id = l.platform_id
+		WHERE l.score_status = ? AND ss.finished_at IS NOT NULL
+		ORDER BY ss.finished_at, l.id
+		LIMIT ?
+	`, models.LTIScorePending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying pending LTI scores: %v", err)
+	}
+	defer rows.Close()
+
+	var scores []models.LTIScore
+	for rows.Next() {
+		var score models.LTIScore
+		err := rows.Scan(
+			&score.LaunchID,
+			&score.Attempts,
+			&score.Subject,
+			&score.LineItemURL,
+			&score.FinishedAt,
+			&score.WordsReviewed,
+			&score.CorrectCount,
+			&score.Platform.ID,
+			&score.Platform.Name,
+			&score.Platform.Issuer,
+			&score.Platform.ClientID,
+			&score.Platform.DeploymentID,
+			&score.Platform.AuthLoginURL,
+			&score.Platform.AuthTokenURL,
+			&score.Platform.JWKSURL,
+			&score.Platform.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning LTI score: %v", err)
+		}
+		scores = append(scores, score)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating LTI scores: %v", err)
+	}
+
+	op.rows(int64(len(scores)))
+	return scores, nil
+}
+
+// RecordLTIScoreAttempt stores the outcome of posting a launch's score
+func (r *SQLiteRepository) RecordLTIScoreAttempt(ctx context.Context, launchID int64, status, attemptErr string) (err error) {
+	ctx, op := instrument(ctx, "RecordLTIScoreAttempt")
+	defer func() { op.end(err) }()
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE lti_launches
+		SET score_status = ?, score_attempts = score_attempts + 1, score_error = NULLIF(?, ''),
+			score_posted_at = CASE WHEN ? = ? THEN CURRENT_TIMESTAMP ELSE score_posted_at END
+		WHERE id = ?
+	`, status, attemptErr, status, models.LTIScorePosted, launchID)
+	if err != nil {
+		return fmt.Errorf("error recording LTI score attempt: %v", err)
+	}
+	return nil
+}
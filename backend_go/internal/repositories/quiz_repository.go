@@ -0,0 +1,373 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/grading"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/metrics"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// GetQuizCandidates returns the words of a group along with how often
+// each has been reviewed incorrectly
+func (r *SQLiteRepository) GetQuizCandidates(ctx context.Context, groupID int64) (_ []models.QuizCandidate, err error) {
+	ctx, op := instrument(ctx, "GetQuizCandidates")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			w.id, w.arabic, w.romaji, w.english, COALESCE(w.parts, ''), w.created_at,
+			(
+				SELECT COUNT(*)
+				FROM word_review_items wri
+				WHERE wri.word_id = w.id AND NOT wri.is_correct
+			) as mistakes
+		FROM words w
+		JOIN words_groups wg ON wg.word_id = w.id
+		JOIN groups g ON g.id = wg.group_id
+		WHERE wg.group_id = ? AND w.deleted_at IS NULL AND g.deleted_at IS NULL
+		GROUP BY w.id
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying quiz candidates: %v", err)
+	}
+	defer rows.Close()
+
+	var candidates []models.QuizCandidate
+	for rows.Next() {
+		var candidate models.QuizCandidate
+		var partsStr string
+		err := rows.Scan(
+			&candidate.Word.ID,
+			&candidate.Word.Arabic,
+			&candidate.Word.Romaji,
+			&candidate.Word.English,
+			&partsStr,
+			&candidate.Word.CreatedAt,
+			&candidate.Mistakes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning quiz candidate: %v", err)
+		}
+
+		if partsStr != "" {
+			candidate.Word.Parts = json.RawMessage(partsStr)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quiz candidates: %v", err)
+	}
+
+	op.rows(int64(len(candidates)))
+	return candidates, nil
+}
+
+// GetWordConfusions returns how often words of a group were answered with
+// another word of the group, either picked as a quiz option or typed as a
+// wrong answer that matches the other word
+func (r *SQLiteRepository) GetWordConfusions(ctx context.Context, groupID int64) (_ []models.WordConfusion, err error) {
+	ctx, op := instrument(ctx, "GetWordConfusions")
+	defer func() { op.end(err) }()
+
+	confused := make(map[[2]int64]int)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT qq.word_id, qq.answer_word_id, COUNT(*)
+		FROM quiz_questions qq
+		JOIN words_groups wg ON wg.word_id = qq.word_id
+		WHERE wg.group_id = ?
+			AND qq.answer_word_id IS NOT NULL
+			AND qq.answer_word_id != qq.word_id
+		GROUP BY qq.word_id, qq.answer_word_id
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying word confusions: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var wordID, confusedWithID int64
+		var count int
+		if err := rows.Scan(&wordID, &confusedWithID, &count); err != nil {
+			return nil, fmt.Errorf("error scanning word confusion: %v", err)
+		}
+		confused[[2]int64{wordID, confusedWithID}] += count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word confusions: %v", err)
+	}
+
+	if err := r.typedConfusions(ctx, groupID, confused); err != nil {
+		return nil, err
+	}
+
+	confusions := make([]models.WordConfusion, 0, len(confused))
+	for pair, count := range confused {
+		confusions = append(confusions, models.WordConfusion{WordID: pair[0], ConfusedWithID: pair[1], Count: count})
+	}
+	sort.Slice(confusions, func(a, b int) bool {
+		if confusions[a].WordID != confusions[b].WordID {
+			return confusions[a].WordID < confusions[b].WordID
+		}
+		return confusions[a].ConfusedWithID < confusions[b].ConfusedWithID
+	})
+
+	op.rows(int64(len(confusions)))
+	return confusions, nil
+}
+
+// typedConfusions adds the wrong typed answers to a group's words that,
+// once normalized, are a form of another word of the group
+func (r *SQLiteRepository) typedConfusions(ctx context.Context, groupID int64, confused map[[2]int64]int) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT w.id, w.arabic, w.romaji, w.english
+		FROM words w
+		JOIN words_groups wg ON wg.word_id = w.id
+		WHERE wg.group_id = ? AND w.deleted_at IS NULL
+	`, groupID)
+	if err != nil {
+		return fmt.Errorf("error querying group words: %v", err)
+	}
+	defer rows.Close()
+
+	// forms maps each normalized form to its word, or to 0 when several
+	// words share it and a typed answer cannot be attributed
+	forms := make(map[string]int64)
+	for rows.Next() {
+		var id int64
+		var arabic, romaji, english string
+		if err := rows.Scan(&id, &arabic, &romaji, &english); err != nil {
+			return fmt.Errorf("error scanning group word: %v", err)
+		}
+		for _, form := range append([]string{arabic, romaji}, grading.Alternatives(english)...) {
+			form = grading.Normalize(form)
+			if form == "" {
+				continue
+			}
+			if other, ok := forms[form]; ok && other != id {
+				forms[form] = 0
+			} else {
+				forms[form] = id
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating group words: %v", err)
+	}
+
+	answers, err := r.db.QueryContext(ctx, `
+		SELECT wri.word_id, wri.answer_text, COUNT(*)
+		FROM word_review_items wri
+		JOIN words_groups wg ON wg.word_id = wri.word_id
+		WHERE wg.group_id = ? AND NOT wri.is_correct AND COALESCE(wri.answer_text, '') != ''
+		GROUP BY wri.word_id, wri.answer_text
+	`, groupID)
+	if err != nil {
+		return fmt.Errorf("error querying wrong answers: %v", err)
+	}
+	defer answers.Close()
+
+	for answers.Next() {
+		var wordID int64
+		var text string
+		var count int
+		if err := answers.Scan(&wordID, &text, &count); err != nil {
+			return fmt.Errorf("error scanning wrong answer: %v", err)
+		}
+		if other := forms[grading.Normalize(text)]; other != 0 && other != wordID {
+			confused[[2]int64{wordID, other}] += count
+		}
+	}
+	if err := answers.Err(); err != nil {
+		return fmt.Errorf("error iterating wrong answers: %v", err)
+	}
+	return nil
+}
+
+// CreateQuiz stores a generated quiz together with a new study session
+// that its answers are recorded against
+func (r *SQLiteRepository) CreateQuiz(ctx context.Context, quiz *models.Quiz, studyActivityID *int64) (err error) {
+	ctx, op := instrument(ctx, "CreateQuiz")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	session := models.StudySession{GroupID: quiz.GroupID}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO study_sessions (study_activity_id, group_id, learner_id)
+		VALUES (?, ?, ?)
+		RETURNING id, created_at
+	`, studyActivityID, quiz.GroupID, learnerFromContext(ctx)).Scan(&session.ID, &session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating study session: %v", err)
+	}
+	quiz.StudySessionID = session.ID
+
+	var queue eventQueue
+	if err := onSessionStarted(ctx, tx, &queue, &session); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO quizzes (group_id, study_session_id, direction)
+		VALUES (?, ?, ?)
+		RETURNING id, created_at
+	`, quiz.GroupID, quiz.StudySessionID, quiz.Direction).Scan(&quiz.ID, &quiz.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating quiz: %v", err)
+	}
+
+	for i := range quiz.Questions {
+		question := &quiz.Questions[i]
+
+		optionIDs := make([]int64, len(question.Options))
+		for j, option := range question.Options {
+			optionIDs[j] = option.WordID
+		}
+		options, err := json.Marshal(optionIDs)
+		if err != nil {
+			return fmt.Errorf("error encoding quiz options: %v", err)
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO quiz_questions (quiz_id, position, word_id, options)
+			VALUES (?, ?, ?, ?)
+			RETURNING id
+		`, quiz.ID, question.Position, question.WordID, string(options)).Scan(&question.ID)
+		if err != nil {
+			return fmt.Errorf("error creating quiz question: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing quiz: %v", err)
+	}
+	queue.publish()
+
+	metrics.SessionsStarted.Inc()
+	op.rows(int64(len(quiz.Questions)))
+	return nil
+}
+
+// AnswerQuiz grades answers to a quiz and records each as a word review
+// in the quiz's study session. Each question can only be answered once.
+func (r *SQLiteRepository) AnswerQuiz(ctx context.Context, quizID int64, answers []models.QuizAnswer) (_ []models.QuizAnswerResult, err error) {
+	ctx, op := instrument(ctx, "AnswerQuiz")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var sessionID sql.NullInt64
+	var direction string
+	err = tx.QueryRowContext(ctx, "SELECT study_session_id, direction FROM quizzes WHERE id = ?", quizID).Scan(&sessionID, &direction)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: quiz %d", ErrNotFound, quizID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying quiz: %v", err)
+	}
+	if err := checkSessionLearner(ctx, tx, sessionID.Int64); err != nil {
+		return nil, err
+	}
+
+	var queue eventQueue
+	results := make([]models.QuizAnswerResult, 0, len(answers))
+	for _, answer := range answers {
+		var wordID int64
+		var optionsJSON string
+		var answeredAt sql.NullTime
+		err := tx.QueryRowContext(ctx, `
+			SELECT word_id, options, answered_at
+			FROM quiz_questions
+			WHERE id = ? AND quiz_id = ?
+		`, answer.QuestionID, quizID).Scan(&wordID, &optionsJSON, &answeredAt)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: question %d in quiz %d", ErrNotFound, answer.QuestionID, quizID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error querying quiz question: %v", err)
+		}
+		if answeredAt.Valid {
+			return nil, fmt.Errorf("%w: question %d already answered", ErrConflict, answer.QuestionID)
+		}
+
+		var options []int64
+		if err := json.Unmarshal([]byte(optionsJSON), &options); err != nil {
+			return nil, fmt.Errorf("error decoding quiz options: %v", err)
+		}
+		if !containsID(options, answer.WordID) {
+			return nil, fmt.Errorf("%w: word %d is not an option of question %d", ErrInvalidInput, answer.WordID, answer.QuestionID)
+		}
+
+		result := models.QuizAnswerResult{
+			QuestionID:    answer.QuestionID,
+			Correct:       answer.WordID == wordID,
+			CorrectWordID: wordID,
+		}
+
+		review := models.WordReviewItem{
+			WordID:         wordID,
+			StudySessionID: sessionID.Int64,
+			IsCorrect:      result.Correct,
+			ResponseMS:     answer.ResponseMS,
+			Direction:      direction,
+			Mode:           models.ModeMultipleChoice,
+			HintsUsed:      answer.HintsUsed,
+			Confidence:     answer.Confidence,
+		}
+		if err := insertReview(ctx, tx, &review); err != nil {
+			return nil, fmt.Errorf("error creating word review item: %v", err)
+		}
+		result.ReviewID = review.ID
+
+		if err := onReviewRecorded(ctx, tx, &queue, &review); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE quiz_questions
+			SET answer_word_id = ?, word_review_item_id = ?, answered_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, answer.WordID, result.ReviewID, answer.QuestionID)
+		if err != nil {
+			return nil, fmt.Errorf("error recording quiz answer: %v", err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing quiz answers: %v", err)
+	}
+	queue.publish()
+
+	for _, result := range results {
+		metrics.RecordReview(result.Correct)
+	}
+
+	op.rows(int64(len(results)))
+	return results, nil
+}
+
+// containsID reports whether ids contains id
+func containsID(ids []int64, id int64) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// createQuiz stores a one-question quiz on word 1 with word 2 as the
+// other option, for the learner in ctx
+func createQuiz(t *testing.T, r *SQLiteRepository, learner string) *models.Quiz {
+	t.Helper()
+	quiz := &models.Quiz{
+		GroupID:   1,
+		Direction: models.DirectionArabicToEnglish,
+		Questions: []models.QuizQuestion{{
+			Position: 1,
+			WordID:   1,
+			Options:  []models.QuizOption{{WordID: 1}, {WordID: 2}},
+		}},
+	}
+	if err := r.CreateQuiz(asLearner(learner), quiz, nil); err != nil {
+		t.Fatalf("CreateQuiz: %v", err)
+	}
+	return quiz
+}
+
+func TestAnswerQuizChecksTheLearner(t *testing.T) {
+	r := newTestRepository(t)
+	quiz := createQuiz(t, r, "alice")
+	answer := []models.QuizAnswer{{QuestionID: quiz.Questions[0].ID, WordID: 2}}
+
+	if _, err := r.AnswerQuiz(asLearner("mallory"), quiz.ID, answer); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("AnswerQuiz as another learner: err = %v, want ErrNotFound", err)
+	}
+
+	results, err := r.AnswerQuiz(asLearner("alice"), quiz.ID, answer)
+	if err != nil {
+		t.Fatalf("AnswerQuiz: %v", err)
+	}
+	if len(results) != 1 || results[0].Correct || results[0].CorrectWordID != 1 {
+		t.Fatalf("results = %+v, want one wrong answer to word 1", results)
+	}
+
+	var learner, mode, direction string
+	var sessionID int64
+	err = r.db.QueryRow(`
+		SELECT learner_id, mode, direction, study_session_id FROM word_review_items WHERE id = ?
+	`, results[0].ReviewID).Scan(&learner, &mode, &direction, &sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if learner != "alice" || mode != models.ModeMultipleChoice || direction != quiz.Direction || sessionID != quiz.StudySessionID {
+		t.Errorf("review recorded as %s/%s/%s in session %d", learner, mode, direction, sessionID)
+	}
+}
+
+func TestGetWordConfusions(t *testing.T) {
+	r := newTestRepository(t)
+	for _, wordID := range []int64{1, 2, 3} {
+		mustExec(t, r, "INSERT INTO words_groups (word_id, group_id) VALUES (?, 1)", wordID)
+	}
+
+	// Picking word 2 for word 1 in a quiz
+	quiz := createQuiz(t, r, "alice")
+	if _, err := r.AnswerQuiz(asLearner("alice"), quiz.ID, []models.QuizAnswer{{QuestionID: quiz.Questions[0].ID, WordID: 2}}); err != nil {
+		t.Fatalf("AnswerQuiz: %v", err)
+	}
+
+	// Typing word 3's English or romaji for word 1, and an unrelated
+	// wrong answer
+	alice := asLearner("alice")
+	session := createSession(t, r, alice, 1)
+	for _, text := range []string{" One ", "wahid", "goodbye"} {
+		text := text
+		item := &models.WordReviewItem{StudySessionID: session.ID, WordID: 1, AnswerText: &text}
+		if err := r.CreateWordReviewItem(alice, item); err != nil {
+			t.Fatalf("CreateWordReviewItem: %v", err)
+		}
+	}
+
+	confusions, err := r.GetWordConfusions(alice, 1)
+	if err != nil {
+		t.Fatalf("GetWordConfusions: %v", err)
+	}
+	want := []models.WordConfusion{
+		{WordID: 1, ConfusedWithID: 2, Count: 1},
+		{WordID: 1, ConfusedWithID: 3, Count: 2},
+	}
+	if !reflect.DeepEqual(confusions, want) {
+		t.Errorf("confusions = %+v, want %+v", confusions, want)
+	}
+}
+
+func TestQuizSessionWithoutActivity(t *testing.T) {
+	r := newTestRepository(t)
+	quiz := createQuiz(t, r, "alice")
+	alice := asLearner("alice")
+
+	sessions, err := r.GetStudySessionsByGroupID(alice, quiz.GroupID)
+	if err != nil {
+		t.Fatalf("GetStudySessionsByGroupID: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != quiz.StudySessionID || sessions[0].StudyActivityID != 0 {
+		t.Fatalf("sessions = %+v, want the quiz session without an activity", sessions)
+	}
+
+	session, err := r.GetStudySession(alice, quiz.StudySessionID)
+	if err != nil || session == nil {
+		t.Fatalf("GetStudySession = %+v, %v", session, err)
+	}
+
+	finished, err := r.FinishStudySession(alice, quiz.StudySessionID)
+	if err != nil {
+		t.Fatalf("FinishStudySession: %v", err)
+	}
+	if finished.FinishedAt == nil {
+		t.Errorf("finished session = %+v, want finished_at set", finished)
+	}
+}
@@ -1,41 +1,151 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	// ErrNotFound is returned when a mutation targets a missing row
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidRevert is returned when a history version cannot be restored
+	ErrInvalidRevert = errors.New("invalid revert")
+	// ErrConflict is returned when a write clashes with existing state
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidInput is returned when a write references invalid data
+	ErrInvalidInput = errors.New("invalid input")
 )
 
 // Repository defines all database operations
 type Repository interface {
 	// Word operations
-	GetWords(groupID int64, search string, page, pageSize int) ([]models.Word, int, error)
-	GetWordByID(id int64) (*models.Word, error)
-	CreateWord(word *models.Word) error
-	UpdateWord(word *models.Word) error
-	DeleteWord(id int64) error
+	GetWords(ctx context.Context, groupID int64, search string, page, pageSize int) ([]models.Word, int, error)
+	GetWordByID(ctx context.Context, id int64) (*models.Word, error)
+	CreateWord(ctx context.Context, word *models.Word) error
+	UpdateWord(ctx context.Context, word *models.Word) error
+	DeleteWord(ctx context.Context, id int64) error
+	RevertWord(ctx context.Context, id int64, version int) (*models.Word, error)
+	RestoreWord(ctx context.Context, id int64) (*models.Word, error)
 
 	// Group operations
-	GetGroups() ([]models.Group, error)
-	GetGroupByID(id int64) (*models.Group, error)
-	CreateGroup(group *models.Group) error
-	UpdateGroup(group *models.Group) error
-	DeleteGroup(id int64) error
+	GetGroups(ctx context.Context) ([]models.Group, error)
+	GetGroupByID(ctx context.Context, id int64) (*models.Group, error)
+	CreateGroup(ctx context.Context, group *models.Group) error
+	UpdateGroup(ctx context.Context, group *models.Group) error
+	DeleteGroup(ctx context.Context, id int64) error
+	AddWordToGroup(ctx context.Context, groupID, wordID int64) error
+	RemoveWordFromGroup(ctx context.Context, groupID, wordID int64) error
+	RestoreGroup(ctx context.Context, id int64) (*models.Group, error)
+	CreateGroupWithWords(ctx context.Context, group *models.Group, wordIDs []int64) error
 
 	// Study session operations
-	GetLastStudySession() (*models.StudySession, error)
-	GetStudySessionsByActivityID(activityID int64) ([]models.StudySession, error)
-	CreateStudySession(session *models.StudySession) error
+	GetLastStudySession(ctx context.Context) (*models.StudySession, error)
+	GetStudySessionsByActivityID(ctx context.Context, activityID int64) ([]models.StudySession, error)
+	GetStudySessionsByGroupID(ctx context.Context, groupID int64) ([]models.StudySession, error)
+	CreateStudySession(ctx context.Context, session *models.StudySession) error
+	GetStudySession(ctx context.Context, id int64) (*models.StudySession, error)
+	FinishStudySession(ctx context.Context, id int64) (*models.StudySession, error)
 
 	// Study activity operations
-	GetStudyActivities() ([]models.StudyActivity, error)
-	GetStudyActivity(id int64) (*models.StudyActivity, error)
-	CreateStudyActivity(activity *models.StudyActivity) error
-	GetStudyProgress(days int) ([]models.StudyActivity, error)
+	GetStudyActivities(ctx context.Context) ([]models.StudyActivity, error)
+	GetStudyActivity(ctx context.Context, id int64) (*models.StudyActivity, error)
+	CreateStudyActivity(ctx context.Context, activity *models.StudyActivity) error
 
 	// Word review operations
-	GetWordReviewItems(sessionID int64) ([]models.WordReviewItem, error)
-	CreateWordReviewItem(review *models.WordReviewItem) error
-	GetQuickStats() (*models.DashboardStats, error)
+	GetWordReviewItems(ctx context.Context, sessionID int64) ([]models.WordReviewItem, error)
+	CreateWordReviewItem(ctx context.Context, review *models.WordReviewItem) error
+	CreateReviewBatch(ctx context.Context, sessionID int64, idempotencyKey, requestHash string, items []models.WordReviewItem) (*models.ReviewBatchResult, bool, error)
+	GetWordReviewItemsByWord(ctx context.Context, wordID int64) ([]models.WordReviewItem, error)
+	GetLearnerWordReviews(ctx context.Context, wordID int64) ([]models.WordReviewItem, error)
+	GetSessionReviewAnalytics(ctx context.Context, sessionID int64) (*models.ReviewAnalytics, error)
+	GetWordReviewAnalytics(ctx context.Context, wordID int64) (*models.ReviewAnalytics, error)
+	GetWordErrorCounts(ctx context.Context, groupID int64) ([]models.DifficultWord, error)
+	GetGroupReviews(ctx context.Context, groupID int64) ([]models.WordReviewItem, error)
+	GetQuickStats(ctx context.Context) (*models.DashboardStats, error)
+	GetWordProgress(ctx context.Context) ([]models.WordProgress, error)
+	GetGroupMemberships(ctx context.Context) ([]models.WordGroup, error)
+	GetLearnerReviews(ctx context.Context) ([]models.WordReviewItem, error)
+
+	// Scheduling operations
+	GetDueWords(ctx context.Context, scheduler string, groupID int64, limit int) ([]models.DueWord, error)
+	GetWordSchedules(ctx context.Context, wordID int64) ([]models.WordSchedule, error)
+	GetLearnerScheduler(ctx context.Context) (string, error)
+	SetLearnerScheduler(ctx context.Context, scheduler string) error
+	GetGroupScheduler(ctx context.Context, groupID int64) (string, error)
+	SetGroupScheduler(ctx context.Context, groupID int64, scheduler string) error
+
+	// Gamification operations
+	GetAchievements(ctx context.Context) (*models.Achievements, error)
+	GetGoalSettings(ctx context.Context) (models.GoalSettings, error)
+	SetGoalSettings(ctx context.Context, settings models.GoalSettings) error
+	GetDailyActivity(ctx context.Context, days int) ([]models.DailyActivity, error)
+
+	// Leaderboard operations
+	GetLeaderboard(ctx context.Context, groupID int64, period, metric string, limit, minReviews int) ([]models.LeaderboardEntry, *models.LeaderboardEntry, error)
+	GetProfile(ctx context.Context) (*models.LearnerProfile, error)
+	UpdateProfile(ctx context.Context, profile *models.LearnerProfile) error
+	BackfillLeaderboards(ctx context.Context) error
+
+	// Sync operations
+	Sync(ctx context.Context, req *models.SyncRequest) (*models.SyncResponse, error)
+
+	// Quiz operations
+	GetQuizCandidates(ctx context.Context, groupID int64) ([]models.QuizCandidate, error)
+	GetWordConfusions(ctx context.Context, groupID int64) ([]models.WordConfusion, error)
+	CreateQuiz(ctx context.Context, quiz *models.Quiz, studyActivityID *int64) error
+	AnswerQuiz(ctx context.Context, quizID int64, answers []models.QuizAnswer) ([]models.QuizAnswerResult, error)
+
+	// Trash operations
+	GetTrash(ctx context.Context) (*models.Trash, error)
+	PurgeTrash(ctx context.Context, cutoff time.Time) (int, error)
+
+	// Webhook operations
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (*models.Webhook, error)
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	GetWebhookDeliveries(ctx context.Context, webhookID int64, status string, limit int) ([]models.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (*models.WebhookDelivery, error)
+	GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]models.OutboxDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, attempt models.WebhookAttempt) error
+
+	// LTI operations
+	GetLTIPlatforms(ctx context.Context) ([]models.LTIPlatform, error)
+	GetLTIPlatform(ctx context.Context, id int64) (*models.LTIPlatform, error)
+	FindLTIPlatform(ctx context.Context, issuer, clientID string) (*models.LTIPlatform, error)
+	CreateLTIPlatform(ctx context.Context, platform *models.LTIPlatform) error
+	DeleteLTIPlatform(ctx context.Context, id int64) error
+	GetLTIKey(ctx context.Context) (kid, privateKey string, err error)
+	CreateLTIKey(ctx context.Context, kid, privateKey string) error
+	CreateLTILoginState(ctx context.Context, state, nonce string, platformID int64, expires time.Time) error
+	ConsumeLTILoginState(ctx context.Context, state string) (nonce string, platformID int64, err error)
+	UpsertLTIUser(ctx context.Context, user *models.LTIUser) error
+	CreateLTILaunch(ctx context.Context, launch *models.LTILaunch) error
+	ConsumeLTIDeepLink(ctx context.Context, key string, ttl time.Duration) (*models.LTILaunch, error)
+	GetPendingLTIScores(ctx context.Context, limit int) ([]models.LTIScore, error)
+	RecordLTIScoreAttempt(ctx context.Context, launchID int64, status, attemptErr string) error
+
+	// Batched lookups and keyset pages for the GraphQL API
+	GetWordsByIDs(ctx context.Context, ids []int64) ([]models.Word, error)
+	GetGroupsByIDs(ctx context.Context, ids []int64) ([]models.Group, error)
+	GetStudyActivitiesByIDs(ctx context.Context, ids []int64) ([]models.StudyActivity, error)
+	GetStudySessionsByIDs(ctx context.Context, ids []int64) ([]models.StudySession, error)
+	GetWordGroupsByWordIDs(ctx context.Context, wordIDs []int64) ([]models.WordGroup, error)
+	PageWords(ctx context.Context, search string, page models.PageRequest) ([]models.Word, error)
+	PageGroupWords(ctx context.Context, groupIDs []int64, page models.PageRequest) ([]models.WordGroup, error)
+	PageGroups(ctx context.Context, page models.PageRequest) ([]models.Group, error)
+	PageStudyActivities(ctx context.Context, groupIDs []int64, page models.PageRequest) ([]models.StudyActivity, error)
+	PageStudySessions(ctx context.Context, by string, parentIDs []int64, page models.PageRequest) ([]models.StudySession, error)
+	PageWordReviews(ctx context.Context, by string, parentIDs []int64, page models.PageRequest) ([]models.WordReviewItem, error)
+
+	// Audit operations
+	GetAuditHistory(ctx context.Context, entityType string, entityID int64) ([]models.AuditEvent, error)
 }
 
 // SQLiteRepository implements Repository interface
@@ -47,3 +157,11 @@ type SQLiteRepository struct {
 func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
 	return &SQLiteRepository{db: db}
 }
+
+// isUniqueViolation reports whether err is a failed UNIQUE or PRIMARY KEY
+// constraint
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey)
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/db"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// TestMain runs the tests from the module root, where InitDB finds the
+// migrations and seed data, without its log output
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := os.Chdir(filepath.Join("..", "..")); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestRepository returns a repository over a freshly migrated and seeded
+// database in a temporary directory
+func newTestRepository(t *testing.T) *SQLiteRepository {
+	t.Helper()
+	conn, err := db.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewSQLiteRepository(conn)
+}
+
+// asLearner returns a context whose requests are made by learner
+func asLearner(learner string) context.Context {
+	return WithAuditInfo(context.Background(), AuditInfo{Actor: learner})
+}
+
+// mustExec runs a statement against the test database
+func mustExec(t *testing.T, r *SQLiteRepository, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := r.db.Exec(query, args...); err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+}
+
+// createSession starts a study session for the learner in ctx
+func createSession(t *testing.T, r *SQLiteRepository, ctx context.Context, groupID int64) *models.StudySession {
+	t.Helper()
+	activity := &models.StudyActivity{GroupID: groupID}
+	if err := r.CreateStudyActivity(ctx, activity); err != nil {
+		t.Fatalf("CreateStudyActivity: %v", err)
+	}
+	session := &models.StudySession{StudyActivityID: activity.ID, GroupID: groupID}
+	if err := r.CreateStudySession(ctx, session); err != nil {
+		t.Fatalf("CreateStudySession: %v", err)
+	}
+	return session
+}
+
+// review records one answer in a session and returns the stored review
+func review(t *testing.T, r *SQLiteRepository, ctx context.Context, sessionID, wordID int64, correct bool) *models.WordReviewItem {
+	t.Helper()
+	item := &models.WordReviewItem{StudySessionID: sessionID, WordID: wordID, IsCorrect: correct}
+	if err := r.CreateWordReviewItem(ctx, item); err != nil {
+		t.Fatalf("CreateWordReviewItem: %v", err)
+	}
+	return item
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/metrics"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// CreateReviewBatch records a batch of reviews for a study session in one
+// transaction. Items whose client_id is already stored are reported as
+// duplicates and items for unknown words, or whose client_id belongs to
+// another session, are rejected. When idempotencyKey is set the result is
+// stored under it for the session, and a retry with the same key and
+// request hash returns the stored result with replayed set.
+func (r *SQLiteRepository) CreateReviewBatch(ctx context.Context, sessionID int64, idempotencyKey, requestHash string, items []models.WordReviewItem) (_ *models.ReviewBatchResult, replayed bool, err error) {
+	ctx, op := instrument(ctx, "CreateReviewBatch")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Check the learner first so a stored result is only replayed to the
+	// learner whose session it belongs to
+	if err := checkSessionLearner(ctx, tx, sessionID); err != nil {
+		return nil, false, err
+	}
+
+	if idempotencyKey != "" {
+		stored, err := getIdempotentResult(ctx, tx, idempotencyKey, sessionID, requestHash)
+		if err != nil || stored != nil {
+			return stored, stored != nil, err
+		}
+	}
+
+	result := &models.ReviewBatchResult{Results: make([]models.ReviewBatchItemResult, 0, len(items))}
+	var created []bool
+	var queue eventQueue
+	knownWords := map[int64]bool{}
+	for i := range items {
+		review := items[i]
+		review.StudySessionID = sessionID
+		item := models.ReviewBatchItemResult{Index: i, ClientID: review.ClientID}
+
+		known, checked := knownWords[review.WordID]
+		if !checked {
+			err := tx.QueryRowContext(ctx, `
+				SELECT EXISTS(SELECT 1 FROM words WHERE id = ? AND deleted_at IS NULL)
+			`, review.WordID).Scan(&known)
+			if err != nil {
+				return nil, false, fmt.Errorf("error querying word: %v", err)
+			}
+			knownWords[review.WordID] = known
+		}
+
+		if !known {
+			item.Status = models.BatchItemRejected
+			item.Error = fmt.Sprintf("word %d not found", review.WordID)
+			result.Rejected++
+			result.Results = append(result.Results, item)
+			continue
+		}
+
+		err := insertReview(ctx, tx, &review)
+		if err == sql.ErrNoRows {
+			// Only a review already in this session is a duplicate; a
+			// client_id from another session must not reveal its review
+			err := tx.QueryRowContext(ctx, `
+				SELECT id FROM word_review_items WHERE client_id = ? AND study_session_id = ?
+			`, *review.ClientID, sessionID).Scan(&item.ReviewID)
+			if err == sql.ErrNoRows {
+				item.Status = models.BatchItemRejected
+				item.Error = fmt.Sprintf("client_id %q was used in another session", *review.ClientID)
+				result.Rejected++
+				result.Results = append(result.Results, item)
+				continue
+			}
+			if err != nil {
+				return nil, false, fmt.Errorf("error querying duplicate review: %v", err)
+			}
+			item.Status = models.BatchItemDuplicate
+			result.Duplicates++
+		} else if err != nil {
+			return nil, false, fmt.Errorf("error creating word review item: %v", err)
+		} else {
+			if err := onReviewRecorded(ctx, tx, &queue, &review); err != nil {
+				return nil, false, err
+			}
+			item.Status = models.BatchItemCreated
+			item.ReviewID = review.ID
+			result.Created++
+			created = append(created, review.IsCorrect)
+		}
+
+		result.Results = append(result.Results, item)
+	}
+
+	if idempotencyKey != "" {
+		response, err := json.Marshal(result)
+		if err != nil {
+			return nil, false, fmt.Errorf("error encoding batch result: %v", err)
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO idempotency_keys (key, study_session_id, request_hash, response)
+			VALUES (?, ?, ?, ?)
+		`, idempotencyKey, sessionID, requestHash, string(response))
+		if isUniqueViolation(err) {
+			// A concurrent request with the same key committed first; drop
+			// this attempt and answer with its result
+			tx.Rollback()
+			stored, err := getIdempotentResult(ctx, r.db, idempotencyKey, sessionID, requestHash)
+			if err == nil && stored == nil {
+				err = fmt.Errorf("%w: idempotency key %q is in use by a concurrent request", ErrConflict, idempotencyKey)
+			}
+			return stored, stored != nil, err
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("error storing idempotency key: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("error committing review batch: %v", err)
+	}
+	queue.publish()
+
+	for _, correct := range created {
+		metrics.RecordReview(correct)
+	}
+
+	op.rows(int64(result.Created))
+	return result, false, nil
+}
+
+// getIdempotentResult returns the stored result for an idempotency key in
+// a session, or nil if the key is unused there. Reusing a key for a
+// different request to the same session is a conflict.
+func getIdempotentResult(ctx context.Context, q interface {
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}, key string, sessionID int64, requestHash string) (*models.ReviewBatchResult, error) {
+	var storedHash, response string
+	err := q.QueryRowContext(ctx, `
+		SELECT request_hash, response
+		FROM idempotency_keys
+		WHERE key = ? AND study_session_id = ?
+	`, key, sessionID).Scan(&storedHash, &response)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying idempotency key: %v", err)
+	}
+	if storedHash != requestHash {
+		return nil, fmt.Errorf("%w: idempotency key %q was used for a different request", ErrConflict, key)
+	}
+
+	var result models.ReviewBatchResult
+	if err := json.Unmarshal([]byte(response), &result); err != nil {
+		return nil, fmt.Errorf("error decoding stored batch result: %v", err)
+	}
+
+	return &result, nil
+}
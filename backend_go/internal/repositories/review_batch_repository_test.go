@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+func TestReviewBatchIdempotencyKeys(t *testing.T) {
+	r := newTestRepository(t)
+	alice, bob := asLearner("alice"), asLearner("bob")
+	aliceSession := createSession(t, r, alice, 1)
+	bobSession := createSession(t, r, bob, 1)
+	items := []models.WordReviewItem{{WordID: 1, IsCorrect: true}, {WordID: 2}}
+
+	first, replayed, err := r.CreateReviewBatch(alice, aliceSession.ID, "key-1", "hash-1", items)
+	if err != nil || replayed || first.Created != 2 {
+		t.Fatalf("first batch = %+v, %v, %v; want 2 created", first, replayed, err)
+	}
+
+	again, replayed, err := r.CreateReviewBatch(alice, aliceSession.ID, "key-1", "hash-1", items)
+	if err != nil || !replayed || again.Results[0].ReviewID != first.Results[0].ReviewID {
+		t.Errorf("retry = %+v, %v, %v; want the stored result replayed", again, replayed, err)
+	}
+
+	if _, _, err := r.CreateReviewBatch(alice, aliceSession.ID, "key-1", "hash-2", items); !errors.Is(err, ErrConflict) {
+		t.Errorf("key reused for another body: err = %v, want ErrConflict", err)
+	}
+
+	// Another learner replaying alice's request learns nothing about it
+	if _, replayed, err := r.CreateReviewBatch(bob, aliceSession.ID, "key-1", "hash-1", items); !errors.Is(err, ErrNotFound) || replayed {
+		t.Errorf("bob replaying alice's batch: replayed %v, err = %v, want ErrNotFound", replayed, err)
+	}
+
+	// and is free to use the same key in his own session
+	result, replayed, err := r.CreateReviewBatch(bob, bobSession.ID, "key-1", "hash-2", items)
+	if err != nil || replayed || result.Created != 2 {
+		t.Errorf("bob's batch with the same key = %+v, %v, %v; want 2 created", result, replayed, err)
+	}
+}
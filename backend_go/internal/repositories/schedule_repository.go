@@ -0,0 +1,294 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/scheduler"
+)
+
+// scheduleTimeLayout is how schedule times are stored so that they
+// compare correctly as text
+const scheduleTimeLayout = "2006-01-02 15:04:05"
+
+// learnerFromContext returns the learner making the request, or "" for
+// anonymous callers, who share one schedule
+func learnerFromContext(ctx context.Context) string {
+	return auditInfoFromContext(ctx).Actor
+}
+
+// scheduleReview advances the reviewed word's state under every scheduler
+// for the learner. Reviews older than the last one scheduled, such as
+// offline reviews synced late, are skipped.
+func scheduleReview(ctx context.Context, tx *sql.Tx, review *models.WordReviewItem) error {
+	learner := learnerFromContext(ctx)
+	quality := scheduler.Quality(review.IsCorrect, review.Confidence, review.HintsUsed)
+	reviewedAt := review.CreatedAt.UTC()
+
+	for _, s := range scheduler.All() {
+		state, err := wordSchedule(ctx, tx, learner, review.WordID, s.Name())
+		if err != nil {
+			return err
+		}
+		if state == nil {
+			state = &models.WordSchedule{WordID: review.WordID, Scheduler: s.Name()}
+		} else if reviewedAt.Before(state.LastReviewedAt) {
+			continue
+		}
+
+		next := s.Next(*state, quality, reviewedAt)
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO word_schedules
+				(learner_id, word_id, scheduler, box, ease_factor, interval_days, repetitions, due_at, last_reviewed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(learner_id, word_id, scheduler) DO UPDATE SET
+				box = excluded.box,
+				ease_factor = excluded.ease_factor,
+				interval_days = excluded.interval_days,
+				repetitions = excluded.repetitions,
+				due_at = excluded.due_at,
+				last_reviewed_at = excluded.last_reviewed_at
+		`, learner, next.WordID, next.Scheduler, next.Box, next.EaseFactor, next.IntervalDays, next.Repetitions,
+			next.DueAt.UTC().Format(scheduleTimeLayout), next.LastReviewedAt.UTC().Format(scheduleTimeLayout))
+		if err != nil {
+			return fmt.Errorf("error updating word schedule: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// wordSchedule returns a learner's state for a word under a scheduler, or
+// nil if the word has not been reviewed with it yet
+func wordSchedule(ctx context.Context, tx *sql.Tx, learner string, wordID int64, schedulerName string) (*models.WordSchedule, error) {
+	state := models.WordSchedule{WordID: wordID, Scheduler: schedulerName}
+	err := tx.QueryRowContext(ctx, `
+		SELECT box, ease_factor, interval_days, repetitions, due_at, last_reviewed_at
+		FROM word_schedules
+		WHERE learner_id = ? AND word_id = ? AND scheduler = ?
+	`, learner, wordID, schedulerName).Scan(
+		&state.Box,
+		&state.EaseFactor,
+		&state.IntervalDays,
+		&state.Repetitions,
+		&state.DueAt,
+		&state.LastReviewedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying word schedule: %v", err)
+	}
+	return &state, nil
+}
+
+// GetDueWords returns the learner's words that are due under a scheduler,
+// most overdue first, followed by words never reviewed with it. The queue
+// is limited to a group when groupID is set.
+func (r *SQLiteRepository) GetDueWords(ctx context.Context, schedulerName string, groupID int64, limit int) (_ []models.DueWord, err error) {
+	ctx, op := instrument(ctx, "GetDueWords")
+	defer func() { op.end(err) }()
+
+	query := `
+		SELECT w.id, w.arabic, w.romaji, w.english, COALESCE(w.parts, ''), w.created_at,
+			ws.box, ws.ease_factor, ws.interval_days, ws.repetitions, ws.due_at, ws.last_reviewed_at
+		FROM words w
+		LEFT JOIN word_schedules ws
+			ON ws.word_id = w.id AND ws.learner_id = ? AND ws.scheduler = ?
+		WHERE w.deleted_at IS NULL
+			AND (ws.word_id IS NULL OR ws.due_at <= ?)
+	`
+	args := []interface{}{learnerFromContext(ctx), schedulerName, time.Now().UTC().Format(scheduleTimeLayout)}
+	if groupID > 0 {
+		query += " AND w.id IN (SELECT word_id FROM words_groups WHERE group_id = ?)"
+		args = append(args, groupID)
+	}
+	query += " ORDER BY ws.word_id IS NULL, ws.due_at, w.id LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying due words: %v", err)
+	}
+	defer rows.Close()
+
+	words := []models.DueWord{}
+	for rows.Next() {
+		var due models.DueWord
+		var parts string
+		var box, repetitions sql.NullInt64
+		var ease, interval sql.NullFloat64
+		var dueAt, lastReviewedAt sql.NullTime
+		err := rows.Scan(
+			&due.Word.ID,
+			&due.Word.Arabic,
+			&due.Word.Romaji,
+			&due.Word.English,
+			&parts,
+			&due.Word.CreatedAt,
+			&box,
+			&ease,
+			&interval,
+			&repetitions,
+			&dueAt,
+			&lastReviewedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning due word: %v", err)
+		}
+		if parts != "" {
+			due.Word.Parts = json.RawMessage(parts)
+		}
+		if dueAt.Valid {
+			due.Schedule = &models.WordSchedule{
+				WordID:         due.Word.ID,
+				Scheduler:      schedulerName,
+				Box:            int(box.Int64),
+				EaseFactor:     ease.Float64,
+				IntervalDays:   interval.Float64,
+				Repetitions:    int(repetitions.Int64),
+				DueAt:          dueAt.Time,
+				LastReviewedAt: lastReviewedAt.Time,
+			}
+		}
+
+		words = append(words, due)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating due words: %v", err)
+	}
+
+	op.rows(int64(len(words)))
+	return words, nil
+}
+
+// GetWordSchedules returns the learner's state for a word under each
+// scheduler it has been reviewed with
+func (r *SQLiteRepository) GetWordSchedules(ctx context.Context, wordID int64) (_ []models.WordSchedule, err error) {
+	ctx, op := instrument(ctx, "GetWordSchedules")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT scheduler, box, ease_factor, interval_days, repetitions, due_at, last_reviewed_at
+		FROM word_schedules
+		WHERE learner_id = ? AND word_id = ?
+		ORDER BY scheduler
+	`, learnerFromContext(ctx), wordID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying word schedules: %v", err)
+	}
+	defer rows.Close()
+
+	var schedules []models.WordSchedule
+	for rows.Next() {
+		state := models.WordSchedule{WordID: wordID}
+		err := rows.Scan(
+			&state.Scheduler,
+			&state.Box,
+			&state.EaseFactor,
+			&state.IntervalDays,
+			&state.Repetitions,
+			&state.DueAt,
+			&state.LastReviewedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning word schedule: %v", err)
+		}
+		schedules = append(schedules, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word schedules: %v", err)
+	}
+
+	op.rows(int64(len(schedules)))
+	return schedules, nil
+}
+
+// GetLearnerScheduler returns the scheduler chosen by the learner, or ""
+// if they have not chosen one
+func (r *SQLiteRepository) GetLearnerScheduler(ctx context.Context) (_ string, err error) {
+	ctx, op := instrument(ctx, "GetLearnerScheduler")
+	defer func() { op.end(err) }()
+
+	var name string
+	err = r.db.QueryRowContext(ctx, "SELECT scheduler FROM learner_settings WHERE learner_id = ?", learnerFromContext(ctx)).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error querying learner scheduler: %v", err)
+	}
+	return name, nil
+}
+
+// SetLearnerScheduler stores the learner's scheduler choice; "" clears it
+func (r *SQLiteRepository) SetLearnerScheduler(ctx context.Context, name string) (err error) {
+	ctx, op := instrument(ctx, "SetLearnerScheduler")
+	defer func() { op.end(err) }()
+
+	learner := learnerFromContext(ctx)
+	if name == "" {
+		_, err = r.db.ExecContext(ctx, "DELETE FROM learner_settings WHERE learner_id = ?", learner)
+	} else {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO learner_settings (learner_id, scheduler) VALUES (?, ?)
+			ON CONFLICT(learner_id) DO UPDATE SET scheduler = excluded.scheduler
+		`, learner, name)
+	}
+	if err != nil {
+		return fmt.Errorf("error storing learner scheduler: %v", err)
+	}
+	return nil
+}
+
+// GetGroupScheduler returns the scheduler chosen for a group, or "" if
+// none was chosen
+func (r *SQLiteRepository) GetGroupScheduler(ctx context.Context, groupID int64) (_ string, err error) {
+	ctx, op := instrument(ctx, "GetGroupScheduler")
+	defer func() { op.end(err) }()
+
+	var name string
+	err = r.db.QueryRowContext(ctx, "SELECT scheduler FROM group_settings WHERE group_id = ?", groupID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error querying group scheduler: %v", err)
+	}
+	return name, nil
+}
+
+// SetGroupScheduler stores the scheduler chosen for a group; "" clears it
+func (r *SQLiteRepository) SetGroupScheduler(ctx context.Context, groupID int64, name string) (err error) {
+	ctx, op := instrument(ctx, "SetGroupScheduler")
+	defer func() { op.end(err) }()
+
+	var exists bool
+	err = r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM groups WHERE id = ? AND deleted_at IS NULL)", groupID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("error querying group: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: group %d", ErrNotFound, groupID)
+	}
+
+	if name == "" {
+		_, err = r.db.ExecContext(ctx, "DELETE FROM group_settings WHERE group_id = ?", groupID)
+	} else {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO group_settings (group_id, scheduler) VALUES (?, ?)
+			ON CONFLICT(group_id) DO UPDATE SET scheduler = excluded.scheduler
+		`, groupID, name)
+	}
+	if err != nil {
+		return fmt.Errorf("error storing group scheduler: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// GetWordErrorCounts returns every reviewed word with its attempts and
+// wrong answers, limited to a group when groupID is set
+func (r *SQLiteRepository) GetWordErrorCounts(ctx context.Context, groupID int64) (_ []models.DifficultWord, err error) {
+	ctx, op := instrument(ctx, "GetWordErrorCounts")
+	defer func() { op.end(err) }()
+
+	query := `
+		SELECT w.id, w.arabic, w.romaji, w.english, COALESCE(w.parts, ''), w.created_at,
+			COUNT(wri.id),
+			COALESCE(SUM(CASE WHEN wri.is_correct THEN 0 ELSE 1 END), 0)
+		FROM words w
+		JOIN word_review_items wri ON wri.word_id = w.id
+		WHERE w.deleted_at IS NULL
+	`
+	args := []interface{}{}
+	if groupID > 0 {
+		query += ` AND w.id IN (
+			SELECT wg.word_id FROM words_groups wg
+			JOIN groups g ON g.id = wg.group_id
+			WHERE wg.group_id = ? AND g.deleted_at IS NULL
+		)`
+		args = append(args, groupID)
+	}
+	query += " GROUP BY w.id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying word error counts: %v", err)
+	}
+	defer rows.Close()
+
+	var counts []models.DifficultWord
+	for rows.Next() {
+		var count models.DifficultWord
+		var partsStr string
+		err := rows.Scan(
+			&count.Word.ID,
+			&count.Word.Arabic,
+			&count.Word.Romaji,
+			&count.Word.English,
+			&partsStr,
+			&count.Word.CreatedAt,
+			&count.Attempts,
+			&count.Errors,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning word error count: %v", err)
+		}
+
+		if partsStr != "" {
+			count.Word.Parts = json.RawMessage(partsStr)
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word error counts: %v", err)
+	}
+
+	op.rows(int64(len(counts)))
+	return counts, nil
+}
+
+// GetGroupReviews returns every review of the words in a group, newest first
+func (r *SQLiteRepository) GetGroupReviews(ctx context.Context, groupID int64) (_ []models.WordReviewItem, err error) {
+	ctx, op := instrument(ctx, "GetGroupReviews")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM word_review_items
+		WHERE word_id IN (
+			SELECT wg.word_id FROM words_groups wg
+			JOIN words w ON w.id = wg.word_id
+			WHERE wg.group_id = ? AND w.deleted_at IS NULL
+		)
+		ORDER BY created_at DESC, id DESC
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying group reviews: %v", err)
+	}
+	defer rows.Close()
+
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(reviews)))
+	return reviews, nil
+}
+
+// GetLearnerReviews returns every review the context's learner made of a
+// live word, oldest first
+func (r *SQLiteRepository) GetLearnerReviews(ctx context.Context) (_ []models.WordReviewItem, err error) {
+	ctx, op := instrument(ctx, "GetLearnerReviews")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM word_review_items
+		WHERE learner_id = ?
+			AND word_id IN (SELECT id FROM words WHERE deleted_at IS NULL)
+		ORDER BY created_at, id
+	`, learnerFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error querying learner reviews: %v", err)
+	}
+	defer rows.Close()
+
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(reviews)))
+	return reviews, nil
+}
@@ -1,37 +1,45 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"time"
+	"math"
 
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/metrics"
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
 )
 
-// GetLastStudySession retrieves the most recent study session
-func (r *SQLiteRepository) GetLastStudySession() (*models.StudySession, error) {
-	var session models.StudySession
-	var groupName string
+// GetLastStudySession retrieves the most recent study session of the
+// learner in ctx
+func (r *SQLiteRepository) GetLastStudySession(ctx context.Context) (_ *models.StudySession, err error) {
+	ctx, op := instrument(ctx, "GetLastStudySession")
+	defer func() { op.end(err) }()
 
-	err := r.db.QueryRow(`
-		SELECT 
-			s.id, s.group_id, s.created_at,
-			g.name,
-			COUNT(w.id) as words_reviewed,
-			SUM(CASE WHEN w.correct THEN 1 ELSE 0 END) as correct_count
+	var session models.StudySession
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			s.id, COALESCE(s.study_activity_id, 0), COALESCE(s.group_id, 0), s.created_at,
+			COALESCE(g.name, ''),
+			COUNT(wri.id) as words_reviewed,
+			COALESCE(SUM(CASE WHEN wri.is_correct THEN 1 ELSE 0 END), 0) as correct_count,
+			COALESCE(SUM(CASE WHEN NOT wri.is_correct THEN 1 ELSE 0 END), 0) as wrong_count
 		FROM study_sessions s
-		JOIN groups g ON s.group_id = g.id
-		LEFT JOIN word_review_items w ON s.id = w.study_session_id
+		LEFT JOIN groups g ON s.group_id = g.id
+		LEFT JOIN word_review_items wri ON s.id = wri.study_session_id
+		WHERE s.learner_id = ?
 		GROUP BY s.id
-		ORDER BY s.created_at DESC
+		ORDER BY s.created_at DESC, s.id DESC
 		LIMIT 1
-	`).Scan(
+	`, learnerFromContext(ctx)).Scan(
 		&session.ID,
+		&session.StudyActivityID,
 		&session.GroupID,
 		&session.CreatedAt,
-		&groupName,
+		&session.GroupName,
 		&session.WordsReviewed,
 		&session.CorrectCount,
+		&session.WrongCount,
 	)
 
 	if err == sql.ErrNoRows {
@@ -43,14 +51,17 @@ func (r *SQLiteRepository) GetLastStudySession() (*models.StudySession, error) {
 
 	session.Group = &models.Group{
 		ID:   session.GroupID,
-		Name: groupName,
+		Name: session.GroupName,
 	}
 
 	return &session, nil
 }
 
 // GetStudyActivities returns all study activities
-func (r *SQLiteRepository) GetStudyActivities() ([]models.StudyActivity, error) {
+func (r *SQLiteRepository) GetStudyActivities(ctx context.Context) (_ []models.StudyActivity, err error) {
+	ctx, op := instrument(ctx, "GetStudyActivities")
+	defer func() { op.end(err) }()
+
 	query := `
 		SELECT 
 			sa.id, 
@@ -65,7 +76,7 @@ func (r *SQLiteRepository) GetStudyActivities() ([]models.StudyActivity, error)
 		GROUP BY sa.id
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("error querying study activities: %v", err)
 	}
@@ -74,32 +85,30 @@ func (r *SQLiteRepository) GetStudyActivities() ([]models.StudyActivity, error)
 	var activities []models.StudyActivity
 	for rows.Next() {
 		var activity models.StudyActivity
-		var createdAt string
 		err := rows.Scan(
 			&activity.ID,
 			&activity.GroupID,
 			&activity.ActivityCount,
 			&activity.ReviewCount,
 			&activity.CorrectCount,
-			&createdAt,
+			&activity.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning study activity: %v", err)
 		}
 
-		activity.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing created_at: %v", err)
-		}
-
 		activities = append(activities, activity)
 	}
 
+	op.rows(int64(len(activities)))
 	return activities, nil
 }
 
 // GetStudyActivity returns a specific study activity
-func (r *SQLiteRepository) GetStudyActivity(id int64) (*models.StudyActivity, error) {
+func (r *SQLiteRepository) GetStudyActivity(ctx context.Context, id int64) (_ *models.StudyActivity, err error) {
+	ctx, op := instrument(ctx, "GetStudyActivity")
+	defer func() { op.end(err) }()
+
 	query := `
 		SELECT 
 			sa.id, 
@@ -116,14 +125,13 @@ func (r *SQLiteRepository) GetStudyActivity(id int64) (*models.StudyActivity, er
 	`
 
 	var activity models.StudyActivity
-	var createdAt string
-	err := r.db.QueryRow(query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&activity.ID,
 		&activity.GroupID,
 		&activity.ActivityCount,
 		&activity.ReviewCount,
 		&activity.CorrectCount,
-		&createdAt,
+		&activity.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -132,190 +140,351 @@ func (r *SQLiteRepository) GetStudyActivity(id int64) (*models.StudyActivity, er
 		return nil, fmt.Errorf("error querying study activity: %v", err)
 	}
 
-	activity.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing created_at: %v", err)
-	}
-
 	return &activity, nil
 }
 
 // CreateStudyActivity creates a new study activity
-func (r *SQLiteRepository) CreateStudyActivity(activity *models.StudyActivity) error {
+func (r *SQLiteRepository) CreateStudyActivity(ctx context.Context, activity *models.StudyActivity) (err error) {
+	ctx, op := instrument(ctx, "CreateStudyActivity")
+	defer func() { op.end(err) }()
+
 	query := `
 		INSERT INTO study_activities (group_id)
 		VALUES (?)
 		RETURNING id, created_at
 	`
 
-	var createdAt string
-	err := r.db.QueryRow(query, activity.GroupID).Scan(&activity.ID, &createdAt)
+	err = r.db.QueryRowContext(ctx, query, activity.GroupID).Scan(&activity.ID, &activity.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("error creating study activity: %v", err)
 	}
 
-	activity.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
+	return nil
+}
+
+// GetStudySessionsByActivityID returns all study sessions for an activity
+func (r *SQLiteRepository) GetStudySessionsByActivityID(ctx context.Context, activityID int64) (_ []models.StudySession, err error) {
+	ctx, op := instrument(ctx, "GetStudySessionsByActivityID")
+	defer func() { op.end(err) }()
+
+	sessions, err := r.studySessions(ctx, r.db, "ss.study_activity_id = ?", activityID)
 	if err != nil {
-		return fmt.Errorf("error parsing created_at: %v", err)
+		return nil, err
 	}
 
-	return nil
+	op.rows(int64(len(sessions)))
+	return sessions, nil
 }
 
-// GetStudySessionsByActivityID returns all study sessions for an activity
-func (r *SQLiteRepository) GetStudySessionsByActivityID(activityID int64) ([]models.StudySession, error) {
-	query := `
-		SELECT id, study_activity_id, group_id, created_at
-		FROM study_sessions
-		WHERE study_activity_id = ?
-	`
+// GetStudySessionsByGroupID returns all study sessions for a group, newest first
+func (r *SQLiteRepository) GetStudySessionsByGroupID(ctx context.Context, groupID int64) (_ []models.StudySession, err error) {
+	ctx, op := instrument(ctx, "GetStudySessionsByGroupID")
+	defer func() { op.end(err) }()
+
+	sessions, err := r.studySessions(ctx, r.db, "ss.group_id = ?", groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(sessions)))
+	return sessions, nil
+}
 
-	rows, err := r.db.Query(query, activityID)
+// studySessions returns the study sessions matching where with their
+// review tallies, newest first. Quizzes started without an activity have
+// a study_activity_id of 0.
+func (r *SQLiteRepository) studySessions(ctx context.Context, q interface {
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+}, where string, args ...interface{}) ([]models.StudySession, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			ss.id, COALESCE(ss.study_activity_id, 0), ss.group_id, ss.created_at, ss.finished_at,
+			COUNT(wri.id),
+			COALESCE(SUM(CASE WHEN wri.is_correct THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN NOT wri.is_correct THEN 1 ELSE 0 END), 0)
+		FROM study_sessions ss
+		LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
+		WHERE `+where+`
+		GROUP BY ss.id
+		ORDER BY ss.created_at DESC, ss.id DESC
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying study sessions: %v", err)
 	}
 	defer rows.Close()
 
-	var sessions []models.StudySession
+	sessions := []models.StudySession{}
 	for rows.Next() {
 		var session models.StudySession
-		var createdAt string
 		err := rows.Scan(
 			&session.ID,
 			&session.StudyActivityID,
 			&session.GroupID,
-			&createdAt,
+			&session.CreatedAt,
+			&session.FinishedAt,
+			&session.WordsReviewed,
+			&session.CorrectCount,
+			&session.WrongCount,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning study session: %v", err)
 		}
 
-		session.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing created_at: %v", err)
-		}
-
 		sessions = append(sessions, session)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating study sessions: %v", err)
+	}
+
 	return sessions, nil
 }
 
-// CreateStudySession creates a new study session
-func (r *SQLiteRepository) CreateStudySession(session *models.StudySession) error {
+// GetStudySession returns a study session with its tallies, or nil if it
+// does not exist
+func (r *SQLiteRepository) GetStudySession(ctx context.Context, id int64) (_ *models.StudySession, err error) {
+	ctx, op := instrument(ctx, "GetStudySession")
+	defer func() { op.end(err) }()
+
+	sessions, err := r.studySessions(ctx, r.db, "ss.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+	return &sessions[0], nil
+}
+
+// FinishStudySession marks one of the learner's study sessions as
+// finished, raises a session.finished event and checks the session for
+// achievements. Finishing a session twice is a conflict.
+func (r *SQLiteRepository) FinishStudySession(ctx context.Context, id int64) (_ *models.StudySession, err error) {
+	ctx, op := instrument(ctx, "FinishStudySession")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSessionLearner(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE study_sessions SET finished_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND finished_at IS NULL
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error finishing study session: %v", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error finishing study session: %v", err)
+	}
+
+	sessions, err := r.studySessions(ctx, tx, "ss.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("%w: study session %d", ErrNotFound, id)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%w: study session %d already finished", ErrConflict, id)
+	}
+	session := &sessions[0]
+
+	var queue eventQueue
+	if err := onSessionFinished(ctx, tx, &queue, session, learnerFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	queue.publish()
+
+	op.rows(n)
+	return session, nil
+}
+
+// CreateStudySession creates a new study session for the learner and
+// awards its XP
+func (r *SQLiteRepository) CreateStudySession(ctx context.Context, session *models.StudySession) (err error) {
+	ctx, op := instrument(ctx, "CreateStudySession")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO study_sessions (study_activity_id, group_id)
-		VALUES (?, ?)
+		INSERT INTO study_sessions (study_activity_id, group_id, learner_id)
+		VALUES (?, ?, ?)
 		RETURNING id, created_at
 	`
 
-	var createdAt string
-	err := r.db.QueryRow(query, session.StudyActivityID, session.GroupID).Scan(&session.ID, &createdAt)
+	err = tx.QueryRowContext(ctx, query, session.StudyActivityID, session.GroupID, learnerFromContext(ctx)).Scan(&session.ID, &session.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("error creating study session: %v", err)
 	}
 
-	session.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
-	if err != nil {
-		return fmt.Errorf("error parsing created_at: %v", err)
+	var queue eventQueue
+	if err := onSessionStarted(ctx, tx, &queue, session); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing study session: %v", err)
 	}
+	queue.publish()
+	metrics.SessionsStarted.Inc()
 
 	return nil
 }
 
-// GetStudyProgress returns study progress for the last n days
-func (r *SQLiteRepository) GetStudyProgress(days int) ([]models.StudyActivity, error) {
-	query := `
-		SELECT 
-			sa.id, 
-			sa.group_id,
-			COUNT(DISTINCT ss.id) as activity_count,
-			COUNT(wri.id) as review_count,
-			COUNT(CASE WHEN wri.is_correct THEN 1 END) as correct_count,
-			sa.created_at
-		FROM study_activities sa
-		LEFT JOIN study_sessions ss ON ss.study_activity_id = sa.id
-		LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
-		WHERE sa.created_at >= datetime('now', ?)
-		GROUP BY sa.id
-		ORDER BY sa.created_at DESC
-	`
+// GetWordProgress returns the attempts, correct answers and current streak
+// of correct answers of the learner in ctx for every word in the vocabulary
+func (r *SQLiteRepository) GetWordProgress(ctx context.Context) (_ []models.WordProgress, err error) {
+	ctx, op := instrument(ctx, "GetWordProgress")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH ranked AS (
+			SELECT
+				word_id,
+				is_correct,
+				ROW_NUMBER() OVER (PARTITION BY word_id ORDER BY created_at DESC, id DESC) AS position
+			FROM word_review_items
+			WHERE learner_id = ?
+		),
+		per_word AS (
+			SELECT
+				word_id,
+				COUNT(*) AS attempts,
+				SUM(CASE WHEN is_correct THEN 1 ELSE 0 END) AS correct_count,
+				COALESCE(MIN(CASE WHEN NOT is_correct THEN position END) - 1, COUNT(*)) AS current_streak
+			FROM ranked
+			GROUP BY word_id
+		)
+		SELECT w.id, COALESCE(p.attempts, 0), COALESCE(p.correct_count, 0), COALESCE(p.current_streak, 0)
+		FROM words w
+		LEFT JOIN per_word p ON p.word_id = w.id
+		WHERE w.deleted_at IS NULL
+	`, learnerFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error querying word progress: %v", err)
+	}
+	defer rows.Close()
+
+	var progress []models.WordProgress
+	for rows.Next() {
+		var word models.WordProgress
+		if err := rows.Scan(&word.WordID, &word.Attempts, &word.CorrectCount, &word.CurrentStreak); err != nil {
+			return nil, fmt.Errorf("error scanning word progress: %v", err)
+		}
+		progress = append(progress, word)
+	}
 
-	rows, err := r.db.Query(query, fmt.Sprintf("-%d days", days))
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word progress: %v", err)
+	}
+
+	op.rows(int64(len(progress)))
+	return progress, nil
+}
+
+// GetGroupMemberships returns every membership between a live word and a live group
+func (r *SQLiteRepository) GetGroupMemberships(ctx context.Context) (_ []models.WordGroup, err error) {
+	ctx, op := instrument(ctx, "GetGroupMemberships")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT wg.id, wg.word_id, wg.group_id, wg.created_at
+		FROM words_groups wg
+		JOIN words w ON w.id = wg.word_id
+		JOIN groups g ON g.id = wg.group_id
+		WHERE w.deleted_at IS NULL AND g.deleted_at IS NULL
+	`)
 	if err != nil {
-		return nil, fmt.Errorf("error querying study progress: %v", err)
+		return nil, fmt.Errorf("error querying group memberships: %v", err)
 	}
 	defer rows.Close()
 
-	var activities []models.StudyActivity
+	var memberships []models.WordGroup
 	for rows.Next() {
-		var activity models.StudyActivity
-		var createdAt string
+		var membership models.WordGroup
 		err := rows.Scan(
-			&activity.ID,
-			&activity.GroupID,
-			&activity.ActivityCount,
-			&activity.ReviewCount,
-			&activity.CorrectCount,
-			&createdAt,
+			&membership.ID,
+			&membership.WordID,
+			&membership.GroupID,
+			&membership.CreatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("error scanning study activity: %v", err)
-		}
-
-		activity.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing created_at: %v", err)
+			return nil, fmt.Errorf("error scanning group membership: %v", err)
 		}
+		memberships = append(memberships, membership)
+	}
 
-		activities = append(activities, activity)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group memberships: %v", err)
 	}
 
-	return activities, nil
+	op.rows(int64(len(memberships)))
+	return memberships, nil
 }
 
-// GetQuickStats returns quick statistics for the dashboard
-func (r *SQLiteRepository) GetQuickStats() (*models.DashboardStats, error) {
+// GetQuickStats returns quick statistics for the dashboard. Word and group
+// totals cover the shared vocabulary; sessions, reviews and the streak are
+// those of the learner in ctx.
+func (r *SQLiteRepository) GetQuickStats(ctx context.Context) (_ *models.DashboardStats, err error) {
+	ctx, op := instrument(ctx, "GetQuickStats")
+	defer func() { op.end(err) }()
+
+	// Each figure is counted on its own table; joining them would multiply rows.
+	// The streak counts consecutive days with a review, ending today or
+	// yesterday: days in one run share the same day number minus row number.
 	query := `
-		WITH stats AS (
-			SELECT 
-				COUNT(DISTINCT w.id) as total_words,
-				COUNT(DISTINCT g.id) as total_groups,
-				COUNT(DISTINCT ss.id) as total_sessions,
-				COUNT(wri.id) as review_count,
-				COUNT(CASE WHEN wri.is_correct THEN 1 END) as correct_count,
-				MAX(ss.created_at) as last_session_date
-			FROM words w
-			CROSS JOIN groups g
-			LEFT JOIN study_sessions ss ON ss.group_id = g.id
-			LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
+		WITH review_days AS (
+			SELECT DISTINCT DATE(created_at) AS day FROM word_review_items WHERE learner_id = :learner
+		),
+		runs AS (
+			SELECT day, JULIANDAY(day) - ROW_NUMBER() OVER (ORDER BY day) AS run
+			FROM review_days
 		)
-		SELECT 
-			total_words,
-			total_groups,
-			total_sessions,
-			review_count,
-			correct_count,
-			CAST(CASE 
-				WHEN review_count > 0 
-				THEN ROUND(CAST(correct_count AS FLOAT) / review_count * 100, 2)
-				ELSE 0 
-			END AS FLOAT) as accuracy_rate,
-			last_session_date
-		FROM stats
+		SELECT
+			(SELECT COUNT(*) FROM words WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM groups WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM study_sessions WHERE learner_id = :learner),
+			(SELECT COUNT(*) FROM word_review_items WHERE learner_id = :learner),
+			(SELECT COUNT(*) FROM word_review_items WHERE learner_id = :learner AND is_correct),
+			(SELECT MAX(created_at) FROM study_sessions WHERE learner_id = :learner),
+			(SELECT COUNT(DISTINCT ss.group_id)
+				FROM study_sessions ss
+				JOIN groups g ON g.id = ss.group_id
+				WHERE ss.learner_id = :learner AND g.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM runs
+				WHERE run = (SELECT run FROM runs ORDER BY day DESC LIMIT 1)
+					AND (SELECT MAX(day) FROM review_days) >= DATE('now', '-1 day'))
 	`
 
 	stats := &models.DashboardStats{}
 	var lastSessionDate sql.NullString
 
-	err := r.db.QueryRow(query).Scan(
+	err = r.db.QueryRowContext(ctx, query, sql.Named("learner", learnerFromContext(ctx))).Scan(
 		&stats.TotalWords,
 		&stats.TotalGroups,
 		&stats.TotalSessions,
 		&stats.ReviewCount,
 		&stats.CorrectCount,
-		&stats.AccuracyRate,
 		&lastSessionDate,
+		&stats.TotalActiveGroups,
+		&stats.StudyStreak,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error querying quick stats: %v", err)
@@ -324,6 +493,12 @@ func (r *SQLiteRepository) GetQuickStats() (*models.DashboardStats, error) {
 	if lastSessionDate.Valid {
 		stats.LastSessionDate = lastSessionDate.String
 	}
+	if stats.ReviewCount > 0 {
+		rate := float64(stats.CorrectCount) / float64(stats.ReviewCount) * 100
+		stats.AccuracyRate = math.Round(rate*100) / 100
+		stats.SuccessRate = int(math.Round(rate))
+	}
+	stats.TotalStudySessions = stats.TotalSessions
 
 	return stats, nil
 }
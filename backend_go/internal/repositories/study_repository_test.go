@@ -0,0 +1,280 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/stats"
+)
+
+// addMemberships puts each listed word in its group
+func addMemberships(t *testing.T, r *SQLiteRepository, groups map[int64][]int64) {
+	t.Helper()
+	ctx := asLearner("admin")
+	for groupID, wordIDs := range groups {
+		for _, wordID := range wordIDs {
+			if err := r.AddWordToGroup(ctx, groupID, wordID); err != nil {
+				t.Fatalf("AddWordToGroup(%d, %d): %v", groupID, wordID, err)
+			}
+		}
+	}
+}
+
+func TestGetQuickStatsCountsEachRowOnce(t *testing.T) {
+	r := newTestRepository(t)
+
+	// The seed data holds five words and three groups. Every word sits in several groups, so joining words with groups or
+	// memberships would multiply the counts below
+	addMemberships(t, r, map[int64][]int64{1: {1, 2, 3, 4, 5}, 2: {1, 2, 3, 4, 5}, 3: {1}})
+
+	alice := asLearner("alice")
+	first := createSession(t, r, alice, 1)
+	review(t, r, alice, first.ID, 1, true)
+	review(t, r, alice, first.ID, 2, true)
+	review(t, r, alice, first.ID, 3, false)
+	second := createSession(t, r, alice, 2)
+	review(t, r, alice, second.ID, 1, true)
+
+	bob := asLearner("bob")
+	other := createSession(t, r, bob, 3)
+	review(t, r, bob, other.ID, 1, false)
+
+	got, err := r.GetQuickStats(alice)
+	if err != nil {
+		t.Fatalf("GetQuickStats: %v", err)
+	}
+	want := models.DashboardStats{
+		TotalWords:         5,
+		TotalGroups:        3,
+		TotalSessions:      2,
+		ReviewCount:        4,
+		CorrectCount:       3,
+		AccuracyRate:       75,
+		SuccessRate:        75,
+		TotalStudySessions: 2,
+		TotalActiveGroups:  2,
+		StudyStreak:        1,
+		LastSessionDate:    got.LastSessionDate,
+	}
+	if *got != want {
+		t.Errorf("alice's quick stats = %+v, want %+v", *got, want)
+	}
+	if got.LastSessionDate == "" {
+		t.Error("alice's last session date is empty")
+	}
+
+	got, err = r.GetQuickStats(bob)
+	if err != nil {
+		t.Fatalf("GetQuickStats: %v", err)
+	}
+	if got.TotalSessions != 1 || got.ReviewCount != 1 || got.CorrectCount != 0 || got.TotalActiveGroups != 1 {
+		t.Errorf("bob's quick stats = %+v, want only bob's session and review", *got)
+	}
+
+	got, err = r.GetQuickStats(asLearner("carol"))
+	if err != nil {
+		t.Fatalf("GetQuickStats: %v", err)
+	}
+	if got.TotalWords != 5 || got.TotalSessions != 0 || got.ReviewCount != 0 || got.StudyStreak != 0 || got.LastSessionDate != "" {
+		t.Errorf("carol's quick stats = %+v, want shared totals only", *got)
+	}
+}
+
+func TestGetQuickStatsStudyStreak(t *testing.T) {
+	r := newTestRepository(t)
+	alice := asLearner("alice")
+	session := createSession(t, r, alice, 1)
+
+	// Two reviews today, then yesterday, two days ago and, after a gap,
+	// four days ago
+	for _, offset := range []string{"-0 days", "-0 days", "-1 days", "-2 days", "-4 days"} {
+		item := review(t, r, alice, session.ID, 1, true)
+		mustExec(t, r, "UPDATE word_review_items SET created_at = datetime('now', ?) WHERE id = ?", offset, item.ID)
+	}
+
+	streak := func() int {
+		t.Helper()
+		got, err := r.GetQuickStats(alice)
+		if err != nil {
+			t.Fatalf("GetQuickStats: %v", err)
+		}
+		return got.StudyStreak
+	}
+
+	if got := streak(); got != 3 {
+		t.Errorf("streak ending today = %d, want 3", got)
+	}
+
+	mustExec(t, r, "UPDATE word_review_items SET created_at = datetime(created_at, '-1 day')")
+	if got := streak(); got != 3 {
+		t.Errorf("streak ending yesterday = %d, want 3", got)
+	}
+
+	mustExec(t, r, "UPDATE word_review_items SET created_at = datetime(created_at, '-1 day')")
+	if got := streak(); got != 0 {
+		t.Errorf("streak ending two days ago = %d, want 0", got)
+	}
+
+	// Another learner studying today does not keep alice's streak alive
+	bob := asLearner("bob")
+	review(t, r, bob, createSession(t, r, bob, 1).ID, 1, true)
+	if got := streak(); got != 0 {
+		t.Errorf("streak with only bob studying today = %d, want 0", got)
+	}
+}
+
+func TestGetLastStudySession(t *testing.T) {
+	r := newTestRepository(t)
+	var groupName string
+	if err := r.db.QueryRow("SELECT name FROM groups WHERE id = 2").Scan(&groupName); err != nil {
+		t.Fatal(err)
+	}
+
+	alice := asLearner("alice")
+	older := createSession(t, r, alice, 1)
+	review(t, r, alice, older.ID, 1, true)
+	mustExec(t, r, "UPDATE study_sessions SET created_at = datetime('now', '-1 hour') WHERE id = ?", older.ID)
+
+	latest := createSession(t, r, alice, 2)
+	review(t, r, alice, latest.ID, 1, true)
+	review(t, r, alice, latest.ID, 2, false)
+	review(t, r, alice, latest.ID, 3, false)
+
+	// A newer session of another learner is not alice's last session
+	bob := asLearner("bob")
+	createSession(t, r, bob, 3)
+
+	got, err := r.GetLastStudySession(alice)
+	if err != nil {
+		t.Fatalf("GetLastStudySession: %v", err)
+	}
+	if got == nil {
+		t.Fatal("GetLastStudySession returned no session")
+	}
+	if got.ID != latest.ID || got.GroupID != 2 || got.GroupName != groupName {
+		t.Errorf("last session = %d in group %d %q, want %d in group 2 %q", got.ID, got.GroupID, got.GroupName, latest.ID, groupName)
+	}
+	if got.WordsReviewed != 3 || got.CorrectCount != 1 || got.WrongCount != 2 {
+		t.Errorf("last session tallies = %d reviewed, %d correct, %d wrong; want 3, 1, 2", got.WordsReviewed, got.CorrectCount, got.WrongCount)
+	}
+
+	got, err = r.GetLastStudySession(bob)
+	if err != nil {
+		t.Fatalf("GetLastStudySession: %v", err)
+	}
+	if got == nil || got.WordsReviewed != 0 || got.CorrectCount != 0 || got.WrongCount != 0 {
+		t.Errorf("bob's last session = %+v, want an empty session", got)
+	}
+
+	got, err = r.GetLastStudySession(asLearner("carol"))
+	if err != nil {
+		t.Fatalf("GetLastStudySession: %v", err)
+	}
+	if got != nil {
+		t.Errorf("carol's last session = %+v, want none", got)
+	}
+}
+
+func TestStudyProgress(t *testing.T) {
+	r := newTestRepository(t)
+	addMemberships(t, r, map[int64][]int64{1: {1, 2, 3, 4, 5}, 2: {2, 3}, 3: {1}})
+
+	alice := asLearner("alice")
+	session := createSession(t, r, alice, 1)
+	for i := 0; i < 5; i++ {
+		review(t, r, alice, session.ID, 1, true)
+	}
+	review(t, r, alice, session.ID, 2, true)
+	review(t, r, alice, session.ID, 2, false)
+
+	// Bob masters a word alice has not studied
+	bob := asLearner("bob")
+	other := createSession(t, r, bob, 1)
+	for i := 0; i < 5; i++ {
+		review(t, r, bob, other.ID, 3, true)
+	}
+
+	words, err := r.GetWordProgress(alice)
+	if err != nil {
+		t.Fatalf("GetWordProgress: %v", err)
+	}
+	groups, err := r.GetGroups(alice)
+	if err != nil {
+		t.Fatalf("GetGroups: %v", err)
+	}
+	memberships, err := r.GetGroupMemberships(alice)
+	if err != nil {
+		t.Fatalf("GetGroupMemberships: %v", err)
+	}
+	progress := stats.Progress(words, groups, memberships)
+
+	if progress.TotalAvailableWords != 5 || progress.TotalWordsStudied != 2 || progress.MasteredWords != 1 || progress.MasteryPercentage != 20 {
+		t.Errorf("progress = %+v, want 5 available, 2 studied, 1 mastered, 20%%", progress)
+	}
+
+	want := map[int64]models.GroupProgress{
+		1: {TotalAvailableWords: 5, TotalWordsStudied: 2, MasteredWords: 1, MasteryPercentage: 20},
+		2: {TotalAvailableWords: 2, TotalWordsStudied: 1, MasteredWords: 0, MasteryPercentage: 0},
+		3: {TotalAvailableWords: 1, TotalWordsStudied: 1, MasteredWords: 1, MasteryPercentage: 100},
+	}
+	if len(progress.Groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(progress.Groups), len(want))
+	}
+	for _, got := range progress.Groups {
+		w := want[got.GroupID]
+		w.GroupID, w.GroupName = got.GroupID, got.GroupName
+		if got != w {
+			t.Errorf("group %d progress = %+v, want %+v", got.GroupID, got, w)
+		}
+	}
+
+	// The dashboard spec names the totals total_available_words and
+	// total_words_studied, at the top level and per group
+	body, err := json.Marshal(progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shape struct {
+		TotalAvailableWords *int `json:"total_available_words"`
+		TotalWordsStudied   *int `json:"total_words_studied"`
+		Groups              []struct {
+			GroupID             *int64 `json:"group_id"`
+			TotalAvailableWords *int   `json:"total_available_words"`
+			TotalWordsStudied   *int   `json:"total_words_studied"`
+		} `json:"groups"`
+	}
+	if err := json.Unmarshal(body, &shape); err != nil {
+		t.Fatal(err)
+	}
+	if shape.TotalAvailableWords == nil || *shape.TotalAvailableWords != 5 || shape.TotalWordsStudied == nil || *shape.TotalWordsStudied != 2 {
+		t.Errorf("progress JSON = %s, want total_available_words 5 and total_words_studied 2", body)
+	}
+	for _, group := range shape.Groups {
+		if group.GroupID == nil || group.TotalAvailableWords == nil || group.TotalWordsStudied == nil {
+			t.Errorf("group progress JSON is missing fields: %s", body)
+		}
+	}
+}
+
+func TestFinishStudySessionChecksTheLearner(t *testing.T) {
+	r := newTestRepository(t)
+	alice := asLearner("alice")
+	session := createSession(t, r, alice, 1)
+
+	if _, err := r.FinishStudySession(asLearner("mallory"), session.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("FinishStudySession as another learner: err = %v, want ErrNotFound", err)
+	}
+	if _, err := r.FinishStudySession(alice, session.ID+100); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FinishStudySession of an unknown session: err = %v, want ErrNotFound", err)
+	}
+
+	finished, err := r.FinishStudySession(alice, session.ID)
+	if err != nil || finished.FinishedAt == nil {
+		t.Fatalf("FinishStudySession = %+v, %v; want it finished", finished, err)
+	}
+	if _, err := r.FinishStudySession(alice, session.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("finishing twice: err = %v, want ErrConflict", err)
+	}
+}
@@ -0,0 +1,441 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/metrics"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// syncTokenPrefix versions the change token format
+const syncTokenPrefix = "v1:"
+
+// Sync applies the sessions and reviews a client queued offline and returns
+// the content changed since the client's token. Content is read-only for
+// clients, so the server always wins; pushed reviews are merged with the
+// server's by client_id, so every review from every device is kept once.
+func (r *SQLiteRepository) Sync(ctx context.Context, req *models.SyncRequest) (_ *models.SyncResponse, err error) {
+	ctx, op := instrument(ctx, "Sync")
+	defer func() { op.end(err) }()
+
+	since, err := decodeSyncToken(req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var version int64
+	if err := tx.QueryRowContext(ctx, "SELECT version FROM sync_state WHERE id = 1").Scan(&version); err != nil {
+		return nil, fmt.Errorf("error querying sync version: %v", err)
+	}
+
+	resp := &models.SyncResponse{
+		Token:    encodeSyncToken(version),
+		Sessions: []models.SyncItemResult{},
+		Reviews:  []models.SyncItemResult{},
+		Deleted:  []models.SyncTombstone{},
+	}
+	if since > version {
+		// The token comes from another database, e.g. before a reset
+		since = 0
+		resp.Reset = true
+	}
+
+	now := time.Now().UTC()
+	var queue eventQueue
+	var sessionsCreated int
+	for _, session := range req.Sessions {
+		result, err := syncSession(ctx, tx, &queue, session, now)
+		if err != nil {
+			return nil, err
+		}
+		if result.Status == models.BatchItemCreated {
+			sessionsCreated++
+		}
+		resp.Sessions = append(resp.Sessions, result)
+	}
+
+	var reviewsCreated []bool
+	for i := range req.Reviews {
+		result, err := syncReview(ctx, tx, &queue, &req.Reviews[i], now)
+		if err != nil {
+			return nil, err
+		}
+		if result.Status == models.BatchItemCreated {
+			reviewsCreated = append(reviewsCreated, req.Reviews[i].IsCorrect)
+		}
+		resp.Reviews = append(resp.Reviews, result)
+	}
+
+	if resp.Words, err = syncWords(ctx, tx, since); err != nil {
+		return nil, err
+	}
+	if resp.Groups, err = syncGroups(ctx, tx, since); err != nil {
+		return nil, err
+	}
+	if resp.Memberships, err = syncMemberships(ctx, tx, since); err != nil {
+		return nil, err
+	}
+	if since > 0 {
+		if resp.Deleted, err = syncTombstones(ctx, tx, since); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing sync: %v", err)
+	}
+	queue.publish()
+
+	metrics.SessionsStarted.Add(float64(sessionsCreated))
+	for _, correct := range reviewsCreated {
+		metrics.RecordReview(correct)
+	}
+
+	op.rows(int64(len(resp.Words) + len(resp.Groups) + len(resp.Memberships) + len(resp.Deleted)))
+	return resp, nil
+}
+
+// syncSession stores a session started offline unless its client_id is known.
+// A client_id already used by another learner is rejected.
+func syncSession(ctx context.Context, tx *sql.Tx, queue *eventQueue, session models.SyncSession, now time.Time) (models.SyncItemResult, error) {
+	result := models.SyncItemResult{ClientID: session.ClientID}
+
+	var learner string
+	err := tx.QueryRowContext(ctx, "SELECT id, learner_id FROM study_sessions WHERE client_id = ?", session.ClientID).Scan(&result.ID, &learner)
+	if err == nil {
+		if learner != learnerFromContext(ctx) {
+			return models.SyncItemResult{ClientID: session.ClientID, Status: models.BatchItemRejected, Error: "client_id already used"}, nil
+		}
+		result.Status = models.BatchItemDuplicate
+		return result, nil
+	}
+	if err != sql.ErrNoRows {
+		return result, fmt.Errorf("error querying study session: %v", err)
+	}
+
+	var valid bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM study_activities WHERE id = ?)
+			AND EXISTS(SELECT 1 FROM groups WHERE id = ? AND deleted_at IS NULL)
+	`, session.StudyActivityID, session.GroupID).Scan(&valid)
+	if err != nil {
+		return result, fmt.Errorf("error validating study session: %v", err)
+	}
+	if !valid {
+		result.Status = models.BatchItemRejected
+		result.Error = "study activity or group not found"
+		return result, nil
+	}
+
+	created := models.StudySession{
+		StudyActivityID: session.StudyActivityID,
+		GroupID:         session.GroupID,
+		CreatedAt:       clientTime(session.StartedAt, now),
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO study_sessions (client_id, study_activity_id, group_id, learner_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`, session.ClientID, created.StudyActivityID, created.GroupID, learnerFromContext(ctx),
+		clientTimestamp(session.StartedAt, now)).Scan(&created.ID)
+	if err != nil {
+		return result, fmt.Errorf("error creating study session: %v", err)
+	}
+	result.ID = created.ID
+
+	if err := onSessionStarted(ctx, tx, queue, &created); err != nil {
+		return result, err
+	}
+
+	result.Status = models.BatchItemCreated
+	return result, nil
+}
+
+// syncReview stores a review recorded offline in one of the learner's
+// sessions unless its client_id is known
+func syncReview(ctx context.Context, tx *sql.Tx, queue *eventQueue, review *models.SyncReview, now time.Time) (models.SyncItemResult, error) {
+	if review.ClientID == nil || *review.ClientID == "" {
+		return models.SyncItemResult{Status: models.BatchItemRejected, Error: "client_id is required"}, nil
+	}
+	result := models.SyncItemResult{ClientID: *review.ClientID}
+
+	if review.StudySessionID == 0 && review.SessionClientID != "" {
+		err := tx.QueryRowContext(ctx, "SELECT id FROM study_sessions WHERE client_id = ?", review.SessionClientID).Scan(&review.StudySessionID)
+		if err != nil && err != sql.ErrNoRows {
+			return result, fmt.Errorf("error querying study session: %v", err)
+		}
+	}
+	err := checkSessionLearner(ctx, tx, review.StudySessionID)
+	if errors.Is(err, ErrNotFound) {
+		result.Status = models.BatchItemRejected
+		result.Error = "study session not found"
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// Reviews of words trashed while the client was offline are still kept
+	var known bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM words WHERE id = ?)", review.WordID).Scan(&known); err != nil {
+		return result, fmt.Errorf("error querying word: %v", err)
+	}
+	if !known {
+		result.Status = models.BatchItemRejected
+		result.Error = fmt.Sprintf("word %d not found", review.WordID)
+		return result, nil
+	}
+
+	err = insertReview(ctx, tx, &review.WordReviewItem)
+	if err == sql.ErrNoRows {
+		// Only a review already synced into the same session is a duplicate
+		err := tx.QueryRowContext(ctx, "SELECT id FROM word_review_items WHERE client_id = ? AND study_session_id = ?",
+			*review.ClientID, review.StudySessionID).Scan(&result.ID)
+		if err == sql.ErrNoRows {
+			result.Status = models.BatchItemRejected
+			result.Error = "client_id already used"
+			return result, nil
+		}
+		if err != nil {
+			return result, fmt.Errorf("error querying duplicate review: %v", err)
+		}
+		result.Status = models.BatchItemDuplicate
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("error creating word review item: %v", err)
+	}
+
+	if review.ReviewedAt != nil {
+		review.CreatedAt = clientTime(review.ReviewedAt, now)
+		_, err := tx.ExecContext(ctx, "UPDATE word_review_items SET created_at = ? WHERE id = ?",
+			clientTimestamp(review.ReviewedAt, now), review.ID)
+		if err != nil {
+			return result, fmt.Errorf("error setting review time: %v", err)
+		}
+	}
+
+	if err := onReviewRecorded(ctx, tx, queue, &review.WordReviewItem); err != nil {
+		return result, err
+	}
+
+	result.Status = models.BatchItemCreated
+	result.ID = review.ID
+	return result, nil
+}
+
+// syncWords returns the words changed after version since, trashed ones included
+func syncWords(ctx context.Context, tx *sql.Tx, since int64) ([]models.Word, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, arabic, romaji, english, COALESCE(parts, ''), created_at, deleted_at, updated_at
+		FROM words
+		WHERE change_version > ?
+		ORDER BY change_version
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("error querying changed words: %v", err)
+	}
+	defer rows.Close()
+
+	words := []models.Word{}
+	for rows.Next() {
+		var word models.Word
+		var partsStr string
+		err := rows.Scan(
+			&word.ID,
+			&word.Arabic,
+			&word.Romaji,
+			&word.English,
+			&partsStr,
+			&word.CreatedAt,
+			&word.DeletedAt,
+			&word.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning changed word: %v", err)
+		}
+
+		if partsStr != "" {
+			word.Parts = json.RawMessage(partsStr)
+		}
+		words = append(words, word)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating changed words: %v", err)
+	}
+
+	return words, nil
+}
+
+// syncGroups returns the groups changed after version since, trashed ones included
+func syncGroups(ctx context.Context, tx *sql.Tx, since int64) ([]models.Group, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, name, COALESCE(description, ''), created_at, deleted_at, updated_at
+		FROM groups
+		WHERE change_version > ?
+		ORDER BY change_version
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("error querying changed groups: %v", err)
+	}
+	defer rows.Close()
+
+	groups := []models.Group{}
+	for rows.Next() {
+		var group models.Group
+		err := rows.Scan(
+			&group.ID,
+			&group.Name,
+			&group.Description,
+			&group.CreatedAt,
+			&group.DeletedAt,
+			&group.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning changed group: %v", err)
+		}
+
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating changed groups: %v", err)
+	}
+
+	return groups, nil
+}
+
+// syncMemberships returns the word-group memberships added after version since
+func syncMemberships(ctx context.Context, tx *sql.Tx, since int64) ([]models.WordGroup, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, word_id, group_id, created_at, updated_at
+		FROM words_groups
+		WHERE change_version > ?
+		ORDER BY change_version
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("error querying changed memberships: %v", err)
+	}
+	defer rows.Close()
+
+	memberships := []models.WordGroup{}
+	for rows.Next() {
+		var membership models.WordGroup
+		err := rows.Scan(
+			&membership.ID,
+			&membership.WordID,
+			&membership.GroupID,
+			&membership.CreatedAt,
+			&membership.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning changed membership: %v", err)
+		}
+
+		memberships = append(memberships, membership)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating changed memberships: %v", err)
+	}
+
+	return memberships, nil
+}
+
+// syncTombstones returns rows deleted after version since. Rows that were
+// recreated afterwards, such as a purged word brought back by a revert, are
+// left out because they are returned as changes instead.
+func syncTombstones(ctx context.Context, tx *sql.Tx, since int64) ([]models.SyncTombstone, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT t.entity_type, t.entity_id, MAX(t.deleted_at)
+		FROM sync_tombstones t
+		WHERE t.change_version > ?
+			AND NOT (
+				(t.entity_type = ? AND EXISTS (SELECT 1 FROM words WHERE id = t.entity_id))
+				OR (t.entity_type = ? AND EXISTS (SELECT 1 FROM groups WHERE id = t.entity_id))
+				OR (t.entity_type = ? AND EXISTS (SELECT 1 FROM words_groups WHERE id = t.entity_id))
+			)
+		GROUP BY t.entity_type, t.entity_id
+		ORDER BY MAX(t.change_version)
+	`, since, models.EntityWord, models.EntityGroup, models.EntityWordGroup)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tombstones: %v", err)
+	}
+	defer rows.Close()
+
+	tombstones := []models.SyncTombstone{}
+	for rows.Next() {
+		var tombstone models.SyncTombstone
+		var deletedAt string
+		if err := rows.Scan(&tombstone.EntityType, &tombstone.EntityID, &deletedAt); err != nil {
+			return nil, fmt.Errorf("error scanning tombstone: %v", err)
+		}
+
+		// MAX() loses the column type, so the timestamp arrives as text
+		tombstone.DeletedAt, err = time.Parse("2006-01-02 15:04:05", deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing tombstone time: %v", err)
+		}
+		tombstones = append(tombstones, tombstone)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tombstones: %v", err)
+	}
+
+	return tombstones, nil
+}
+
+// clientTimestamp formats a client-supplied time for storage, falling back
+// to now when it is missing and clamping clocks that run ahead of the server
+func clientTimestamp(t *time.Time, now time.Time) string {
+	return clientTime(t, now).Format("2006-01-02 15:04:05")
+}
+
+// clientTime returns a client-supplied time in UTC, falling back to now
+// when it is missing or in the future
+func clientTime(t *time.Time, now time.Time) time.Time {
+	if t == nil || t.After(now) {
+		return now
+	}
+	return t.UTC()
+}
+
+// encodeSyncToken wraps a change version in an opaque token
+func encodeSyncToken(version int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(syncTokenPrefix + strconv.FormatInt(version, 10)))
+}
+
+// decodeSyncToken returns the change version in a token, or 0 for an empty token
+func decodeSyncToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(raw), syncTokenPrefix) {
+		return 0, fmt.Errorf("%w: malformed sync token", ErrInvalidInput)
+	}
+
+	version, err := strconv.ParseInt(strings.TrimPrefix(string(raw), syncTokenPrefix), 10, 64)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("%w: malformed sync token", ErrInvalidInput)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// syncReviewItem returns a queued review of word in the given session
+func syncReviewItem(clientID string, sessionID int64, sessionClientID string, wordID int64) models.SyncReview {
+	return models.SyncReview{
+		WordReviewItem:  models.WordReviewItem{ClientID: &clientID, StudySessionID: sessionID, WordID: wordID, IsCorrect: true},
+		SessionClientID: sessionClientID,
+	}
+}
+
+func TestSyncSessionsAndReviews(t *testing.T) {
+	r := newTestRepository(t)
+	alice, bob := asLearner("alice"), asLearner("bob")
+	activity := createSession(t, r, alice, 1).StudyActivityID
+	mustExec(t, r, "UPDATE groups SET deleted_at = CURRENT_TIMESTAMP WHERE id = 3")
+
+	req := &models.SyncRequest{
+		Sessions: []models.SyncSession{
+			{ClientID: "s-1", StudyActivityID: activity, GroupID: 1},
+			{ClientID: "s-trashed", StudyActivityID: activity, GroupID: 3},
+		},
+		Reviews: []models.SyncReview{
+			syncReviewItem("r-1", 0, "s-1", 1),
+			syncReviewItem("r-2", 0, "s-1", 99),
+		},
+	}
+	resp, err := r.Sync(alice, req)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := resp.Sessions[0]; got.Status != models.BatchItemCreated {
+		t.Errorf("session = %+v, want created", got)
+	}
+	if got := resp.Sessions[1]; got.Status != models.BatchItemRejected {
+		t.Errorf("session in trashed group = %+v, want rejected", got)
+	}
+	if got := resp.Reviews[0]; got.Status != models.BatchItemCreated {
+		t.Errorf("review = %+v, want created", got)
+	}
+	if got := resp.Reviews[1]; got.Status != models.BatchItemRejected {
+		t.Errorf("review of unknown word = %+v, want rejected", got)
+	}
+	sessionID, reviewID := resp.Sessions[0].ID, resp.Reviews[0].ID
+
+	// Replaying the same push is a no-op
+	again, err := r.Sync(alice, req)
+	if err != nil {
+		t.Fatalf("Sync replay: %v", err)
+	}
+	if got := again.Sessions[0]; got.Status != models.BatchItemDuplicate || got.ID != sessionID {
+		t.Errorf("replayed session = %+v, want duplicate of %d", got, sessionID)
+	}
+	if got := again.Reviews[0]; got.Status != models.BatchItemDuplicate || got.ID != reviewID {
+		t.Errorf("replayed review = %+v, want duplicate of %d", got, reviewID)
+	}
+
+	// Bob can neither review in alice's session nor see her rows through
+	// client ids he guesses
+	resp, err = r.Sync(bob, &models.SyncRequest{
+		Sessions: []models.SyncSession{{ClientID: "s-1", StudyActivityID: activity, GroupID: 1}},
+		Reviews: []models.SyncReview{
+			syncReviewItem("r-3", sessionID, "", 1),
+			syncReviewItem("r-4", 0, "s-1", 1),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Sync as bob: %v", err)
+	}
+	if got := resp.Sessions[0]; got.Status != models.BatchItemRejected || got.ID != 0 {
+		t.Errorf("bob reusing alice's session client_id = %+v, want rejected", got)
+	}
+	for i, got := range resp.Reviews {
+		if got.Status != models.BatchItemRejected || got.ID != 0 {
+			t.Errorf("bob's review %d in alice's session = %+v, want rejected", i, got)
+		}
+	}
+
+	own := createSession(t, r, bob, 1)
+	resp, err = r.Sync(bob, &models.SyncRequest{Reviews: []models.SyncReview{syncReviewItem("r-1", own.ID, "", 1)}})
+	if err != nil {
+		t.Fatalf("Sync as bob: %v", err)
+	}
+	if got := resp.Reviews[0]; got.Status != models.BatchItemRejected || got.ID != 0 {
+		t.Errorf("bob reusing alice's review client_id = %+v, want rejected", got)
+	}
+}
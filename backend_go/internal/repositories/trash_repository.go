@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// GetTrash returns all soft-deleted words and groups, most recent first
+func (r *SQLiteRepository) GetTrash(ctx context.Context) (_ *models.Trash, err error) {
+	ctx, op := instrument(ctx, "GetTrash")
+	defer func() { op.end(err) }()
+
+	trash := &models.Trash{Words: []models.Word{}, Groups: []models.Group{}}
+
+	wordRows, err := r.db.QueryContext(ctx, `
+		SELECT id, arabic, romaji, english, COALESCE(parts, ''), created_at, deleted_at
+		FROM words
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying trashed words: %v", err)
+	}
+	defer wordRows.Close()
+
+	for wordRows.Next() {
+		var word models.Word
+		var partsStr string
+		err := wordRows.Scan(
+			&word.ID,
+			&word.Arabic,
+			&word.Romaji,
+			&word.English,
+			&partsStr,
+			&word.CreatedAt,
+			&word.DeletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning trashed word: %v", err)
+		}
+
+		if partsStr != "" {
+			word.Parts = json.RawMessage(partsStr)
+		}
+		trash.Words = append(trash.Words, word)
+	}
+	if err = wordRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trashed words: %v", err)
+	}
+
+	groupRows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, COALESCE(description, ''), created_at, deleted_at
+		FROM groups
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying trashed groups: %v", err)
+	}
+	defer groupRows.Close()
+
+	for groupRows.Next() {
+		var group models.Group
+		err := groupRows.Scan(
+			&group.ID,
+			&group.Name,
+			&group.Description,
+			&group.CreatedAt,
+			&group.DeletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning trashed group: %v", err)
+		}
+		trash.Groups = append(trash.Groups, group)
+	}
+	if err = groupRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trashed groups: %v", err)
+	}
+
+	op.rows(int64(len(trash.Words) + len(trash.Groups)))
+	return trash, nil
+}
+
+// PurgeTrash permanently deletes words and groups trashed before cutoff,
+// along with their group memberships, and returns how many were removed
+func (r *SQLiteRepository) PurgeTrash(ctx context.Context, cutoff time.Time) (_ int, err error) {
+	ctx, op := instrument(ctx, "PurgeTrash")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	threshold := cutoff.UTC().Format("2006-01-02 15:04:05")
+
+	wordIDs, err := trashedIDs(ctx, tx, "words", threshold)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range wordIDs {
+		before, err := getWordTx(ctx, tx, id)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM words_groups WHERE word_id = ?", id); err != nil {
+			return 0, fmt.Errorf("error purging word memberships: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM words WHERE id = ?", id); err != nil {
+			return 0, fmt.Errorf("error purging word: %v", err)
+		}
+		if err := recordAudit(ctx, tx, models.EntityWord, id, models.AuditPurge, before, nil); err != nil {
+			return 0, err
+		}
+	}
+
+	groupIDs, err := trashedIDs(ctx, tx, "groups", threshold)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range groupIDs {
+		before, err := getGroupTx(ctx, tx, id)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM words_groups WHERE group_id = ?", id); err != nil {
+			return 0, fmt.Errorf("error purging group memberships: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM groups WHERE id = ?", id); err != nil {
+			return 0, fmt.Errorf("error purging group: %v", err)
+		}
+		if err := recordAudit(ctx, tx, models.EntityGroup, id, models.AuditPurge, before, nil); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing purge: %v", err)
+	}
+
+	purged := len(wordIDs) + len(groupIDs)
+	op.rows(int64(purged))
+	return purged, nil
+}
+
+// trashedIDs returns the IDs of rows in table trashed before threshold
+func trashedIDs(ctx context.Context, tx *sql.Tx, table, threshold string) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id
+		FROM %s
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+	`, table), threshold)
+	if err != nil {
+		return nil, fmt.Errorf("error querying trashed %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning trashed %s: %v", table, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,409 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/events"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/webhooks"
+)
+
+const webhookColumns = `id, url, secret, events, group_id, description, active, created_at`
+
+const deliveryColumns = `
+	id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_attempt_at, response_status, last_error, delivered_at, created_at`
+
+// enqueueWebhooks writes a delivery to the outbox for every active
+// webhook subscribed to the event
+func enqueueWebhooks(ctx context.Context, tx *sql.Tx, event events.Event) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM webhooks
+		WHERE active AND ',' || events || ',' LIKE '%,' || ? || ',%'
+			AND (group_id IS NULL OR group_id = ?)
+	`, event.Type, event.GroupID)
+	if err != nil {
+		return fmt.Errorf("error querying webhooks: %v", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning webhook: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating webhooks: %v", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	payload := models.WebhookPayload{
+		ID:        webhooks.NewEventID(),
+		Type:      event.Type,
+		CreatedAt: event.Time,
+		SessionID: event.SessionID,
+		GroupID:   event.GroupID,
+		LearnerID: event.LearnerID,
+		Data:      event.Data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %v", err)
+	}
+
+	for _, id := range ids {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, id, payload.ID, event.Type, string(body), event.Time.UTC().Format(scheduleTimeLayout))
+		if err != nil {
+			return fmt.Errorf("error queueing webhook delivery: %v", err)
+		}
+	}
+	return nil
+}
+
+// raiseGroupsMastered raises a group.mastered event for every group of a
+// newly mastered word in which the learner has now mastered every word
+func raiseGroupsMastered(ctx context.Context, tx *sql.Tx, queue *eventQueue, learner string, wordID int64) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT wg.group_id, COUNT(DISTINCT wg.word_id)
+		FROM words_groups wg
+		JOIN words w ON w.id = wg.word_id AND w.deleted_at IS NULL
+		JOIN groups g ON g.id = wg.group_id AND g.deleted_at IS NULL
+		LEFT JOIN learner_word_progress p ON p.word_id = wg.word_id AND p.learner_id = ?
+		WHERE wg.group_id IN (SELECT group_id FROM words_groups WHERE word_id = ?)
+		GROUP BY wg.group_id
+		HAVING COUNT(DISTINCT wg.word_id) = COUNT(DISTINCT CASE WHEN p.mastered THEN wg.word_id END)
+	`, learner, wordID)
+	if err != nil {
+		return fmt.Errorf("error querying mastered groups: %v", err)
+	}
+	var mastered []models.GroupMastery
+	for rows.Next() {
+		group := models.GroupMastery{LearnerID: learner}
+		if err := rows.Scan(&group.GroupID, &group.Words); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning mastered group: %v", err)
+		}
+		mastered = append(mastered, group)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating mastered groups: %v", err)
+	}
+
+	for _, group := range mastered {
+		err := queue.raise(ctx, tx, events.Event{
+			Type:      events.GroupMastered,
+			GroupID:   group.GroupID,
+			LearnerID: learner,
+			Data:      group,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanWebhook scans a row selected with webhookColumns
+func scanWebhook(row interface{ Scan(...interface{}) error }) (models.Webhook, error) {
+	var webhook models.Webhook
+	var eventTypes string
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.Secret,
+		&eventTypes,
+		&webhook.GroupID,
+		&webhook.Description,
+		&webhook.Active,
+		&webhook.CreatedAt,
+	)
+	webhook.Events = strings.Split(eventTypes, ",")
+	return webhook, err
+}
+
+// scanDelivery scans a row selected with deliveryColumns
+func scanDelivery(row interface{ Scan(...interface{}) error }) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload string
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastAttemptAt,
+		&delivery.ResponseStatus,
+		&delivery.LastError,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+	)
+	delivery.Payload = json.RawMessage(payload)
+	return delivery, err
+}
+
+// GetWebhooks returns every registered webhook
+func (r *SQLiteRepository) GetWebhooks(ctx context.Context) (_ []models.Webhook, err error) {
+	ctx, op := instrument(ctx, "GetWebhooks")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhooks: %v", err)
+	}
+	defer rows.Close()
+
+	hooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook: %v", err)
+		}
+		hooks = append(hooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %v", err)
+	}
+
+	op.rows(int64(len(hooks)))
+	return hooks, nil
+}
+
+// GetWebhook returns a webhook, or nil if it does not exist
+func (r *SQLiteRepository) GetWebhook(ctx context.Context, id int64) (_ *models.Webhook, err error) {
+	ctx, op := instrument(ctx, "GetWebhook")
+	defer func() { op.end(err) }()
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook: %v", err)
+	}
+	return &webhook, nil
+}
+
+// CreateWebhook registers a webhook
+func (r *SQLiteRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) (err error) {
+	ctx, op := instrument(ctx, "CreateWebhook")
+	defer func() { op.end(err) }()
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO webhooks (url, secret, events, group_id, description, active)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id, created_at
+	`, webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), webhook.GroupID,
+		webhook.Description, webhook.Active).Scan(&webhook.ID, &webhook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating webhook: %v", err)
+	}
+	return nil
+}
+
+// UpdateWebhook replaces a webhook's settings; an empty secret keeps the
+// current one
+func (r *SQLiteRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) (err error) {
+	ctx, op := instrument(ctx, "UpdateWebhook")
+	defer func() { op.end(err) }()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhooks
+		SET url = ?, secret = COALESCE(NULLIF(?, ''), secret), events = ?, group_id = ?, description = ?, active = ?
+		WHERE id = ?
+	`, webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), webhook.GroupID,
+		webhook.Description, webhook.Active, webhook.ID)
+	if err != nil {
+		return fmt.Errorf("error updating webhook: %v", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error updating webhook: %v", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: webhook %d", ErrNotFound, webhook.ID)
+	}
+
+	op.rows(n)
+	return nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (r *SQLiteRepository) DeleteWebhook(ctx context.Context, id int64) (err error) {
+	ctx, op := instrument(ctx, "DeleteWebhook")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return fmt.Errorf("error deleting webhook deliveries: %v", err)
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %v", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %v", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: webhook %d", ErrNotFound, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+	op.rows(n)
+	return nil
+}
+
+// GetWebhookDeliveries returns a webhook's most recent deliveries,
+// optionally only those with a status
+func (r *SQLiteRepository) GetWebhookDeliveries(ctx context.Context, webhookID int64, status string, limit int) (_ []models.WebhookDelivery, err error) {
+	ctx, op := instrument(ctx, "GetWebhookDeliveries")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE webhook_id = ? AND (? = '' OR status = ?)
+		ORDER BY id DESC
+		LIMIT ?
+	`, webhookID, status, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %v", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %v", err)
+	}
+
+	op.rows(int64(len(deliveries)))
+	return deliveries, nil
+}
+
+// RedeliverWebhookDelivery queues a fresh copy of a past delivery, with
+// the same event ID and payload, for immediate sending
+func (r *SQLiteRepository) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (_ *models.WebhookDelivery, err error) {
+	ctx, op := instrument(ctx, "RedeliverWebhookDelivery")
+	defer func() { op.end(err) }()
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at)
+		SELECT webhook_id, event_id, event_type, payload, ?
+		FROM webhook_deliveries
+		WHERE id = ? AND webhook_id = ?
+		RETURNING `+deliveryColumns,
+		time.Now().UTC().Format(scheduleTimeLayout), deliveryID, webhookID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: webhook delivery %d", ErrNotFound, deliveryID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error queueing webhook redelivery: %v", err)
+	}
+
+	op.rows(1)
+	return &delivery, nil
+}
+
+// GetDueWebhookDeliveries returns pending deliveries of active webhooks
+// whose next attempt is due, oldest first
+func (r *SQLiteRepository) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) (_ []models.OutboxDelivery, err error) {
+	ctx, op := instrument(ctx, "GetDueWebhookDeliveries")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.attempts, w.url, w.secret
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND w.active AND d.next_attempt_at <= ?
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT ?
+	`, models.DeliveryPending, now.UTC().Format(scheduleTimeLayout), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying due webhook deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.OutboxDelivery
+	for rows.Next() {
+		var delivery models.OutboxDelivery
+		var payload string
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&payload,
+			&delivery.Attempts,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %v", err)
+		}
+		delivery.Payload = []byte(payload)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %v", err)
+	}
+
+	op.rows(int64(len(deliveries)))
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt logs the outcome of sending a delivery
+func (r *SQLiteRepository) RecordWebhookAttempt(ctx context.Context, attempt models.WebhookAttempt) (err error) {
+	ctx, op := instrument(ctx, "RecordWebhookAttempt")
+	defer func() { op.end(err) }()
+
+	at := attempt.AttemptedAt.UTC().Format(scheduleTimeLayout)
+	var next, delivered interface{}
+	if attempt.NextAttemptAt != nil {
+		next = attempt.NextAttemptAt.UTC().Format(scheduleTimeLayout)
+	}
+	if attempt.Status == models.DeliveryDelivered {
+		delivered = at
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = attempts + 1, last_attempt_at = ?, next_attempt_at = ?,
+			response_status = NULLIF(?, 0), last_error = NULLIF(?, ''), delivered_at = ?
+		WHERE id = ?
+	`, attempt.Status, at, next, attempt.ResponseStatus, attempt.Error, delivered, attempt.DeliveryID)
+	if err != nil {
+		return fmt.Errorf("error recording webhook attempt: %v", err)
+	}
+	return nil
+}
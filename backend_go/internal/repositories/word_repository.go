@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -10,7 +11,10 @@ import (
 )
 
 // GetWords retrieves words with filtering and pagination
-func (r *SQLiteRepository) GetWords(groupID int64, search string, page, pageSize int) ([]models.Word, int, error) {
+func (r *SQLiteRepository) GetWords(ctx context.Context, groupID int64, search string, page, pageSize int) (_ []models.Word, _ int, err error) {
+	ctx, op := instrument(ctx, "GetWords")
+	defer func() { op.end(err) }()
+
 	offset := (page - 1) * pageSize
 
 	// First get total count
@@ -18,9 +22,13 @@ func (r *SQLiteRepository) GetWords(groupID int64, search string, page, pageSize
 	args := []interface{}{}
 
 	// Add filters
-	wheres := []string{}
+	wheres := []string{"w.deleted_at IS NULL"}
 	if groupID > 0 {
-		wheres = append(wheres, "w.id IN (SELECT word_id FROM words_groups WHERE group_id = ?)")
+		wheres = append(wheres, `w.id IN (
+			SELECT wg.word_id FROM words_groups wg
+			JOIN groups g ON g.id = wg.group_id
+			WHERE wg.group_id = ? AND g.deleted_at IS NULL
+		)`)
 		args = append(args, groupID)
 	}
 	if search != "" {
@@ -28,12 +36,10 @@ func (r *SQLiteRepository) GetWords(groupID int64, search string, page, pageSize
 		searchPattern := "%" + search + "%"
 		args = append(args, searchPattern, searchPattern, searchPattern)
 	}
-	if len(wheres) > 0 {
-		countQuery += " WHERE " + strings.Join(wheres, " AND ")
-	}
+	countQuery += " WHERE " + strings.Join(wheres, " AND ")
 
 	var totalCount int
-	err := r.db.QueryRow(countQuery, args...).Scan(&totalCount)
+	err = r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error getting total count: %v", err)
 	}
@@ -43,13 +49,11 @@ func (r *SQLiteRepository) GetWords(groupID int64, search string, page, pageSize
 		SELECT w.id, w.arabic, w.romaji, w.english, w.parts, w.created_at
 		FROM words w
 	`
-	if len(wheres) > 0 {
-		query += " WHERE " + strings.Join(wheres, " AND ")
-	}
+	query += " WHERE " + strings.Join(wheres, " AND ")
 	query += " ORDER BY w.created_at DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error querying words: %v", err)
 	}
@@ -82,17 +86,21 @@ func (r *SQLiteRepository) GetWords(groupID int64, search string, page, pageSize
 		return nil, 0, fmt.Errorf("error iterating word rows: %v", err)
 	}
 
+	op.rows(int64(len(words)))
 	return words, totalCount, nil
 }
 
 // GetWordByID retrieves a single word by ID
-func (r *SQLiteRepository) GetWordByID(id int64) (*models.Word, error) {
+func (r *SQLiteRepository) GetWordByID(ctx context.Context, id int64) (_ *models.Word, err error) {
+	ctx, op := instrument(ctx, "GetWordByID")
+	defer func() { op.end(err) }()
+
 	var word models.Word
 	var partsStr string
-	err := r.db.QueryRow(`
+	err = r.db.QueryRowContext(ctx, `
 		SELECT id, arabic, romaji, english, parts, created_at
 		FROM words
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`, id).Scan(
 		&word.ID,
 		&word.Arabic,
@@ -117,14 +125,17 @@ func (r *SQLiteRepository) GetWordByID(id int64) (*models.Word, error) {
 }
 
 // CreateWord creates a new word
-func (r *SQLiteRepository) CreateWord(word *models.Word) error {
-	tx, err := r.db.Begin()
+func (r *SQLiteRepository) CreateWord(ctx context.Context, word *models.Word) (err error) {
+	ctx, op := instrument(ctx, "CreateWord")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error starting transaction: %v", err)
 	}
 	defer tx.Rollback()
 
-	result, err := tx.Exec(`
+	result, err := tx.ExecContext(ctx, `
 		INSERT INTO words (arabic, romaji, english, parts)
 		VALUES (?, ?, ?, ?)
 	`, word.Arabic, word.Romaji, word.English, word.Parts)
@@ -138,12 +149,39 @@ func (r *SQLiteRepository) CreateWord(word *models.Word) error {
 	}
 	word.ID = id
 
+	created, err := getWordTx(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	word.CreatedAt = created.CreatedAt
+
+	if err := recordAudit(ctx, tx, models.EntityWord, id, models.AuditCreate, nil, created); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
 // UpdateWord updates an existing word
-func (r *SQLiteRepository) UpdateWord(word *models.Word) error {
-	result, err := r.db.Exec(`
+func (r *SQLiteRepository) UpdateWord(ctx context.Context, word *models.Word) (err error) {
+	ctx, op := instrument(ctx, "UpdateWord")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getWordTx(ctx, tx, word.ID)
+	if err != nil {
+		return err
+	}
+	if before == nil || before.DeletedAt != nil {
+		return fmt.Errorf("%w: word %d", ErrNotFound, word.ID)
+	}
+
+	result, err := tx.ExecContext(ctx, `
 		UPDATE words
 		SET arabic = ?, romaji = ?, english = ?, parts = ?
 		WHERE id = ?
@@ -156,16 +194,45 @@ func (r *SQLiteRepository) UpdateWord(word *models.Word) error {
 	if err != nil {
 		return fmt.Errorf("error getting rows affected: %v", err)
 	}
-	if rows == 0 {
-		return fmt.Errorf("word not found: %d", word.ID)
+	op.rows(rows)
+
+	after, err := getWordTx(ctx, tx, word.ID)
+	if err != nil {
+		return err
+	}
+	word.CreatedAt = after.CreatedAt
+
+	if err := recordAudit(ctx, tx, models.EntityWord, word.ID, models.AuditUpdate, before, after); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-// DeleteWord deletes a word by ID
-func (r *SQLiteRepository) DeleteWord(id int64) error {
-	result, err := r.db.Exec("DELETE FROM words WHERE id = ?", id)
+// DeleteWord moves a word to the trash
+func (r *SQLiteRepository) DeleteWord(ctx context.Context, id int64) (err error) {
+	ctx, op := instrument(ctx, "DeleteWord")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getWordTx(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if before == nil || before.DeletedAt != nil {
+		return fmt.Errorf("%w: word %d", ErrNotFound, id)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE words
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL
+	`, id)
 	if err != nil {
 		return fmt.Errorf("error deleting word: %v", err)
 	}
@@ -174,9 +241,151 @@ func (r *SQLiteRepository) DeleteWord(id int64) error {
 	if err != nil {
 		return fmt.Errorf("error getting rows affected: %v", err)
 	}
-	if rows == 0 {
-		return fmt.Errorf("word not found: %d", id)
+	op.rows(rows)
+
+	if err := recordAudit(ctx, tx, models.EntityWord, id, models.AuditDelete, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevertWord restores a word to the state recorded after the given version
+// of its history, recreating it if it has since been deleted
+func (r *SQLiteRepository) RevertWord(ctx context.Context, id int64, version int) (_ *models.Word, err error) {
+	ctx, op := instrument(ctx, "RevertWord")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	event, err := getAuditEvent(ctx, tx, models.EntityWord, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, fmt.Errorf("%w: word %d version %d", ErrNotFound, id, version)
+	}
+	if string(event.After) == "null" {
+		return nil, fmt.Errorf("%w: version %d deleted the word", ErrInvalidRevert, version)
+	}
+
+	var target models.Word
+	if err := json.Unmarshal(event.After, &target); err != nil {
+		return nil, fmt.Errorf("error decoding word snapshot: %v", err)
+	}
+
+	before, err := getWordTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if before == nil {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO words (id, arabic, romaji, english, parts, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, id, target.Arabic, target.Romaji, target.English, []byte(target.Parts), target.CreatedAt)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE words
+			SET arabic = ?, romaji = ?, english = ?, parts = ?, deleted_at = NULL
+			WHERE id = ?
+		`, target.Arabic, target.Romaji, target.English, []byte(target.Parts), id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reverting word: %v", err)
+	}
+
+	after, err := getWordTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var beforeSnapshot interface{}
+	if before != nil && before.DeletedAt == nil {
+		beforeSnapshot = before
+	}
+	if err := recordAudit(ctx, tx, models.EntityWord, id, models.AuditRevert, beforeSnapshot, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing revert: %v", err)
+	}
+
+	return after, nil
+}
+
+// RestoreWord takes a word back out of the trash
+func (r *SQLiteRepository) RestoreWord(ctx context.Context, id int64) (_ *models.Word, err error) {
+	ctx, op := instrument(ctx, "RestoreWord")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getWordTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil || before.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: word %d in trash", ErrNotFound, id)
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE words SET deleted_at = NULL WHERE id = ?", id); err != nil {
+		return nil, fmt.Errorf("error restoring word: %v", err)
+	}
+
+	after, err := getWordTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, tx, models.EntityWord, id, models.AuditRestore, nil, after); err != nil {
+		return nil, err
 	}
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing restore: %v", err)
+	}
+
+	return after, nil
+}
+
+// getWordTx loads a word inside a transaction, including trashed words,
+// returning nil if it is missing
+func getWordTx(ctx context.Context, tx *sql.Tx, id int64) (*models.Word, error) {
+	var word models.Word
+	var partsStr string
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, arabic, romaji, english, COALESCE(parts, ''), created_at, deleted_at
+		FROM words
+		WHERE id = ?
+	`, id).Scan(
+		&word.ID,
+		&word.Arabic,
+		&word.Romaji,
+		&word.English,
+		&partsStr,
+		&word.CreatedAt,
+		&word.DeletedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning word: %v", err)
+	}
+
+	if partsStr != "" {
+		word.Parts = json.RawMessage(partsStr)
+	}
+
+	return &word, nil
 }
@@ -1,70 +1,301 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"time"
 
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/metrics"
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
 )
 
+// reviewColumns lists the word_review_items columns read by scanReviews
+const reviewColumns = `id, client_id, word_id, study_session_id, is_correct, answer_text, score,
+	response_ms, COALESCE(direction, ''), COALESCE(mode, ''), hints_used, confidence, learner_id, created_at`
+
 // GetWordReviewItems returns all word review items for a study session
-func (r *SQLiteRepository) GetWordReviewItems(sessionID int64) ([]models.WordReviewItem, error) {
-	query := `
-		SELECT id, word_id, study_session_id, is_correct, created_at
+func (r *SQLiteRepository) GetWordReviewItems(ctx context.Context, sessionID int64) (_ []models.WordReviewItem, err error) {
+	ctx, op := instrument(ctx, "GetWordReviewItems")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`
 		FROM word_review_items
 		WHERE study_session_id = ?
-	`
+		ORDER BY created_at, id
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying word review items: %v", err)
+	}
+	defer rows.Close()
+
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(reviews)))
+	return reviews, nil
+}
+
+// GetWordReviewItemsByWord returns all review items for a word, newest first
+func (r *SQLiteRepository) GetWordReviewItemsByWord(ctx context.Context, wordID int64) (_ []models.WordReviewItem, err error) {
+	ctx, op := instrument(ctx, "GetWordReviewItemsByWord")
+	defer func() { op.end(err) }()
 
-	rows, err := r.db.Query(query, sessionID)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM word_review_items
+		WHERE word_id = ?
+		ORDER BY created_at DESC, id DESC
+	`, wordID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying word review items: %v", err)
 	}
 	defer rows.Close()
 
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(reviews)))
+	return reviews, nil
+}
+
+// GetLearnerWordReviews returns the context's learner's review items for a
+// word, newest first
+func (r *SQLiteRepository) GetLearnerWordReviews(ctx context.Context, wordID int64) (_ []models.WordReviewItem, err error) {
+	ctx, op := instrument(ctx, "GetLearnerWordReviews")
+	defer func() { op.end(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM word_review_items
+		WHERE word_id = ? AND learner_id = ?
+		ORDER BY created_at DESC, id DESC
+	`, wordID, learnerFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error querying learner word reviews: %v", err)
+	}
+	defer rows.Close()
+
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	op.rows(int64(len(reviews)))
+	return reviews, nil
+}
+
+// CreateWordReviewItem creates a new word review item and runs the
+// learner's review triggers (scheduling, XP, achievements). A review whose
+// client_id was already recorded is rejected with ErrConflict.
+func (r *SQLiteRepository) CreateWordReviewItem(ctx context.Context, review *models.WordReviewItem) (err error) {
+	ctx, op := instrument(ctx, "CreateWordReviewItem")
+	defer func() { op.end(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSessionLearner(ctx, tx, review.StudySessionID); err != nil {
+		return err
+	}
+
+	err = insertReview(ctx, tx, review)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: review %s already recorded", ErrConflict, *review.ClientID)
+	}
+	if err != nil {
+		return fmt.Errorf("error creating word review item: %v", err)
+	}
+
+	var queue eventQueue
+	if err := onReviewRecorded(ctx, tx, &queue, review); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing word review item: %v", err)
+	}
+	queue.publish()
+
+	metrics.RecordReview(review.IsCorrect)
+
+	return nil
+}
+
+// checkSessionLearner returns ErrNotFound unless the study session exists
+// and belongs to the context's learner, so reviews cannot be filed under
+// another learner's session
+func checkSessionLearner(ctx context.Context, tx *sql.Tx, sessionID int64) error {
+	var learner string
+	err := tx.QueryRowContext(ctx, "SELECT learner_id FROM study_sessions WHERE id = ?", sessionID).Scan(&learner)
+	if err == sql.ErrNoRows || (err == nil && learner != learnerFromContext(ctx)) {
+		return fmt.Errorf("%w: study session %d", ErrNotFound, sessionID)
+	}
+	if err != nil {
+		return fmt.Errorf("error querying study session: %v", err)
+	}
+	return nil
+}
+
+// insertReview stores a review made by the context's learner, returning
+// sql.ErrNoRows when its client_id has already been recorded
+func insertReview(ctx context.Context, tx *sql.Tx, review *models.WordReviewItem) error {
+	review.LearnerID = learnerFromContext(ctx)
+	return tx.QueryRowContext(ctx, `
+		INSERT INTO word_review_items
+			(client_id, word_id, study_session_id, is_correct, answer_text, score,
+			 response_ms, direction, mode, hints_used, confidence, learner_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)
+		ON CONFLICT(client_id) WHERE client_id IS NOT NULL DO NOTHING
+		RETURNING id, created_at
+	`,
+		review.ClientID,
+		review.WordID,
+		review.StudySessionID,
+		review.IsCorrect,
+		review.AnswerText,
+		review.Score,
+		review.ResponseMS,
+		review.Direction,
+		review.Mode,
+		review.HintsUsed,
+		review.Confidence,
+		review.LearnerID,
+	).Scan(&review.ID, &review.CreatedAt)
+}
+
+// GetSessionReviewAnalytics summarises the reviews of a study session
+func (r *SQLiteRepository) GetSessionReviewAnalytics(ctx context.Context, sessionID int64) (_ *models.ReviewAnalytics, err error) {
+	ctx, op := instrument(ctx, "GetSessionReviewAnalytics")
+	defer func() { op.end(err) }()
+
+	return r.reviewAnalytics(ctx, "study_session_id = ?", sessionID)
+}
+
+// GetWordReviewAnalytics summarises the reviews of a word
+func (r *SQLiteRepository) GetWordReviewAnalytics(ctx context.Context, wordID int64) (_ *models.ReviewAnalytics, err error) {
+	ctx, op := instrument(ctx, "GetWordReviewAnalytics")
+	defer func() { op.end(err) }()
+
+	return r.reviewAnalytics(ctx, "word_id = ?", wordID)
+}
+
+// reviewAnalytics aggregates the review items matching where, overall and per mode
+func (r *SQLiteRepository) reviewAnalytics(ctx context.Context, where string, arg interface{}) (*models.ReviewAnalytics, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(mode, ''),
+			COUNT(*),
+			COALESCE(SUM(is_correct), 0),
+			SUM(score),
+			COUNT(score),
+			SUM(response_ms),
+			COUNT(response_ms),
+			COALESCE(SUM(hints_used), 0),
+			SUM(confidence),
+			COUNT(confidence)
+		FROM word_review_items
+		WHERE `+where+`
+		GROUP BY COALESCE(mode, '')
+	`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("error querying review analytics: %v", err)
+	}
+	defer rows.Close()
+
+	analytics := &models.ReviewAnalytics{ByMode: map[string]models.ReviewModeAnalytics{}}
+	var scoreSum, responseSum, confidenceSum float64
+	var scoreCount, responseCount, confidenceCount int
+	for rows.Next() {
+		var mode string
+		var attempts, correct, hints, scored, timed, rated int
+		var score, response, confidence sql.NullFloat64
+		err := rows.Scan(&mode, &attempts, &correct, &score, &scored, &response, &timed, &hints, &confidence, &rated)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning review analytics: %v", err)
+		}
+
+		analytics.Attempts += attempts
+		analytics.CorrectCount += correct
+		analytics.HintsUsed += hints
+		scoreSum += score.Float64
+		scoreCount += scored
+		responseSum += response.Float64
+		responseCount += timed
+		confidenceSum += confidence.Float64
+		confidenceCount += rated
+
+		if mode == "" {
+			mode = "unspecified"
+		}
+		analytics.ByMode[mode] = models.ReviewModeAnalytics{
+			Attempts:      attempts,
+			CorrectCount:  correct,
+			AvgResponseMS: average(response.Float64, timed),
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review analytics: %v", err)
+	}
+
+	if analytics.Attempts > 0 {
+		analytics.Accuracy = float64(analytics.CorrectCount) / float64(analytics.Attempts)
+	}
+	analytics.AvgScore = average(scoreSum, scoreCount)
+	analytics.AvgResponseMS = average(responseSum, responseCount)
+	analytics.AvgConfidence = average(confidenceSum, confidenceCount)
+
+	return analytics, nil
+}
+
+// average returns sum/count, or nil when nothing was counted
+func average(sum float64, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+	avg := sum / float64(count)
+	return &avg
+}
+
+// scanReviews reads review items selected with reviewColumns
+func scanReviews(rows *sql.Rows) ([]models.WordReviewItem, error) {
 	var reviews []models.WordReviewItem
 	for rows.Next() {
 		var review models.WordReviewItem
-		var createdAt string
 		err := rows.Scan(
 			&review.ID,
+			&review.ClientID,
 			&review.WordID,
 			&review.StudySessionID,
 			&review.IsCorrect,
-			&createdAt,
+			&review.AnswerText,
+			&review.Score,
+			&review.ResponseMS,
+			&review.Direction,
+			&review.Mode,
+			&review.HintsUsed,
+			&review.Confidence,
+			&review.LearnerID,
+			&review.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning word review item: %v", err)
 		}
 
-		review.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing created_at: %v", err)
-		}
-
 		reviews = append(reviews, review)
 	}
 
-	return reviews, nil
-}
-
-// CreateWordReviewItem creates a new word review item
-func (r *SQLiteRepository) CreateWordReviewItem(review *models.WordReviewItem) error {
-	query := `
-		INSERT INTO word_review_items (word_id, study_session_id, is_correct)
-		VALUES (?, ?, ?)
-		RETURNING id, created_at
-	`
-
-	var createdAt string
-	err := r.db.QueryRow(query, review.WordID, review.StudySessionID, review.IsCorrect).Scan(&review.ID, &createdAt)
-	if err != nil {
-		return fmt.Errorf("error creating word review item: %v", err)
-	}
-
-	review.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAt)
-	if err != nil {
-		return fmt.Errorf("error parsing created_at: %v", err)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word review items: %v", err)
 	}
 
-	return nil
+	return reviews, nil
 }
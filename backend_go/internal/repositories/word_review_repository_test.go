@@ -0,0 +1,27 @@
+package repositories
+
+import "testing"
+
+func TestGetLearnerWordReviews(t *testing.T) {
+	r := newTestRepository(t)
+	alice, bob := asLearner("alice"), asLearner("bob")
+	aliceSession := createSession(t, r, alice, 1)
+	bobSession := createSession(t, r, bob, 1)
+
+	first := review(t, r, alice, aliceSession.ID, 1, false)
+	second := review(t, r, alice, aliceSession.ID, 1, true)
+	review(t, r, alice, aliceSession.ID, 2, true)
+	review(t, r, bob, bobSession.ID, 1, true)
+
+	reviews, err := r.GetLearnerWordReviews(alice, 1)
+	if err != nil {
+		t.Fatalf("GetLearnerWordReviews: %v", err)
+	}
+	if len(reviews) != 2 || reviews[0].ID != second.ID || reviews[1].ID != first.ID {
+		t.Fatalf("alice's reviews of word 1 = %+v, want %d then %d", reviews, second.ID, first.ID)
+	}
+
+	if reviews, err := r.GetLearnerWordReviews(asLearner("carol"), 1); err != nil || len(reviews) != 0 {
+		t.Errorf("carol's reviews = %+v, %v; want none", reviews, err)
+	}
+}
@@ -0,0 +1,138 @@
+package rooms
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 4096
+	sendBuffer     = 32
+)
+
+// client is one WebSocket connection to a room, either the host or a
+// player
+type client struct {
+	room    *Room
+	conn    *websocket.Conn
+	send    chan []byte
+	host    bool
+	learner string
+
+	closeMu sync.Mutex
+	closed  bool
+}
+
+// Serve attaches a WebSocket connection to the room and pumps messages
+// until either side closes it. Players are identified by learner.
+func (r *Room) Serve(conn *websocket.Conn, host bool, learner, name string) {
+	c := &client{
+		room:    r,
+		conn:    conn,
+		send:    make(chan []byte, sendBuffer),
+		host:    host,
+		learner: learner,
+	}
+
+	go c.writePump()
+	joined := make(chan struct{})
+	r.do(func() {
+		r.join(c, name)
+		close(joined)
+	})
+	select {
+	case <-joined:
+	case <-r.done:
+		c.closeSend()
+	}
+	c.readPump()
+}
+
+// queue hands data to the write pump; it reports false when the client
+// is closed or its buffer is full
+func (c *client) queue(data []byte) bool {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeSend stops the write pump, which then closes the connection
+func (c *client) closeSend() {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// readPump decodes client messages and hands them to the room
+func (c *client) readPump() {
+	defer func() {
+		c.room.do(func() { c.room.leave(c) })
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg clientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.room.do(func() { c.room.send(c, errorMessage("invalid message")) })
+			continue
+		}
+		c.room.do(func() { c.room.handle(c, msg) })
+	}
+}
+
+// writePump writes queued messages and keeps the connection alive
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
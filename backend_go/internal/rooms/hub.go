@@ -0,0 +1,159 @@
+package rooms
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+const (
+	// MaxRooms bounds how many rooms may be open at once
+	MaxRooms = 100
+
+	codeLength = 6
+	// codeAlphabet leaves out characters that are easy to misread
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	// lobbyTimeout closes rooms whose game never starts
+	lobbyTimeout = time.Hour
+	// finishedTTL keeps final scoreboards readable for a while
+	finishedTTL = 10 * time.Minute
+	// revealPause is how long answers are shown before the next question
+	revealPause = 4 * time.Second
+	// recordTimeout bounds each database write made for a room
+	recordTimeout = 5 * time.Second
+)
+
+// ErrTooManyRooms is returned when MaxRooms rooms are already open
+var ErrTooManyRooms = errors.New("too many open rooms")
+
+// Recorder persists each participant's session and answers
+type Recorder interface {
+	CreateStudySession(ctx context.Context, session *models.StudySession) error
+	CreateWordReviewItem(ctx context.Context, review *models.WordReviewItem) error
+	FinishStudySession(ctx context.Context, id int64) (*models.StudySession, error)
+}
+
+// Config describes the game played in a room
+type Config struct {
+	GroupID         int64
+	StudyActivityID int64
+	Direction       string
+	QuestionSeconds int
+	Questions       []models.QuizQuestion
+}
+
+// Hub holds the open rooms. Each room runs its own goroutine that owns
+// its state, so the hub only guards the code index.
+type Hub struct {
+	mu       sync.Mutex
+	rooms    map[string]*Room
+	recorder Recorder
+	closed   bool
+}
+
+// NewHub creates an empty hub recording results through recorder
+func NewHub(recorder Recorder) *Hub {
+	return &Hub{rooms: make(map[string]*Room), recorder: recorder}
+}
+
+// Create opens a room with a fresh join code and starts its goroutine
+func (h *Hub) Create(cfg Config) (*Room, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed || len(h.rooms) >= MaxRooms {
+		return nil, ErrTooManyRooms
+	}
+
+	code, err := randomCode()
+	for err == nil && h.rooms[code] != nil {
+		code, err = randomCode()
+	}
+	if err != nil {
+		return nil, err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	room := newRoom(code, token, cfg, h)
+	h.rooms[code] = room
+	go room.run()
+	go room.journal.run()
+	time.AfterFunc(lobbyTimeout, func() {
+		room.do(func() {
+			if room.status == models.RoomLobby {
+				room.shutdown()
+			}
+		})
+	})
+	return room, nil
+}
+
+// Get returns the open room with a join code
+func (h *Hub) Get(code string) (*Room, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room, ok := h.rooms[code]
+	return room, ok
+}
+
+// remove forgets a closed room
+func (h *Hub) remove(code string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.rooms, code)
+}
+
+// Close shuts every room down, disconnects its clients and waits for
+// their queued writes
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	rooms := make([]*Room, 0, len(h.rooms))
+	for _, room := range h.rooms {
+		rooms = append(rooms, room)
+	}
+	h.mu.Unlock()
+
+	for _, room := range rooms {
+		room.do(room.shutdown)
+		<-room.journal.done
+	}
+}
+
+// randomCode returns a join code
+func randomCode() (string, error) {
+	b := make([]byte, codeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// randomToken returns a host token
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// tokensEqual compares tokens in constant time
+func tokensEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
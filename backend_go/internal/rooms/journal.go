@@ -0,0 +1,137 @@
+package rooms
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+)
+
+// journal persists a room's study sessions and answers on its own
+// goroutine, in the order the room submits them, so database writes never
+// hold up the game. Its queue is unbounded; a game submits at most one
+// write per player and question.
+type journal struct {
+	room     string
+	cfg      Config
+	recorder Recorder
+
+	mu      sync.Mutex
+	pending []func()
+	closed  bool
+	wake    chan struct{}
+	done    chan struct{}
+
+	// sessions maps learners to their study session; only the journal
+	// goroutine touches it
+	sessions map[string]int64
+}
+
+func newJournal(room string, cfg Config, recorder Recorder) *journal {
+	return &journal{
+		room:     room,
+		cfg:      cfg,
+		recorder: recorder,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		sessions: make(map[string]int64),
+	}
+}
+
+// run performs queued writes until the journal is closed and drained
+func (j *journal) run() {
+	defer close(j.done)
+	for {
+		j.mu.Lock()
+		batch, closed := j.pending, j.closed
+		j.pending = nil
+		j.mu.Unlock()
+
+		for _, f := range batch {
+			f()
+		}
+		if len(batch) == 0 {
+			if closed {
+				return
+			}
+			<-j.wake
+		}
+	}
+}
+
+// add queues a write; writes queued after close are dropped
+func (j *journal) add(f func()) {
+	j.mu.Lock()
+	if j.closed {
+		j.mu.Unlock()
+		return
+	}
+	j.pending = append(j.pending, f)
+	j.mu.Unlock()
+	j.signal()
+}
+
+// close stops accepting writes; queued ones are still performed
+func (j *journal) close() {
+	j.mu.Lock()
+	j.closed = true
+	j.mu.Unlock()
+	j.signal()
+}
+
+func (j *journal) signal() {
+	select {
+	case j.wake <- struct{}{}:
+	default:
+	}
+}
+
+// startSession opens the learner's study session
+func (j *journal) startSession(learner string) {
+	j.add(func() {
+		session := models.StudySession{StudyActivityID: j.cfg.StudyActivityID, GroupID: j.cfg.GroupID}
+		ctx, cancel := learnerContext(learner)
+		defer cancel()
+		if err := j.recorder.CreateStudySession(ctx, &session); err != nil {
+			slog.Error("error creating room study session", "room", j.room, "learner", learner, "error", err)
+			return
+		}
+		j.sessions[learner] = session.ID
+	})
+}
+
+// record stores an answer as a review in the learner's study session
+func (j *journal) record(learner string, review models.WordReviewItem) {
+	j.add(func() {
+		review.StudySessionID = j.sessions[learner]
+		if review.StudySessionID == 0 {
+			return
+		}
+		ctx, cancel := learnerContext(learner)
+		defer cancel()
+		if err := j.recorder.CreateWordReviewItem(ctx, &review); err != nil {
+			slog.Error("error recording room answer", "room", j.room, "learner", learner, "error", err)
+		}
+	})
+}
+
+// finishSessions finishes every study session the room opened
+func (j *journal) finishSessions() {
+	j.add(func() {
+		for learner, sessionID := range j.sessions {
+			ctx, cancel := learnerContext(learner)
+			if _, err := j.recorder.FinishStudySession(ctx, sessionID); err != nil {
+				slog.Error("error finishing room study session", "room", j.room, "session_id", sessionID, "error", err)
+			}
+			cancel()
+		}
+	})
+}
+
+// learnerContext attributes database writes to a learner
+func learnerContext(learner string) (context.Context, context.CancelFunc) {
+	ctx := repositories.WithAuditInfo(context.Background(), repositories.AuditInfo{Actor: learner})
+	return context.WithTimeout(ctx, recordTimeout)
+}
@@ -0,0 +1,522 @@
+package rooms
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// Points for a correct answer: a base plus a bonus shrinking with the
+// time taken
+const (
+	basePoints  = 500
+	speedPoints = 500
+)
+
+// Room is a live quiz game. Its state is only touched by its own
+// goroutine; other goroutines send it closures through do. Sessions and
+// answers are persisted by its journal.
+type Room struct {
+	code      string
+	hostToken string
+	cfg       Config
+	createdAt time.Time
+	hub       *Hub
+	journal   *journal
+
+	commands chan func()
+	done     chan struct{}
+
+	status   string
+	current  int
+	deadline time.Time
+	timer    *time.Timer
+	players  map[string]*player
+	clients  map[*client]struct{}
+}
+
+// player is a participant, kept across reconnects by learner ID
+type player struct {
+	learner  string
+	name     string
+	started  bool
+	score    int
+	correct  int
+	answered int
+	// answers holds the outcome of each answered question by index
+	answers map[int]answer
+}
+
+type answer struct {
+	correct bool
+	points  int
+}
+
+func newRoom(code, token string, cfg Config, hub *Hub) *Room {
+	return &Room{
+		code:      code,
+		hostToken: token,
+		cfg:       cfg,
+		createdAt: time.Now().UTC(),
+		hub:       hub,
+		journal:   newJournal(code, cfg, hub.recorder),
+		commands:  make(chan func()),
+		done:      make(chan struct{}),
+		status:    models.RoomLobby,
+		current:   -1,
+		players:   make(map[string]*player),
+		clients:   make(map[*client]struct{}),
+	}
+}
+
+// Code returns the code students join with
+func (r *Room) Code() string {
+	return r.code
+}
+
+// HostToken returns the token that lets a connection control the game
+func (r *Room) HostToken() string {
+	return r.hostToken
+}
+
+// IsHost reports whether token is the room's host token
+func (r *Room) IsHost(token string) bool {
+	return tokensEqual(token, r.hostToken)
+}
+
+// run executes commands until the room shuts down. Commands still
+// waiting when a command shuts the room down are dropped.
+func (r *Room) run() {
+	for {
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+		select {
+		case f := <-r.commands:
+			f()
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// do runs f on the room goroutine; it is dropped once the room is closed
+func (r *Room) do(f func()) {
+	select {
+	case r.commands <- f:
+	case <-r.done:
+	}
+}
+
+// Snapshot returns the room's public state, or false once it is closed
+func (r *Room) Snapshot() (models.Room, bool) {
+	reply := make(chan models.Room, 1)
+	r.do(func() { reply <- r.snapshot() })
+	select {
+	case room := <-reply:
+		return room, true
+	case <-r.done:
+		return models.Room{}, false
+	}
+}
+
+func (r *Room) snapshot() models.Room {
+	return models.Room{
+		Code:            r.code,
+		GroupID:         r.cfg.GroupID,
+		Direction:       r.cfg.Direction,
+		Status:          r.status,
+		QuestionCount:   len(r.cfg.Questions),
+		QuestionSeconds: r.cfg.QuestionSeconds,
+		CurrentQuestion: r.current + 1,
+		Players:         len(r.players),
+		Scoreboard:      r.scoreboard(),
+		CreatedAt:       r.createdAt,
+	}
+}
+
+// join registers a connected client; players get a study session once
+// the game has started
+func (r *Room) join(c *client, name string) {
+	if !c.host {
+		if r.status == models.RoomFinished {
+			r.send(c, errorMessage("the game has finished"))
+			r.leave(c)
+			return
+		}
+		for other := range r.clients {
+			if !other.host && other.learner == c.learner {
+				r.send(c, errorMessage("already joined from another connection"))
+				r.leave(c)
+				return
+			}
+		}
+
+		p, ok := r.players[c.learner]
+		if !ok {
+			p = &player{learner: c.learner, answers: make(map[int]answer)}
+			r.players[c.learner] = p
+		}
+		p.name = name
+		if r.status != models.RoomLobby {
+			r.startSession(p)
+		}
+	}
+
+	r.clients[c] = struct{}{}
+	r.send(c, r.stateMessage())
+	if r.status == models.RoomQuestion {
+		r.send(c, r.questionMessage())
+	}
+	r.broadcast(r.lobbyMessage())
+}
+
+// leave disconnects a client; its player stays in the game. If the others
+// have all answered the current question, it is revealed without waiting
+// for the timer.
+func (r *Room) leave(c *client) {
+	_, joined := r.clients[c]
+	delete(r.clients, c)
+	c.closeSend()
+	if !joined || c.host {
+		return
+	}
+	switch r.status {
+	case models.RoomLobby:
+		r.broadcast(r.lobbyMessage())
+	case models.RoomQuestion:
+		if r.everyoneAnswered() {
+			r.revealAnswer()
+		}
+	}
+}
+
+// handle applies a message from a client
+func (r *Room) handle(c *client, msg clientMessage) {
+	if _, ok := r.clients[c]; !ok {
+		return
+	}
+	switch msg.Type {
+	case "start":
+		if !c.host {
+			r.send(c, errorMessage("only the host can start the game"))
+			return
+		}
+		if r.status != models.RoomLobby {
+			r.send(c, errorMessage("the game has already started"))
+			return
+		}
+		if len(r.players) == 0 {
+			r.send(c, errorMessage("no players have joined"))
+			return
+		}
+		for _, p := range r.players {
+			r.startSession(p)
+		}
+		r.nextQuestion()
+	case "end":
+		if !c.host {
+			r.send(c, errorMessage("only the host can end the game"))
+			return
+		}
+		r.finish()
+	case "answer":
+		if c.host {
+			r.send(c, errorMessage("the host cannot answer"))
+			return
+		}
+		r.answer(c, msg)
+	default:
+		r.send(c, errorMessage("unknown message type"))
+	}
+}
+
+// nextQuestion pushes the next question to everyone, or finishes the game
+func (r *Room) nextQuestion() {
+	if r.current+1 >= len(r.cfg.Questions) {
+		r.finish()
+		return
+	}
+	r.current++
+
+	r.status = models.RoomQuestion
+	seconds := time.Duration(r.cfg.QuestionSeconds) * time.Second
+	r.deadline = time.Now().Add(seconds)
+	r.broadcast(r.questionMessage())
+
+	index := r.current
+	r.schedule(seconds, func() {
+		if r.status == models.RoomQuestion && r.current == index {
+			r.revealAnswer()
+		}
+	})
+}
+
+// answer scores a player's answer to the current question by correctness
+// and speed, and queues it to be recorded as a word review
+func (r *Room) answer(c *client, msg clientMessage) {
+	p := r.players[c.learner]
+	if r.status != models.RoomQuestion || msg.Question != r.current+1 {
+		r.send(c, errorMessage("question is not open"))
+		return
+	}
+	if _, done := p.answers[r.current]; done {
+		r.send(c, errorMessage("question already answered"))
+		return
+	}
+
+	question := r.cfg.Questions[r.current]
+	var chosen *models.QuizOption
+	for i := range question.Options {
+		if question.Options[i].WordID == msg.WordID {
+			chosen = &question.Options[i]
+		}
+	}
+	if chosen == nil {
+		r.send(c, errorMessage("word is not an option of this question"))
+		return
+	}
+
+	total := time.Duration(r.cfg.QuestionSeconds) * time.Second
+	remaining := time.Until(r.deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+	result := answer{correct: chosen.WordID == question.WordID}
+	if result.correct {
+		result.points = basePoints + int(float64(speedPoints)*remaining.Seconds()/total.Seconds())
+		p.correct++
+	}
+	p.answers[r.current] = result
+	p.answered++
+	p.score += result.points
+
+	responseMS := int((total - remaining).Milliseconds())
+	r.journal.record(p.learner, models.WordReviewItem{
+		WordID:     question.WordID,
+		IsCorrect:  result.correct,
+		AnswerText: &chosen.Text,
+		ResponseMS: &responseMS,
+		Direction:  r.cfg.Direction,
+		Mode:       models.ModeMultipleChoice,
+	})
+
+	r.send(c, message{"type": "answered", "question": r.current + 1})
+	r.broadcastHosts(message{"type": "answer_count", "question": r.current + 1, "answered": r.answeredCount(), "players": len(r.players)})
+
+	if r.everyoneAnswered() {
+		r.revealAnswer()
+	}
+}
+
+// revealAnswer closes the current question, tells each player how they
+// did and shows the scoreboard before moving on
+func (r *Room) revealAnswer() {
+	r.stopTimer()
+	r.status = models.RoomReveal
+
+	question := r.cfg.Questions[r.current]
+	for c := range r.clients {
+		if c.host {
+			continue
+		}
+		p := r.players[c.learner]
+		result, answered := p.answers[r.current]
+		r.send(c, message{
+			"type":     "result",
+			"question": r.current + 1,
+			"answered": answered,
+			"correct":  result.correct,
+			"points":   result.points,
+			"score":    p.score,
+		})
+	}
+	r.broadcast(message{
+		"type":            "question_end",
+		"question":        r.current + 1,
+		"correct_word_id": question.WordID,
+		"scoreboard":      r.scoreboard(),
+	})
+
+	index := r.current
+	r.schedule(revealPause, func() {
+		if r.status == models.RoomReveal && r.current == index {
+			r.nextQuestion()
+		}
+	})
+}
+
+// finish ends the game, finishes every participant's study session and
+// closes the room after finishedTTL
+func (r *Room) finish() {
+	if r.status == models.RoomFinished {
+		return
+	}
+	r.stopTimer()
+	r.status = models.RoomFinished
+	r.broadcast(message{"type": "finished", "scoreboard": r.scoreboard()})
+	r.journal.finishSessions()
+
+	r.schedule(finishedTTL, r.shutdown)
+}
+
+// shutdown disconnects everyone and closes the room. The journal still
+// performs the writes already queued.
+func (r *Room) shutdown() {
+	r.stopTimer()
+	r.status = models.RoomClosed
+	for c := range r.clients {
+		delete(r.clients, c)
+		c.closeSend()
+	}
+	r.hub.remove(r.code)
+	r.journal.close()
+	close(r.done)
+}
+
+// startSession opens the player's study session if it has none
+func (r *Room) startSession(p *player) {
+	if p.started {
+		return
+	}
+	p.started = true
+	r.journal.startSession(p.learner)
+}
+
+// schedule runs f on the room goroutine after d, replacing any pending timer
+func (r *Room) schedule(d time.Duration, f func()) {
+	r.stopTimer()
+	r.timer = time.AfterFunc(d, func() { r.do(f) })
+}
+
+func (r *Room) stopTimer() {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+// answeredCount counts the players who answered the current question
+func (r *Room) answeredCount() int {
+	n := 0
+	for _, p := range r.players {
+		if _, ok := p.answers[r.current]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+// everyoneAnswered reports whether every connected player has answered the
+// current question; players who left are not waited for
+func (r *Room) everyoneAnswered() bool {
+	connected := false
+	for c := range r.clients {
+		if c.host {
+			continue
+		}
+		if _, ok := r.players[c.learner].answers[r.current]; !ok {
+			return false
+		}
+		connected = true
+	}
+	return connected
+}
+
+// scoreboard ranks players by score; equal scores share a rank
+func (r *Room) scoreboard() []models.ScoreboardEntry {
+	entries := make([]models.ScoreboardEntry, 0, len(r.players))
+	for _, p := range r.players {
+		entries = append(entries, models.ScoreboardEntry{
+			Name:     p.name,
+			Score:    p.score,
+			Correct:  p.correct,
+			Answered: p.answered,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries
+}
+
+func (r *Room) stateMessage() message {
+	return message{"type": "state", "room": r.snapshot()}
+}
+
+func (r *Room) lobbyMessage() message {
+	names := make([]string, 0, len(r.players))
+	for _, p := range r.players {
+		names = append(names, p.name)
+	}
+	sort.Strings(names)
+	return message{"type": "players", "players": names}
+}
+
+func (r *Room) questionMessage() message {
+	question := r.cfg.Questions[r.current]
+	return message{
+		"type":     "question",
+		"question": r.current + 1,
+		"total":    len(r.cfg.Questions),
+		"prompt":   question.Prompt,
+		"options":  question.Options,
+		"seconds":  r.cfg.QuestionSeconds,
+		"deadline": r.deadline.UTC(),
+	}
+}
+
+// send queues a message for one client, dropping clients too slow to
+// keep up
+func (r *Room) send(c *client, msg message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Error("error encoding room message", "room", r.code, "error", err)
+		return
+	}
+	if !c.queue(data) {
+		r.leave(c)
+	}
+}
+
+func (r *Room) broadcast(msg message) {
+	for c := range r.clients {
+		r.send(c, msg)
+	}
+}
+
+func (r *Room) broadcastHosts(msg message) {
+	for c := range r.clients {
+		if c.host {
+			r.send(c, msg)
+		}
+	}
+}
+
+// message is a JSON message sent to clients
+type message map[string]interface{}
+
+func errorMessage(text string) message {
+	return message{"type": "error", "error": text}
+}
+
+// clientMessage is a JSON message received from a client
+type clientMessage struct {
+	Type     string `json:"type"`
+	Question int    `json:"question"`
+	WordID   int64  `json:"word_id"`
+}
@@ -0,0 +1,159 @@
+package rooms
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// slowRecorder logs every write after a delay, like a busy database
+type slowRecorder struct {
+	delay time.Duration
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (s *slowRecorder) log(call string) {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+}
+
+func (s *slowRecorder) CreateStudySession(ctx context.Context, session *models.StudySession) error {
+	s.log("start")
+	session.ID = 7
+	return nil
+}
+
+func (s *slowRecorder) CreateWordReviewItem(ctx context.Context, review *models.WordReviewItem) error {
+	s.log(fmt.Sprintf("review %d in %d", review.WordID, review.StudySessionID))
+	return nil
+}
+
+func (s *slowRecorder) FinishStudySession(ctx context.Context, id int64) (*models.StudySession, error) {
+	s.log(fmt.Sprintf("finish %d", id))
+	return &models.StudySession{ID: id}, nil
+}
+
+// testClient is a connection without a socket; messages pile up in send
+func testClient(room *Room, learner string) *client {
+	return &client{room: room, send: make(chan []byte, sendBuffer), learner: learner}
+}
+
+func TestRoomPersistsWithoutBlockingTheGame(t *testing.T) {
+	recorder := &slowRecorder{delay: 100 * time.Millisecond}
+	hub := NewHub(recorder)
+	room, err := hub.Create(Config{
+		GroupID:         1,
+		StudyActivityID: 1,
+		QuestionSeconds: 20,
+		Questions: []models.QuizQuestion{
+			{WordID: 1, Options: []models.QuizOption{{WordID: 1}, {WordID: 2}}},
+			{WordID: 2, Options: []models.QuizOption{{WordID: 1}, {WordID: 2}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := testClient(room, "")
+	host.host = true
+	alice := testClient(room, "alice")
+	room.do(func() { room.join(host, "") })
+	room.do(func() { room.join(alice, "Alice") })
+
+	began := time.Now()
+	room.do(func() { room.handle(host, clientMessage{Type: "start"}) })
+	room.do(func() { room.handle(alice, clientMessage{Type: "answer", Question: 1, WordID: 1}) })
+	room.do(func() { room.handle(host, clientMessage{Type: "end"}) })
+	if elapsed := time.Since(began); elapsed >= recorder.delay {
+		t.Errorf("the game waited %v on the database", elapsed)
+	}
+
+	hub.Close()
+	want := []string{"start", "review 1 in 7", "finish 7"}
+	if fmt.Sprint(recorder.calls) != fmt.Sprint(want) {
+		t.Errorf("writes = %q, want %q", recorder.calls, want)
+	}
+
+	if _, ok := room.Snapshot(); ok {
+		t.Error("Snapshot succeeded on a closed room")
+	}
+	if room.status != models.RoomClosed {
+		t.Errorf("status = %q, want %q", room.status, models.RoomClosed)
+	}
+}
+
+// startedRoom starts a game of questions two-option questions with alice
+// and bob playing
+func startedRoom(t *testing.T, questions int) (room *Room, host, alice, bob *client) {
+	t.Helper()
+	hub := NewHub(&slowRecorder{})
+	t.Cleanup(hub.Close)
+	cfg := Config{GroupID: 1, StudyActivityID: 1, QuestionSeconds: 20}
+	for i := 1; i <= questions; i++ {
+		cfg.Questions = append(cfg.Questions, models.QuizQuestion{WordID: int64(i), Options: []models.QuizOption{{WordID: 1}, {WordID: 2}}})
+	}
+	room, err := hub.Create(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host = testClient(room, "")
+	host.host = true
+	alice, bob = testClient(room, "alice"), testClient(room, "bob")
+	room.do(func() {
+		room.join(host, "")
+		room.join(alice, "Alice")
+		room.join(bob, "Bob")
+		room.handle(host, clientMessage{Type: "start"})
+	})
+	return room, host, alice, bob
+}
+
+func TestRoomRevealsOnceConnectedPlayersAnswered(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps func(room *Room, alice, bob *client)
+	}{
+		{"everyone answers", func(room *Room, alice, bob *client) {
+			room.handle(alice, clientMessage{Type: "answer", Question: 1, WordID: 1})
+			room.handle(bob, clientMessage{Type: "answer", Question: 1, WordID: 2})
+		}},
+		{"an answered player leaves before the last answer", func(room *Room, alice, bob *client) {
+			room.handle(alice, clientMessage{Type: "answer", Question: 1, WordID: 1})
+			room.leave(alice)
+			room.handle(bob, clientMessage{Type: "answer", Question: 1, WordID: 2})
+		}},
+		{"the last player to answer leaves", func(room *Room, alice, bob *client) {
+			room.handle(alice, clientMessage{Type: "answer", Question: 1, WordID: 1})
+			room.leave(bob)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room, _, alice, bob := startedRoom(t, 2)
+			room.do(func() { tt.steps(room, alice, bob) })
+			if snapshot, _ := room.Snapshot(); snapshot.Status != models.RoomReveal {
+				t.Errorf("status = %q, want %q", snapshot.Status, models.RoomReveal)
+			}
+		})
+	}
+}
+
+func TestRoomWaitsForConnectedPlayers(t *testing.T) {
+	room, host, alice, _ := startedRoom(t, 2)
+	room.do(func() {
+		room.handle(alice, clientMessage{Type: "answer", Question: 1, WordID: 1})
+		room.leave(host)
+	})
+	if snapshot, _ := room.Snapshot(); snapshot.Status != models.RoomQuestion {
+		t.Errorf("status = %q with bob still to answer, want %q", snapshot.Status, models.RoomQuestion)
+	}
+}
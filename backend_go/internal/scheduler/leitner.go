@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// Leitner moves words through numbered boxes: a correct answer promotes a
+// word one box, a wrong answer demotes it, and each box has its own
+// review interval
+type Leitner struct {
+	// Intervals holds the review interval of each box, first box first
+	Intervals []time.Duration
+	// DemoteToFirst sends wrong answers back to box 1 instead of one box down
+	DemoteToFirst bool
+}
+
+// Name implements Scheduler
+func (Leitner) Name() string { return models.SchedulerLeitner }
+
+// Next implements Scheduler
+func (l Leitner) Next(state models.WordSchedule, quality int, now time.Time) models.WordSchedule {
+	box := state.Box
+	if box < 1 {
+		box = 1
+	}
+
+	next := state
+	next.Scheduler = models.SchedulerLeitner
+	if quality >= 3 {
+		box++
+		next.Repetitions++
+	} else {
+		if l.DemoteToFirst {
+			box = 1
+		} else {
+			box--
+		}
+		next.Repetitions = 0
+	}
+	if box < 1 {
+		box = 1
+	}
+	if box > len(l.Intervals) {
+		box = len(l.Intervals)
+	}
+
+	next.Box = box
+	next.IntervalDays = days(l.Intervals[box-1])
+	next.DueAt = now.Add(l.Intervals[box-1])
+	next.LastReviewedAt = now
+	return next
+}
@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// Scheduler decides when a word is next due from its previous state and
+// the quality of the latest answer
+type Scheduler interface {
+	Name() string
+	// Next returns the state after a review graded quality (0 to 5, see
+	// Quality). A zero state means the word has not been reviewed yet.
+	Next(state models.WordSchedule, quality int, now time.Time) models.WordSchedule
+}
+
+// Config selects the default scheduler and tunes the Leitner boxes
+type Config struct {
+	Default          string
+	LeitnerIntervals []time.Duration
+	// LeitnerDemoteToFirst sends wrongly answered words back to the first
+	// box instead of one box down
+	LeitnerDemoteToFirst bool
+}
+
+// DefaultConfig uses SM-2 and five Leitner boxes reviewed after 1, 3, 7,
+// 14 and 30 days
+func DefaultConfig() Config {
+	return Config{
+		Default: models.SchedulerSM2,
+		LeitnerIntervals: []time.Duration{
+			24 * time.Hour,
+			3 * 24 * time.Hour,
+			7 * 24 * time.Hour,
+			14 * 24 * time.Hour,
+			30 * 24 * time.Hour,
+		},
+		LeitnerDemoteToFirst: true,
+	}
+}
+
+// ConfigFromEnv reads SCHEDULER (sm2 or leitner), LEITNER_INTERVALS (comma
+// separated Go durations, one per box) and LEITNER_DEMOTION (first or previous)
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+
+	if name := os.Getenv("SCHEDULER"); name != "" {
+		if name == models.SchedulerSM2 || name == models.SchedulerLeitner {
+			cfg.Default = name
+		} else {
+			slog.Warn("invalid SCHEDULER, using default", "value", name, "default", cfg.Default)
+		}
+	}
+
+	if value := os.Getenv("LEITNER_INTERVALS"); value != "" {
+		intervals, ok := parseIntervals(value)
+		if ok {
+			cfg.LeitnerIntervals = intervals
+		} else {
+			slog.Warn("invalid LEITNER_INTERVALS, using default boxes", "value", value)
+		}
+	}
+
+	switch demotion := os.Getenv("LEITNER_DEMOTION"); demotion {
+	case "", "first":
+	case "previous":
+		cfg.LeitnerDemoteToFirst = false
+	default:
+		slog.Warn("invalid LEITNER_DEMOTION, demoting to the first box", "value", demotion)
+	}
+
+	return cfg
+}
+
+// parseIntervals parses comma separated positive durations
+func parseIntervals(value string) ([]time.Duration, bool) {
+	var intervals []time.Duration
+	for _, field := range strings.Split(value, ",") {
+		interval, err := time.ParseDuration(strings.TrimSpace(field))
+		if err != nil || interval <= 0 {
+			return nil, false
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals, len(intervals) > 0
+}
+
+var (
+	schedulers  = map[string]Scheduler{}
+	defaultName string
+)
+
+func init() {
+	Init(DefaultConfig())
+}
+
+// Init registers the schedulers described by cfg
+func Init(cfg Config) {
+	schedulers = map[string]Scheduler{
+		models.SchedulerSM2: SM2{},
+		models.SchedulerLeitner: Leitner{
+			Intervals:     cfg.LeitnerIntervals,
+			DemoteToFirst: cfg.LeitnerDemoteToFirst,
+		},
+	}
+	defaultName = cfg.Default
+}
+
+// Get returns the scheduler registered under name
+func Get(name string) (Scheduler, bool) {
+	s, ok := schedulers[name]
+	return s, ok
+}
+
+// Default returns the scheduler used when neither the learner nor the
+// group chose one
+func Default() Scheduler {
+	return schedulers[defaultName]
+}
+
+// All returns every registered scheduler
+func All() []Scheduler {
+	all := make([]Scheduler, 0, len(schedulers))
+	for _, name := range []string{models.SchedulerSM2, models.SchedulerLeitner} {
+		all = append(all, schedulers[name])
+	}
+	return all
+}
+
+// Quality grades a review from 0 (blackout) to 5 (perfect recall) for the
+// schedulers. Confidence raises or lowers a correct answer and any hint
+// caps it at 3; a confidently wrong answer scores lowest.
+func Quality(correct bool, confidence *int, hintsUsed int) int {
+	if !correct {
+		if confidence != nil && *confidence >= 4 {
+			return 0
+		}
+		return 1
+	}
+
+	quality := 4
+	if confidence != nil {
+		switch {
+		case *confidence >= 4:
+			quality = 5
+		case *confidence <= 2:
+			quality = 3
+		}
+	}
+	if hintsUsed > 0 && quality > 3 {
+		quality = 3
+	}
+	return quality
+}
+
+// days converts a duration to fractional days
+func days(d time.Duration) float64 {
+	return d.Hours() / 24
+}
+
+// afterDays returns now plus a fractional number of days
+func afterDays(now time.Time, n float64) time.Time {
+	return now.Add(time.Duration(n * 24 * float64(time.Hour)))
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestSM2Next(t *testing.T) {
+	tests := []struct {
+		name         string
+		state        models.WordSchedule
+		quality      int
+		repetitions  int
+		intervalDays float64
+		ease         float64
+	}{
+		{"first review", models.WordSchedule{}, 4, 1, 1, 2.5},
+		{"second review", models.WordSchedule{Repetitions: 1, IntervalDays: 1, EaseFactor: 2.5}, 5, 2, 6, 2.6},
+		{"interval grows by ease", models.WordSchedule{Repetitions: 2, IntervalDays: 6, EaseFactor: 2.6}, 4, 3, 16, 2.6},
+		{"hard answer lowers ease", models.WordSchedule{Repetitions: 2, IntervalDays: 6, EaseFactor: 2.5}, 3, 3, 15, 2.36},
+		{"lapse restarts", models.WordSchedule{Repetitions: 3, IntervalDays: 16, EaseFactor: 2.0}, 1, 0, 1, 1.46},
+		{"ease floor", models.WordSchedule{Repetitions: 3, IntervalDays: 16, EaseFactor: 1.3}, 0, 0, 1, 1.3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := SM2{}.Next(tt.state, tt.quality, now)
+			if next.Scheduler != models.SchedulerSM2 || next.Repetitions != tt.repetitions || next.IntervalDays != tt.intervalDays {
+				t.Errorf("Next = %s, %d repetitions, %v days; want sm2, %d, %v",
+					next.Scheduler, next.Repetitions, next.IntervalDays, tt.repetitions, tt.intervalDays)
+			}
+			if math.Abs(next.EaseFactor-tt.ease) > 1e-9 {
+				t.Errorf("ease factor = %v, want %v", next.EaseFactor, tt.ease)
+			}
+			if want := now.Add(time.Duration(tt.intervalDays) * 24 * time.Hour); !next.DueAt.Equal(want) || !next.LastReviewedAt.Equal(now) {
+				t.Errorf("due %v, reviewed %v; want %v, %v", next.DueAt, next.LastReviewedAt, want, now)
+			}
+		})
+	}
+}
+
+func TestLeitnerNext(t *testing.T) {
+	day := 24 * time.Hour
+	intervals := []time.Duration{day, 3 * day, 7 * day}
+	tests := []struct {
+		name          string
+		demoteToFirst bool
+		state         models.WordSchedule
+		quality       int
+		box           int
+		repetitions   int
+	}{
+		{"new word promoted from the first box", false, models.WordSchedule{}, 4, 2, 1},
+		{"promotion", false, models.WordSchedule{Box: 1, Repetitions: 1}, 3, 2, 2},
+		{"promotion clamped at the last box", false, models.WordSchedule{Box: 3, Repetitions: 4}, 5, 3, 5},
+		{"demotion one box", false, models.WordSchedule{Box: 3, Repetitions: 4}, 2, 2, 0},
+		{"demotion to the first box", true, models.WordSchedule{Box: 3, Repetitions: 4}, 2, 1, 0},
+		{"demotion clamped at the first box", false, models.WordSchedule{Box: 1, Repetitions: 1}, 0, 1, 0},
+		{"box beyond a shrunk configuration", false, models.WordSchedule{Box: 5}, 4, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := Leitner{Intervals: intervals, DemoteToFirst: tt.demoteToFirst}.Next(tt.state, tt.quality, now)
+			if next.Scheduler != models.SchedulerLeitner || next.Box != tt.box || next.Repetitions != tt.repetitions {
+				t.Errorf("Next = %s, box %d, %d repetitions; want leitner, %d, %d",
+					next.Scheduler, next.Box, next.Repetitions, tt.box, tt.repetitions)
+			}
+			interval := intervals[tt.box-1]
+			if next.IntervalDays != days(interval) || !next.DueAt.Equal(now.Add(interval)) || !next.LastReviewedAt.Equal(now) {
+				t.Errorf("interval %v days due %v, want %v due %v", next.IntervalDays, next.DueAt, days(interval), now.Add(interval))
+			}
+		})
+	}
+}
+
+func TestQuality(t *testing.T) {
+	confidence := func(n int) *int { return &n }
+	tests := []struct {
+		name       string
+		correct    bool
+		confidence *int
+		hintsUsed  int
+		want       int
+	}{
+		{"correct", true, nil, 0, 4},
+		{"correct and confident", true, confidence(5), 0, 5},
+		{"correct but unsure", true, confidence(2), 0, 3},
+		{"correct with a hint", true, nil, 1, 3},
+		{"confident with a hint", true, confidence(4), 2, 3},
+		{"unsure with a hint", true, confidence(1), 1, 3},
+		{"wrong", false, nil, 0, 1},
+		{"wrong but unsure", false, confidence(3), 0, 1},
+		{"confidently wrong", false, confidence(4), 0, 0},
+	}
+	for _, tt := range tests {
+		if got := Quality(tt.correct, tt.confidence, tt.hintsUsed); got != tt.want {
+			t.Errorf("%s: Quality = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	defaults := DefaultConfig()
+	tests := []struct {
+		name      string
+		env       map[string]string
+		scheduler string
+		intervals []time.Duration
+		toFirst   bool
+	}{
+		{"unset", nil, models.SchedulerSM2, defaults.LeitnerIntervals, true},
+		{"leitner", map[string]string{"SCHEDULER": "leitner"}, models.SchedulerLeitner, defaults.LeitnerIntervals, true},
+		{"unknown scheduler", map[string]string{"SCHEDULER": "fsrs"}, models.SchedulerSM2, defaults.LeitnerIntervals, true},
+		{"intervals", map[string]string{"LEITNER_INTERVALS": "12h, 48h,96h"}, models.SchedulerSM2,
+			[]time.Duration{12 * time.Hour, 48 * time.Hour, 96 * time.Hour}, true},
+		{"unparsable interval", map[string]string{"LEITNER_INTERVALS": "12h,2d"}, models.SchedulerSM2, defaults.LeitnerIntervals, true},
+		{"non-positive interval", map[string]string{"LEITNER_INTERVALS": "12h,0s"}, models.SchedulerSM2, defaults.LeitnerIntervals, true},
+		{"demote to previous", map[string]string{"LEITNER_DEMOTION": "previous"}, models.SchedulerSM2, defaults.LeitnerIntervals, false},
+		{"demote to first", map[string]string{"LEITNER_DEMOTION": "first"}, models.SchedulerSM2, defaults.LeitnerIntervals, true},
+		{"unknown demotion", map[string]string{"LEITNER_DEMOTION": "none"}, models.SchedulerSM2, defaults.LeitnerIntervals, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"SCHEDULER", "LEITNER_INTERVALS", "LEITNER_DEMOTION"} {
+				t.Setenv(key, tt.env[key])
+			}
+			cfg := ConfigFromEnv()
+			if cfg.Default != tt.scheduler || !reflect.DeepEqual(cfg.LeitnerIntervals, tt.intervals) || cfg.LeitnerDemoteToFirst != tt.toFirst {
+				t.Errorf("ConfigFromEnv = %+v, want %s, %v, demote to first %v", cfg, tt.scheduler, tt.intervals, tt.toFirst)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+const (
+	initialEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+// SM2 is the SuperMemo 2 algorithm: intervals grow by a per-word ease
+// factor that falls when answers are hard and rises when they are easy
+type SM2 struct{}
+
+// Name implements Scheduler
+func (SM2) Name() string { return models.SchedulerSM2 }
+
+// Next implements Scheduler
+func (SM2) Next(state models.WordSchedule, quality int, now time.Time) models.WordSchedule {
+	ease := state.EaseFactor
+	if ease == 0 {
+		ease = initialEaseFactor
+	}
+
+	next := state
+	next.Scheduler = models.SchedulerSM2
+	if quality < 3 {
+		next.Repetitions = 0
+		next.IntervalDays = 1
+	} else {
+		next.Repetitions++
+		switch next.Repetitions {
+		case 1:
+			next.IntervalDays = 1
+		case 2:
+			next.IntervalDays = 6
+		default:
+			next.IntervalDays = math.Round(state.IntervalDays * ease)
+		}
+	}
+
+	q := float64(5 - quality)
+	next.EaseFactor = math.Max(minEaseFactor, ease+0.1-q*(0.08+q*0.02))
+	next.DueAt = afterDays(now, next.IntervalDays)
+	next.LastReviewedAt = now
+	return next
+}
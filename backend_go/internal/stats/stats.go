@@ -0,0 +1,244 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+)
+
+// PriorWeight is how many pseudo-attempts at the overall error rate are
+// added to each word, so a word missed once in one attempt does not
+// outrank a word missed forty times in fifty
+const PriorWeight = 5.0
+
+// SmoothedErrorRate shrinks a word's error rate towards the prior rate,
+// trusting the word's own history more as its attempts grow
+func SmoothedErrorRate(errors, attempts int, prior float64) float64 {
+	return (float64(errors) + PriorWeight*prior) / (float64(attempts) + PriorWeight)
+}
+
+// Streaks returns the current run of correct answers and the longest such
+// run, given outcomes ordered newest first
+func Streaks(outcomes []bool) (current, longest int) {
+	run := 0
+	counting := true
+	for _, correct := range outcomes {
+		if !correct {
+			counting = false
+			run = 0
+			continue
+		}
+		run++
+		if counting {
+			current = run
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return current, longest
+}
+
+// Mastery grades how well a word is known from its attempts, accuracy and
+// current streak of correct answers
+func Mastery(attempts int, accuracy float64, streak int) string {
+	switch {
+	case attempts == 0:
+		return models.MasteryNew
+	case streak >= 5 && accuracy >= 0.8:
+		return models.MasteryMastered
+	case streak >= 2 && accuracy >= 0.6:
+		return models.MasteryFamiliar
+	default:
+		return models.MasteryLearning
+	}
+}
+
+// ForWord computes a word's statistics from its reviews, newest first
+func ForWord(wordID int64, reviews []models.WordReviewItem) models.WordStats {
+	stats := models.WordStats{WordID: wordID, Attempts: len(reviews)}
+
+	outcomes := make([]bool, len(reviews))
+	for i, review := range reviews {
+		outcomes[i] = review.IsCorrect
+		if review.IsCorrect {
+			stats.CorrectCount++
+		}
+	}
+	if len(reviews) > 0 {
+		stats.LastSeen = &reviews[0].CreatedAt
+		stats.Accuracy = float64(stats.CorrectCount) / float64(stats.Attempts)
+	}
+
+	stats.CurrentStreak, stats.LongestStreak = Streaks(outcomes)
+	stats.Mastery = Mastery(stats.Attempts, stats.Accuracy, stats.CurrentStreak)
+	return stats
+}
+
+// RankDifficult smooths each word's error rate towards the overall rate of
+// all given words and returns up to limit words with at least minAttempts
+// attempts, hardest first
+func RankDifficult(words []models.DifficultWord, minAttempts, limit int) []models.DifficultWord {
+	var errors, attempts int
+	for _, word := range words {
+		errors += word.Errors
+		attempts += word.Attempts
+	}
+	prior := 0.0
+	if attempts > 0 {
+		prior = float64(errors) / float64(attempts)
+	}
+
+	ranked := make([]models.DifficultWord, 0, len(words))
+	for _, word := range words {
+		if word.Attempts == 0 || word.Attempts < minAttempts {
+			continue
+		}
+		word.ErrorRate = float64(word.Errors) / float64(word.Attempts)
+		word.SmoothedErrorRate = SmoothedErrorRate(word.Errors, word.Attempts, prior)
+		ranked = append(ranked, word)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].SmoothedErrorRate != ranked[j].SmoothedErrorRate {
+			return ranked[i].SmoothedErrorRate > ranked[j].SmoothedErrorRate
+		}
+		return ranked[i].Attempts > ranked[j].Attempts
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// ForGroup computes a group's progress from the reviews of its words,
+// newest first, with a daily trend covering the last days days up to now
+func ForGroup(groupID int64, wordCount int, reviews []models.WordReviewItem, days int, now time.Time) models.GroupStats {
+	stats := models.GroupStats{GroupID: groupID, WordCount: wordCount}
+
+	byWord := map[int64][]models.WordReviewItem{}
+	var order []int64
+	var correct int
+	for _, review := range reviews {
+		if _, ok := byWord[review.WordID]; !ok {
+			order = append(order, review.WordID)
+		}
+		byWord[review.WordID] = append(byWord[review.WordID], review)
+		if review.IsCorrect {
+			correct++
+		}
+	}
+
+	stats.WordsStudied = len(order)
+	for _, wordID := range order {
+		if ForWord(wordID, byWord[wordID]).Mastery == models.MasteryMastered {
+			stats.MasteredCount++
+		}
+	}
+	if len(reviews) > 0 {
+		stats.Accuracy = float64(correct) / float64(len(reviews))
+		stats.LastStudied = &reviews[0].CreatedAt
+	}
+
+	stats.Trend = Trend(reviews, days, now)
+	return stats
+}
+
+// Trend buckets reviews by UTC day over the last days days up to now,
+// including days without reviews
+func Trend(reviews []models.WordReviewItem, days int, now time.Time) []models.DailyProgress {
+	today := now.UTC().Truncate(24 * time.Hour)
+	start := today.AddDate(0, 0, -(days - 1))
+
+	trend := make([]models.DailyProgress, days)
+	for i := range trend {
+		trend[i].Date = start.AddDate(0, 0, i).Format("2006-01-02")
+	}
+
+	for _, review := range reviews {
+		day := int(review.CreatedAt.UTC().Sub(start).Hours() / 24)
+		if review.CreatedAt.Before(start) || day >= days {
+			continue
+		}
+		trend[day].Reviews++
+		if review.IsCorrect {
+			trend[day].CorrectCount++
+		}
+	}
+
+	for i := range trend {
+		if trend[i].Reviews > 0 {
+			trend[i].Accuracy = float64(trend[i].CorrectCount) / float64(trend[i].Reviews)
+		}
+	}
+	return trend
+}
+
+// Mastered reports whether a word's review facts reach the mastered level
+func Mastered(progress models.WordProgress) bool {
+	accuracy := 0.0
+	if progress.Attempts > 0 {
+		accuracy = float64(progress.CorrectCount) / float64(progress.Attempts)
+	}
+	return Mastery(progress.Attempts, accuracy, progress.CurrentStreak) == models.MasteryMastered
+}
+
+// Progress totals studied and mastered words. When groups are given, the
+// same totals are broken down by group using memberships.
+func Progress(words []models.WordProgress, groups []models.Group, memberships []models.WordGroup) models.StudyProgress {
+	var progress models.StudyProgress
+	byWord := make(map[int64]models.WordProgress, len(words))
+	for _, word := range words {
+		byWord[word.WordID] = word
+		progress.TotalAvailableWords++
+		if word.Attempts > 0 {
+			progress.TotalWordsStudied++
+		}
+		if Mastered(word) {
+			progress.MasteredWords++
+		}
+	}
+	progress.MasteryPercentage = percentage(progress.MasteredWords, progress.TotalAvailableWords)
+
+	if groups == nil {
+		return progress
+	}
+
+	index := make(map[int64]int, len(groups))
+	progress.Groups = make([]models.GroupProgress, len(groups))
+	for i, group := range groups {
+		index[group.ID] = i
+		progress.Groups[i] = models.GroupProgress{GroupID: group.ID, GroupName: group.Name}
+	}
+	for _, membership := range memberships {
+		i, ok := index[membership.GroupID]
+		word, known := byWord[membership.WordID]
+		if !ok || !known {
+			continue
+		}
+		progress.Groups[i].TotalAvailableWords++
+		if word.Attempts > 0 {
+			progress.Groups[i].TotalWordsStudied++
+		}
+		if Mastered(word) {
+			progress.Groups[i].MasteredWords++
+		}
+	}
+	for i := range progress.Groups {
+		g := &progress.Groups[i]
+		g.MasteryPercentage = percentage(g.MasteredWords, g.TotalAvailableWords)
+	}
+
+	return progress
+}
+
+// percentage returns part as a percentage of total rounded to two decimals
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckURL(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE", "")
+	for raw, ok := range map[string]bool{
+		"https://93.184.215.14/hook":  true,
+		"ftp://93.184.215.14/hook":    false,
+		"/hook":                       false,
+		"http://127.0.0.1:8080/hook":  false,
+		"http://localhost/hook":       false,
+		"http://10.1.2.3/hook":        false,
+		"http://192.168.0.10/hook":    false,
+		"http://169.254.169.254/meta": false,
+		"http://100.64.0.1/hook":      false,
+		"http://0.0.0.0/hook":         false,
+		"http://[::1]/hook":           false,
+		"http://[fd00::1]/hook":       false,
+	} {
+		if err := CheckURL(context.Background(), raw); (err == nil) != ok {
+			t.Errorf("CheckURL(%q) = %v, want ok %v", raw, err, ok)
+		}
+	}
+
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE", "true")
+	if err := CheckURL(context.Background(), "http://127.0.0.1:8080/hook"); err != nil {
+		t.Errorf("CheckURL with WEBHOOK_ALLOW_PRIVATE = %v", err)
+	}
+}
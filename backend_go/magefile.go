@@ -44,6 +44,17 @@ func Test() error {
 	return sh.Run("go", "test", "./...")
 }
 
+// OpenAPI checks that the OpenAPI document covers every registered route
+// and writes it to openapi.json
+func OpenAPI() error {
+	fmt.Println("Checking OpenAPI document...")
+	doc, err := sh.Output("go", "run", ".", "openapi")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile("openapi.json", []byte(doc+"\n"), 0644)
+}
+
 // Lint runs golangci-lint
 func Lint() error {
 	fmt.Println("Running linter...")
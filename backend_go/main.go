@@ -1,44 +1,111 @@
 package main
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/db"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/events"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/graph"
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/handlers"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/jobs"
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/loader"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/logging"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/lti"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/metrics"
 	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/repositories"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/rooms"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/scheduler"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/tracing"
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to drain
+const shutdownTimeout = 15 * time.Second
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		os.Exit(writeOpenAPI(os.Stdout, os.Stderr))
+	}
+
+	// Initialize structured logging
+	logConfig := logging.ConfigFromEnv()
+	logging.Init(logConfig)
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		fatal("Error initializing tracing", err)
+	}
+
+	// Initialize spaced-repetition schedulers
+	scheduler.Init(scheduler.ConfigFromEnv())
+
 	// Initialize database
 	database, err := db.InitDB("./database.db")
 	if err != nil {
-		log.Fatalf("Error initializing database: %v", err)
+		fatal("Error initializing database", err)
 	}
 
+	defer database.Close()
+
+	// Register Prometheus collectors
+	metrics.Register(database)
+
 	// Create repository
 	repo := repositories.NewSQLiteRepository(database)
 
+	// Replay existing history into the leaderboards
+	if err := repo.BackfillLeaderboards(context.Background()); err != nil {
+		fatal("Error backfilling leaderboards", err)
+	}
+
 	// Initialize handlers
 	handler := handlers.NewHandler(repo)
+	healthHandler := handlers.NewHealthHandler(database)
+	hub := rooms.NewHub(repo)
+	roomHandler := handlers.NewRoomHandler(repo, hub, handlers.AllowedOriginsFromEnv())
+
+	// Load the LTI tool's signing key
+	kid, ltiKey, err := lti.LoadKey(context.Background(), repo)
+	if err != nil {
+		fatal("Error loading LTI key", err)
+	}
+	ltiTool := lti.NewTool(lti.ConfigFromEnv(), kid, ltiKey)
+	ltiHandler := handlers.NewLTIHandler(repo, ltiTool)
 
 	// Initialize JSON loader
 	jsonLoader := loader.NewJSONLoader(repo)
 
 	// Load initial data
 	if err := jsonLoader.LoadInitialData(); err != nil {
-		log.Printf("Error loading initial data: %v", err)
+		slog.Error("Error loading initial data", "error", err)
 	}
 
 	// Initialize router
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(logging.RequestID())
+	router.Use(tracing.Middleware())
+	router.Use(metrics.Middleware())
+	router.Use(logging.Middleware(logConfig))
 
 	// Enable CORS
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, X-User-ID, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Idempotent-Replayed")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -46,42 +113,108 @@ func main() {
 		c.Next()
 	})
 
-	// API routes
-	api := router.Group("/api")
-	{
-		// Dashboard endpoints
-		api.GET("/dashboard/last-session", handler.GetLastStudySession)
-		api.GET("/dashboard/study-progress", handler.GetStudyProgress)
-		api.GET("/dashboard/quick-stats", handler.GetQuickStats)
-
-		// Word endpoints
-		api.GET("/words", handler.GetWords)
-		api.POST("/words", handler.CreateWord)
-		api.GET("/words/:id", handler.GetWordByID)
-		api.PUT("/words/:id", handler.UpdateWord)
-		api.DELETE("/words/:id", handler.DeleteWord)
-
-		// Group endpoints
-		api.GET("/groups", handler.GetGroups)
-		api.POST("/groups", handler.CreateGroup)
-		api.GET("/groups/:id", handler.GetGroupByID)
-		api.PUT("/groups/:id", handler.UpdateGroup)
-		api.DELETE("/groups/:id", handler.DeleteGroup)
-
-		// Study activity endpoints
-		api.GET("/study-activities", handler.GetStudyActivities)
-		api.POST("/study-activities", handler.CreateStudyActivity)
-		api.GET("/study-activities/:id", handler.GetStudyActivity)
-		api.GET("/study-activities/:id/study-sessions", handler.GetStudyActivitySessions)
-		api.POST("/study-activities/:id/study-sessions", handler.CreateStudySession)
-
-		// Word review endpoints
-		api.GET("/reviews/session/:session_id", handler.GetWordReviewItems)
-		api.POST("/reviews", handler.CreateWordReviewItem)
+	registerRoutes(router, routeHandlers{
+		api:     handler,
+		health:  healthHandler,
+		rooms:   roomHandler,
+		lti:     ltiHandler,
+		graphql: handlers.NewGraphQLHandler(graph.New(repo)),
+	})
+
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: router,
 	}
 
+	// Stop accepting requests on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Start background jobs
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		jobs.NewTrashPurger(repo).Run(ctx)
+	}()
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		jobs.NewWebhookDispatcher(repo).Run(ctx)
+	}()
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		jobs.NewLTIScorePoster(repo, ltiTool).Run(ctx)
+	}()
+
 	// Start server
-	if err := router.Run(":8080"); err != nil {
-		log.Fatalf("Error starting server: %v", err)
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			fatal("Error starting server", err)
+		}
+	case <-ctx.Done():
 	}
+	stop()
+
+	// Let in-flight requests finish before closing the database; event
+	// streams and room sockets would otherwise stay open until the timeout
+	slog.Info("Shutting down server")
+	events.Close()
+	hub.Close()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error shutting down server", "error", err)
+	}
+	background.Wait()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Error flushing traces", "error", err)
+	}
+	slog.Info("Server stopped")
+}
+
+// writeOpenAPI prints the OpenAPI document without opening the database,
+// failing when it disagrees with the routes gin registered
+func writeOpenAPI(stdout, stderr io.Writer) int {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	spec := registerRoutes(router, routeHandlers{
+		api:     handlers.NewHandler(nil),
+		health:  handlers.NewHealthHandler(nil),
+		rooms:   handlers.NewRoomHandler(nil, nil, nil),
+		lti:     handlers.NewLTIHandler(nil, nil),
+		graphql: handlers.NewGraphQLHandler(graph.New(nil)),
+	})
+
+	if problems := spec.Check(router.Routes()); len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintln(stderr, "openapi:", problem)
+		}
+		return 1
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(spec.Document()); err != nil {
+		fmt.Fprintln(stderr, "openapi:", err)
+		return 1
+	}
+	return 0
+}
+
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,518 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/handlers"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/logging"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/lti"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/metrics"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/models"
+	"github.com/ElDelak/free-genia-bootcamp-2025/backend_go/internal/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// apiVersion is the version reported in the OpenAPI document
+const apiVersion = "1.0.0"
+
+// routeHandlers holds the handlers the routes dispatch to
+type routeHandlers struct {
+	api     *handlers.Handler
+	health  *handlers.HealthHandler
+	rooms   *handlers.RoomHandler
+	lti     *handlers.LTIHandler
+	graphql *handlers.GraphQLHandler
+}
+
+// registerRoutes registers every route on router and returns the OpenAPI
+// document describing them. Routes are only ever added through the spec's
+// routers, so the document always matches what gin serves.
+func registerRoutes(router *gin.Engine, h routeHandlers) *openapi.Spec {
+	spec := openapi.New(openapi.Info{
+		Title:       "Lang Portal API",
+		Description: "Backend for the Arabic language learning portal",
+		Version:     apiVersion,
+	}, handlers.ErrorResponse{})
+	spec.AddSecurityScheme("learner", openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        logging.UserIDHeader,
+		Description: "Identifies the learner; reviews, goals and profiles are kept per learner",
+	})
+
+	root := spec.Router(router)
+
+	// Health probes
+	ops := root.Tag("Operations")
+	ops.GET("/healthz", h.health.Healthz, openapi.Op{
+		Summary:  "Liveness probe",
+		Response: models.HealthStatus{},
+	})
+	ops.GET("/readyz", h.health.Readyz, openapi.Op{
+		Summary:  "Readiness probe covering the database, schema and seed data",
+		Response: models.HealthStatus{},
+		Errors:   []int{http.StatusServiceUnavailable},
+	})
+	ops.GET("/metrics", metrics.Handler(), openapi.Op{
+		ID:          "GetMetrics",
+		Summary:     "Prometheus metrics",
+		Response:    "",
+		ContentType: "text/plain",
+	})
+
+	// LTI 1.3 tool endpoints, called by the LMS and the browser
+	ltiTool := root.Tag("LTI")
+	loginOp := openapi.Op{
+		Summary:     "LTI third-party login initiation",
+		Description: "Takes iss, login_hint, client_id and lti_message_hint as query or form parameters and redirects to the platform's authorization endpoint.",
+		Status:      http.StatusFound,
+		Response:    "",
+		ContentType: "text/html",
+	}
+	ltiTool.GET("/lti/login", h.lti.Login, loginOp)
+	ltiTool.POST("/lti/login", h.lti.Login, loginOp)
+	ltiTool.POST("/lti/launch", h.lti.Launch, openapi.Op{
+		Summary:     "LTI launch",
+		Description: "Takes the platform's id_token and state as form parameters. Resource link launches start a study session and redirect to the app (or return JSON when no app URL is configured); deep linking launches return the group picker page.",
+		Response:    models.LTILaunchResult{},
+		Errors:      []int{http.StatusUnauthorized},
+	})
+	ltiTool.POST("/lti/deep-link", h.lti.DeepLink, openapi.Op{
+		Summary:     "Return the picked group to the platform",
+		Description: "Takes launch and group_id as form parameters and answers with a page that posts the signed deep linking response back to the platform.",
+		Response:    "",
+		ContentType: "text/html",
+		Errors:      []int{http.StatusNotFound},
+	})
+	ltiTool.GET("/lti/jwks", h.lti.JWKS, openapi.Op{
+		Summary:  "The tool's public signing keys",
+		Response: lti.JWKS{},
+	})
+
+	// GraphQL API
+	gql := router.Group("/graphql")
+	gql.Use(handlers.AuditContext())
+	graphqlRoutes := spec.Router(gql).Tag("GraphQL").Security("learner", true)
+	graphqlRoutes.POST("", h.graphql.Query, openapi.Op{
+		ID:          "PostGraphQL",
+		Summary:     "Run a GraphQL query",
+		Description: "Errors raised while executing the query are reported in the response's errors, with a 200 status. Queries whose estimated complexity or depth is over the limits are refused before they run.",
+		Body:        handlers.GraphQLRequest{},
+		Response:    handlers.GraphQLResponse{},
+	})
+	graphqlRoutes.GET("", h.graphql.Query, openapi.Op{
+		ID:       "GetGraphQL",
+		Summary:  "Run a GraphQL query sent as query parameters",
+		Query:    handlers.GraphQLRequest{},
+		Params:   []openapi.Parameter{openapi.QueryParam("variables", "string", "The variables as a JSON object")},
+		Response: handlers.GraphQLResponse{},
+	})
+	graphqlRoutes.GET("/schema", h.graphql.Schema, openapi.Op{
+		ID:          "GetGraphQLSchema",
+		Summary:     "The GraphQL schema in SDL",
+		Response:    "",
+		ContentType: "text/plain",
+	})
+
+	// API routes
+	api := router.Group("/api")
+	api.Use(handlers.AuditContext())
+	docs := spec.Router(api).Security("learner", true)
+	{
+		// Documentation endpoints
+		docs.Tag("Operations").GET("/openapi.json", spec.Handler(), openapi.Op{
+			ID:       "GetOpenAPI",
+			Summary:  "This OpenAPI document",
+			Response: map[string]any{},
+		})
+		docs.Hidden(http.MethodGet, "/docs/*filepath", openapi.UI("/api/openapi.json"))
+
+		// Dashboard endpoints
+		dashboard := docs.Tag("Dashboard")
+		dashboard.GET("/dashboard/last-session", h.api.GetLastStudySession, openapi.Op{
+			Summary:  "The learner's most recent study session",
+			Response: models.StudySession{},
+			Errors:   []int{http.StatusNotFound},
+		})
+		dashboard.GET("/dashboard/study-progress", h.api.GetStudyProgress, openapi.Op{
+			Summary:  "Words the learner studied and mastered, optionally per group",
+			Params:   []openapi.Parameter{openapi.QueryParam("by_group", "boolean", "Break progress down by group")},
+			Response: models.StudyProgress{},
+		})
+		dashboard.GET("/dashboard/quick-stats", h.api.GetQuickStats, openapi.Op{
+			Summary:  "Headline numbers for the learner's dashboard",
+			Response: models.DashboardStats{},
+		})
+
+		// Word endpoints
+		words := docs.Tag("Words")
+		words.GET("/words", h.api.GetWords, openapi.Op{
+			Summary:  "List words",
+			Query:    handlers.WordsQueryParams{},
+			Response: models.WordsPage{},
+		})
+		words.POST("/words", h.api.CreateWord, openapi.Op{
+			Summary:  "Create a word",
+			Body:     models.Word{},
+			Response: models.Word{},
+			Status:   http.StatusCreated,
+		})
+		words.GET("/words/difficult", h.api.GetDifficultWords, openapi.Op{
+			Summary:  "Words ranked by smoothed error rate",
+			Query:    handlers.DifficultWordsQueryParams{},
+			Response: []models.DifficultWord{},
+		})
+		words.POST("/words/difficult/group", h.api.CreateTroubleGroup, openapi.Op{
+			Summary:  "Create a group from the difficult words",
+			Body:     handlers.CreateTroubleGroupRequest{},
+			Response: models.TroubleGroup{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusUnprocessableEntity},
+		})
+		words.GET("/words/:id", h.api.GetWordByID, openapi.Op{
+			Summary:  "Get a word",
+			Response: models.Word{},
+		})
+		words.PUT("/words/:id", h.api.UpdateWord, openapi.Op{
+			Summary:  "Update a word",
+			Body:     models.Word{},
+			Response: models.Word{},
+		})
+		words.DELETE("/words/:id", h.api.DeleteWord, openapi.Op{
+			Summary: "Move a word to the trash",
+		})
+		words.GET("/words/:id/stats", h.api.GetWordStats, openapi.Op{
+			Summary:  "Learning statistics for a word",
+			Response: models.WordStats{},
+		})
+		words.GET("/words/:id/reviews", h.api.GetWordReviews, openapi.Op{
+			Summary:  "Review history of a word",
+			Response: []models.WordReviewItem{},
+		})
+		words.GET("/words/:id/reviews/analytics", h.api.GetWordReviewAnalytics, openapi.Op{
+			Summary:  "Review analytics for a word",
+			Response: models.ReviewAnalytics{},
+		})
+		words.GET("/words/:id/history", h.api.GetWordHistory, openapi.Op{
+			Summary:  "Audit history of a word",
+			Response: []models.AuditEvent{},
+		})
+		words.POST("/words/:id/revert/:version", h.api.RevertWord, openapi.Op{
+			Summary:  "Revert a word to an earlier version",
+			Response: models.Word{},
+			Errors:   []int{http.StatusConflict},
+		})
+		words.POST("/words/:id/restore", h.api.RestoreWord, openapi.Op{
+			Summary:  "Restore a word from the trash",
+			Response: models.Word{},
+		})
+
+		// Group endpoints
+		groups := docs.Tag("Groups")
+		groups.GET("/groups", h.api.GetGroups, openapi.Op{
+			Summary:  "List groups",
+			Response: []models.Group{},
+		})
+		groups.POST("/groups", h.api.CreateGroup, openapi.Op{
+			Summary:  "Create a group",
+			Body:     models.Group{},
+			Response: models.Group{},
+			Status:   http.StatusCreated,
+		})
+		groups.GET("/groups/:id", h.api.GetGroupByID, openapi.Op{
+			Summary:  "Get a group",
+			Response: models.Group{},
+		})
+		groups.PUT("/groups/:id", h.api.UpdateGroup, openapi.Op{
+			Summary:  "Update a group",
+			Body:     models.Group{},
+			Response: models.Group{},
+		})
+		groups.DELETE("/groups/:id", h.api.DeleteGroup, openapi.Op{
+			Summary: "Move a group to the trash",
+		})
+		groups.POST("/groups/:id/words", h.api.AddWordToGroup, openapi.Op{
+			Summary: "Add a word to a group",
+			Body:    handlers.AddWordToGroupRequest{},
+		})
+		groups.DELETE("/groups/:id/words/:word_id", h.api.RemoveWordFromGroup, openapi.Op{
+			Summary: "Remove a word from a group",
+		})
+		groups.GET("/groups/:id/stats", h.api.GetGroupStats, openapi.Op{
+			Summary:  "Learning progress for a group",
+			Params:   []openapi.Parameter{openapi.QueryParam("days", "integer", "Length of the daily trend, 1 to 365 (default 30)")},
+			Response: models.GroupStats{},
+		})
+		groups.GET("/groups/:id/study-sessions", h.api.GetGroupStudySessions, openapi.Op{
+			Summary:  "Study sessions of a group",
+			Response: []models.StudySession{},
+		})
+		groups.GET("/groups/:id/scheduler", h.api.GetGroupScheduler, openapi.Op{
+			Summary:  "Scheduler in effect for a group",
+			Response: models.SchedulerChoice{},
+		})
+		groups.PUT("/groups/:id/scheduler", h.api.SetGroupScheduler, openapi.Op{
+			Summary:  "Choose the scheduler for a group",
+			Body:     models.SchedulerSetting{},
+			Response: models.SchedulerChoice{},
+		})
+		groups.POST("/groups/:id/restore", h.api.RestoreGroup, openapi.Op{
+			Summary:  "Restore a group from the trash",
+			Response: models.Group{},
+		})
+
+		// Trash endpoints
+		docs.Tag("Trash").GET("/trash", h.api.GetTrash, openapi.Op{
+			Summary:  "Soft-deleted words and groups",
+			Response: models.Trash{},
+		})
+
+		// Offline sync endpoints
+		sync := docs.Tag("Sync")
+		sync.GET("/sync", h.api.PullChanges, openapi.Op{
+			Summary:  "Pull changes since a sync token",
+			Params:   []openapi.Parameter{openapi.QueryParam("token", "string", "Token from the previous sync")},
+			Response: models.SyncResponse{},
+		})
+		sync.POST("/sync", h.api.Sync, openapi.Op{
+			Summary:  "Push offline changes and pull the server's",
+			Body:     models.SyncRequest{},
+			Response: models.SyncResponse{},
+		})
+
+		// Study activity endpoints
+		activities := docs.Tag("Study Activities")
+		activities.GET("/study-activities", h.api.GetStudyActivities, openapi.Op{
+			Summary:  "List study activities",
+			Response: []models.StudyActivity{},
+		})
+		activities.POST("/study-activities", h.api.CreateStudyActivity, openapi.Op{
+			Summary:  "Create a study activity for a group",
+			Body:     handlers.CreateStudyActivityRequest{},
+			Response: models.StudyActivity{},
+			Status:   http.StatusCreated,
+		})
+		activities.GET("/study-activities/:id", h.api.GetStudyActivity, openapi.Op{
+			Summary:  "Get a study activity",
+			Response: models.StudyActivity{},
+		})
+		activities.GET("/study-activities/:id/study-sessions", h.api.GetStudyActivitySessions, openapi.Op{
+			Summary:  "Study sessions of an activity",
+			Response: []models.StudySession{},
+		})
+		activities.POST("/study-activities/:id/study-sessions", h.api.CreateStudySession, openapi.Op{
+			Summary:  "Start a study session",
+			Body:     models.StudySession{},
+			Response: models.StudySession{},
+			Status:   http.StatusCreated,
+		})
+
+		// Study session endpoints
+		sessions := docs.Tag("Study Sessions")
+		sessions.POST("/study-sessions/:id/finish", h.api.FinishStudySession, openapi.Op{
+			Summary:  "Finish a study session",
+			Response: models.StudySession{},
+			Errors:   []int{http.StatusConflict},
+		})
+		sessions.Actions(http.MethodPost, "/study-sessions/:id", h.api.StudySessionAction, map[string]openapi.Op{
+			"reviews:batch": {
+				ID:          "CreateReviewBatch",
+				Summary:     "Record a batch of reviews",
+				Description: "Reports the outcome of each item. Batches are idempotent per Idempotency-Key within the session, and items per client_id.",
+				Params:      []openapi.Parameter{openapi.HeaderParam(handlers.IdempotencyKeyHeader, "Replays the stored result when a batch is retried")},
+				Body:        models.ReviewBatch{},
+				Response:    models.ReviewBatchResult{},
+				Errors:      []int{http.StatusConflict},
+			},
+		})
+		sessions.GET("/study-sessions/:id/events", h.api.StreamSessionEvents, openapi.Op{
+			Summary:     "Server-sent events for one study session",
+			Params:      []openapi.Parameter{openapi.HeaderParam("Last-Event-ID", "Resume after this event")},
+			Response:    "",
+			ContentType: "text/event-stream",
+		})
+		sessions.GET("/events", h.api.StreamEvents, openapi.Op{
+			Summary:     "Server-sent events for all study activity",
+			Params:      []openapi.Parameter{openapi.QueryParam("group_id", "integer", "Only events for this group"), openapi.HeaderParam("Last-Event-ID", "Resume after this event")},
+			Response:    "",
+			ContentType: "text/event-stream",
+		})
+
+		// Spaced-repetition endpoints
+		study := docs.Tag("Scheduling")
+		study.GET("/study/due", h.api.GetDueWords, openapi.Op{
+			Summary:  "Words due for review",
+			Query:    handlers.DueQueueQueryParams{},
+			Response: models.DueQueue{},
+		})
+		study.GET("/me/scheduler", h.api.GetMyScheduler, openapi.Op{
+			Summary:  "Scheduler in effect for the learner",
+			Response: models.SchedulerChoice{},
+		})
+		study.PUT("/me/scheduler", h.api.SetMyScheduler, openapi.Op{
+			Summary:  "Choose the learner's scheduler",
+			Body:     models.SchedulerSetting{},
+			Response: models.SchedulerChoice{},
+		})
+		study.GET("/study/forecast", h.api.GetForecast, openapi.Op{
+			Summary:  "Forecast of upcoming reviews and retention",
+			Query:    handlers.ForecastQueryParams{},
+			Response: models.Forecast{},
+		})
+
+		// Gamification endpoints
+		me := docs.Tag("Gamification")
+		me.GET("/me/achievements", h.api.GetMyAchievements, openapi.Op{
+			Summary:  "The learner's streaks and badges",
+			Response: models.Achievements{},
+		})
+		me.GET("/me/goals", h.api.GetMyGoals, openapi.Op{
+			Summary:  "Daily goals and recent progress",
+			Params:   []openapi.Parameter{openapi.QueryParam("days", "integer", "Days of history, 1 to 90 (default 7)")},
+			Response: models.Goals{},
+		})
+		me.PUT("/me/goals", h.api.SetMyGoals, openapi.Op{
+			Summary:  "Set daily goals",
+			Body:     models.GoalSettings{},
+			Response: models.Goals{},
+		})
+		me.GET("/me/profile", h.api.GetMyProfile, openapi.Op{
+			Summary:  "The learner's leaderboard profile",
+			Response: models.LearnerProfile{},
+		})
+		me.PUT("/me/profile", h.api.UpdateMyProfile, openapi.Op{
+			Summary:  "Update the learner's pseudonym and opt-out",
+			Body:     models.LearnerProfile{},
+			Response: models.LearnerProfile{},
+		})
+		me.GET("/leaderboard", h.api.GetLeaderboard, openapi.Op{
+			Summary:  "Leaderboard for a period and metric",
+			Query:    handlers.LeaderboardQueryParams{},
+			Response: models.Leaderboard{},
+		})
+
+		// Quiz endpoints
+		quizzes := docs.Tag("Quizzes")
+		quizzes.POST("/quizzes", h.api.CreateQuiz, openapi.Op{
+			Summary:  "Generate a multiple-choice quiz for a group",
+			Body:     handlers.CreateQuizRequest{},
+			Response: models.Quiz{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusNotFound, http.StatusUnprocessableEntity},
+		})
+		quizzes.POST("/quizzes/:id/answers", h.api.AnswerQuiz, openapi.Op{
+			Summary:  "Grade answers to a quiz",
+			Body:     handlers.AnswerQuizRequest{},
+			Response: models.QuizResults{},
+		})
+
+		// Webhook endpoints
+		webhooks := docs.Tag("Webhooks")
+		webhooks.GET("/webhooks", h.api.GetWebhooks, openapi.Op{
+			Summary:  "List webhooks",
+			Response: []models.Webhook{},
+		})
+		webhooks.POST("/webhooks", h.api.CreateWebhook, openapi.Op{
+			Summary:     "Register a webhook",
+			Description: "The signing secret is only returned here.",
+			Body:        handlers.WebhookRequest{},
+			Response:    models.WebhookCreated{},
+			Status:      http.StatusCreated,
+		})
+		webhooks.GET("/webhooks/:id", h.api.GetWebhook, openapi.Op{
+			Summary:  "Get a webhook",
+			Response: models.Webhook{},
+		})
+		webhooks.PUT("/webhooks/:id", h.api.UpdateWebhook, openapi.Op{
+			Summary:  "Update a webhook",
+			Body:     handlers.WebhookRequest{},
+			Response: models.Webhook{},
+		})
+		webhooks.DELETE("/webhooks/:id", h.api.DeleteWebhook, openapi.Op{
+			Summary: "Delete a webhook",
+		})
+		webhooks.GET("/webhooks/:id/deliveries", h.api.GetWebhookDeliveries, openapi.Op{
+			Summary:  "Delivery log of a webhook",
+			Query:    handlers.WebhookDeliveriesQueryParams{},
+			Response: []models.WebhookDelivery{},
+		})
+		webhooks.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", h.api.RedeliverWebhookDelivery, openapi.Op{
+			Summary:  "Queue a delivery to be sent again",
+			Response: models.WebhookDelivery{},
+			Status:   http.StatusAccepted,
+		})
+
+		// LTI platform registration endpoints
+		platforms := docs.Tag("LTI")
+		platforms.GET("/lti/tool", h.lti.GetToolConfig, openapi.Op{
+			Summary:  "URLs a platform needs to register the tool",
+			Response: handlers.LTIToolConfig{},
+		})
+		platforms.GET("/lti/platforms", h.lti.GetPlatforms, openapi.Op{
+			Summary:  "List registered LTI platforms",
+			Response: []models.LTIPlatform{},
+		})
+		platforms.POST("/lti/platforms", h.lti.CreatePlatform, openapi.Op{
+			Summary:  "Register an LTI platform",
+			Body:     models.LTIPlatform{},
+			Response: models.LTIPlatform{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusConflict},
+		})
+		platforms.DELETE("/lti/platforms/:id", h.lti.DeletePlatform, openapi.Op{
+			Summary: "Remove an LTI platform",
+		})
+
+		// Live quiz room endpoints
+		rooms := docs.Tag("Rooms")
+		rooms.POST("/rooms", h.rooms.CreateRoom, openapi.Op{
+			Summary:  "Open a live quiz room",
+			Body:     handlers.CreateRoomRequest{},
+			Response: models.RoomCreated{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusServiceUnavailable},
+		})
+		rooms.GET("/rooms/:code", h.rooms.GetRoom, openapi.Op{
+			Summary:  "Get a room's state",
+			Response: models.Room{},
+		})
+		rooms.GET("/rooms/:code/ws", h.rooms.JoinRoom, openapi.Op{
+			Summary:     "Join a room over a WebSocket",
+			Description: "Upgrades to a WebSocket. Players pass name and are identified by X-User-ID or learner_id; the host passes the host token instead. Browsers must connect from the server's origin or one listed in ALLOWED_ORIGINS.",
+			Params: []openapi.Parameter{
+				openapi.QueryParam("name", "string", "Player name, at most 40 characters"),
+				openapi.QueryParam("learner_id", "string", "Learner to record answers for, when X-User-ID cannot be set"),
+				openapi.QueryParam("token", "string", "Host token returned when the room was created"),
+			},
+			Status: http.StatusSwitchingProtocols,
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden},
+		})
+
+		// Word review endpoints
+		reviews := docs.Tag("Reviews")
+		reviews.GET("/reviews/session/:session_id", h.api.GetWordReviewItems, openapi.Op{
+			Summary:  "Reviews recorded in a study session",
+			Response: []models.WordReviewItem{},
+		})
+		reviews.GET("/reviews/session/:session_id/analytics", h.api.GetSessionReviewAnalytics, openapi.Op{
+			Summary:  "Review analytics for a study session",
+			Response: models.ReviewAnalytics{},
+		})
+		reviews.POST("/reviews", h.api.CreateWordReviewItem, openapi.Op{
+			Summary:  "Record a review",
+			Body:     models.WordReviewItem{},
+			Response: models.WordReviewItem{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusNotFound, http.StatusConflict},
+		})
+		reviews.POST("/reviews/grade", h.api.GradeReview, openapi.Op{
+			Summary:  "Grade a typed answer and record the review",
+			Body:     handlers.GradeReviewRequest{},
+			Response: handlers.GradeReviewResponse{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusNotFound},
+		})
+	}
+
+	return spec
+}